@@ -0,0 +1,20 @@
+package api
+
+import (
+	"battle_srv/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+Counts the login attempts of an auth channel, an attempt is "ok" only if the handler writes a response without setting "RET", e.g. "IntAuthTokenLogin" writes nothing when the player is not found.
+*/
+func CountLoginAttempts(channel string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		result := metrics.LOGIN_RESULT_FAILED
+		if 0 == c.GetInt(RET) && c.Writer.Written() {
+			result = metrics.LOGIN_RESULT_OK
+		}
+		metrics.LoginAttemptsTotal.WithLabelValues(channel, result).Inc()
+	}
+}
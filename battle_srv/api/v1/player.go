@@ -448,6 +448,45 @@ func (p *playerController) FetchProfile(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+type battleHistoryReq struct {
+	Page     int `form:"page"`     // Starting from 1, defaulted to 1
+	PageSize int `form:"pageSize"` // Defaulted to "models.DEFAULT_BATTLE_HISTORY_PAGE_SIZE"
+}
+
+/*
+The settled battles of the authenticated player from the latest, see "models.GetBattleHistoryByPlayerId".
+*/
+func (p *playerController) BattleHistory(c *gin.Context) {
+	playerId := c.GetInt(api.PLAYER_ID)
+	var req battleHistoryReq
+	err := c.ShouldBindWith(&req, binding.FormPost)
+	api.CErr(c, err)
+	if 0 == req.Page {
+		req.Page = 1
+	}
+	if 0 == req.PageSize {
+		req.PageSize = models.DEFAULT_BATTLE_HISTORY_PAGE_SIZE
+	}
+	if err != nil || 0 > req.Page || 0 > req.PageSize || models.MAX_BATTLE_HISTORY_PAGE_SIZE < req.PageSize {
+		c.Set(api.RET, Constants.RetCode.InvalidRequestParam)
+		return
+	}
+	battles, totalCount, err := models.GetBattleHistoryByPlayerId(int32(playerId), req.Page, req.PageSize)
+	if err != nil {
+		api.CErr(c, err)
+		c.Set(api.RET, Constants.RetCode.MysqlError)
+		return
+	}
+	resp := struct {
+		Ret        int                          `json:"ret"`
+		Page       int                          `json:"page"`
+		PageSize   int                          `json:"pageSize"`
+		TotalCount int                          `json:"totalCount"`
+		Battles    []*models.BattleHistoryEntry `json:"battles"`
+	}{Constants.RetCode.Ok, req.Page, req.PageSize, totalCount, battles}
+	c.JSON(http.StatusOK, resp)
+}
+
 func (p *playerController) TokenAuth(c *gin.Context) {
 	var req struct {
 		Token          string `form:"intAuthToken"`
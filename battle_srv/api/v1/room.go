@@ -0,0 +1,142 @@
+package v1
+
+import (
+	"battle_srv/api"
+	. "battle_srv/common"
+	"battle_srv/models"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"net/http"
+	"strconv"
+)
+
+var Room = roomController{}
+
+type roomController struct {
+}
+
+// Each absent field falls back to that of "models.DefaultRoomParams".
+type privateRoomReq struct {
+	StageName                  *string `form:"stageName"`
+	Capacity                   *int    `form:"capacity"`
+	TeamCount                  *int32  `form:"teamCount"`
+	FriendlyFireEnabled        *bool   `form:"friendlyFireEnabled"`
+	BattleDurationSeconds      *int32  `form:"battleDurationSeconds"`
+	ReadyCheckSeconds          *int32  `form:"readyCheckSeconds"`
+	RematchWindowSeconds       *int32  `form:"rematchWindowSeconds"`
+	AdaptiveInputDelayEnabled  *bool   `form:"adaptiveInputDelayEnabled"`
+	BackendRollbackEnabled     *bool   `form:"backendRollbackEnabled"`
+	ResyncUponChecksumMismatch *bool   `form:"resyncUponChecksumMismatch"`
+	FixedPointCollisionEnabled *bool   `form:"fixedPointCollisionEnabled"`
+	PlayerCollisionEnabled     *bool   `form:"playerCollisionEnabled"`
+	KoRespawnFrames            *int32  `form:"koRespawnFrames"`
+}
+
+func (req *privateRoomReq) roomParams() *models.RoomParams {
+	params := models.DefaultRoomParams()
+	if nil != req.StageName {
+		params.StageName = *req.StageName
+	}
+	if nil != req.Capacity {
+		params.Capacity = *req.Capacity
+	}
+	if nil != req.TeamCount {
+		params.TeamCount = *req.TeamCount
+	}
+	if nil != req.FriendlyFireEnabled {
+		params.FriendlyFireEnabled = *req.FriendlyFireEnabled
+	}
+	if nil != req.BattleDurationSeconds {
+		params.BattleDurationSeconds = *req.BattleDurationSeconds
+	}
+	if nil != req.ReadyCheckSeconds {
+		params.ReadyCheckSeconds = *req.ReadyCheckSeconds
+	}
+	if nil != req.RematchWindowSeconds {
+		params.RematchWindowSeconds = *req.RematchWindowSeconds
+	}
+	if nil != req.AdaptiveInputDelayEnabled {
+		params.AdaptiveInputDelayEnabled = *req.AdaptiveInputDelayEnabled
+	}
+	if nil != req.BackendRollbackEnabled {
+		params.BackendRollbackEnabled = *req.BackendRollbackEnabled
+	}
+	if nil != req.ResyncUponChecksumMismatch {
+		params.ResyncUponChecksumMismatch = *req.ResyncUponChecksumMismatch
+	}
+	if nil != req.FixedPointCollisionEnabled {
+		params.FixedPointCollisionEnabled = *req.FixedPointCollisionEnabled
+	}
+	if nil != req.PlayerCollisionEnabled {
+		params.PlayerCollisionEnabled = *req.PlayerCollisionEnabled
+	}
+	if nil != req.KoRespawnFrames {
+		params.KoRespawnFrames = *req.KoRespawnFrames
+	}
+	return params
+}
+
+/*
+Reserves a private room for the authenticated player, the returned "inviteCode" is then carried by the websocket param "inviteCode" of every participant including the host. A player already hosting a reserved room is rejected until it's released.
+
+The room is customized by the optional form fields of "privateRoomReq", e.g. "stageName=dungeon&capacity=4&teamCount=2", which are validated by "models.RoomParams.Validate".
+*/
+func (p *roomController) ReservePrivateRoom(c *gin.Context) {
+	playerId := c.GetInt(api.PLAYER_ID)
+	var req privateRoomReq
+	err := c.ShouldBindWith(&req, binding.FormPost)
+	api.CErr(c, err)
+	if nil != err {
+		c.Set(api.RET, Constants.RetCode.InvalidRequestParam)
+		return
+	}
+	params := req.roomParams()
+	if err := params.Validate(); nil != err {
+		api.CErr(c, err)
+		c.Set(api.RET, Constants.RetCode.InvalidRequestParam)
+		return
+	}
+	pR, inviteCode, err := models.ReservePrivateRoom(int32(playerId), params)
+	if nil != err {
+		api.CErr(c, err)
+		c.Set(api.RET, Constants.RetCode.LocallyNoAvailableRoom)
+		return
+	}
+	resp := struct {
+		Ret        int    `json:"ret"`
+		RoomId     int32  `json:"roomId"`
+		InviteCode string `json:"inviteCode"`
+	}{Constants.RetCode.Ok, pR.Id, inviteCode}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Only registered when "ServerEnv=TEST", see "setRouter" in "main.go".
+func (p *roomController) LoadStats(c *gin.Context) {
+	resp := struct {
+		Ret   int                             `json:"ret"`
+		Rooms []*models.RoomLoadStatsSnapshot `json:"rooms"`
+	}{Constants.RetCode.Ok, models.AllRoomLoadStatsSnapshots()}
+	c.JSON(http.StatusOK, resp)
+}
+
+/*
+Per-player network quality of each room, e.g. "POST /api/room/v1/netStats" with the form "intAuthToken=...&roomId=3", all rooms are returned if "roomId" is absent.
+
+Behind "TokenAuth" because player ids and round-trip times aren't for the public.
+*/
+func (p *roomController) NetStats(c *gin.Context) {
+	roomId := 0
+	if roomIdStr := c.PostForm("roomId"); "" != roomIdStr {
+		var err error
+		roomId, err = strconv.Atoi(roomIdStr)
+		if nil != err {
+			c.Set(api.RET, Constants.RetCode.InvalidRequestParam)
+			return
+		}
+	}
+	resp := struct {
+		Ret   int                            `json:"ret"`
+		Rooms []*models.RoomNetStatsSnapshot `json:"rooms"`
+	}{Constants.RetCode.Ok, models.AllRoomNetStatsSnapshots(int32(roomId))}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,57 @@
+package battle
+
+import (
+	. "battle_srv/protos"
+	"encoding/binary"
+	"hash/fnv"
+	"sort"
+)
+
+/*
+A deterministic FNV-1a hash of the gameplay-relevant part of a "RoomDownsyncFrame", i.e. players (traversed by "joinIndex" instead of the randomized Golang map order), melee bullets and fireballs.
+
+[WARNING] Fields that're not necessarily consistent between the frontend and the backend, e.g. "MeleeBullet.BattleLocalId" and "CountdownNanos", are deliberately excluded.
+*/
+func Checksum(rdf *RoomDownsyncFrame) uint64 {
+	players := make([]*PlayerDownsync, 0, len(rdf.Players))
+	for _, player := range rdf.Players {
+		players = append(players, player)
+	}
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].JoinIndex < players[j].JoinIndex
+	})
+
+	h := fnv.New64a()
+	buf := make([]byte, 4)
+	write := func(v int32) {
+		binary.LittleEndian.PutUint32(buf, uint32(v))
+		h.Write(buf)
+	}
+
+	write(rdf.Id)
+	write(int32(len(players)))
+	for _, player := range players {
+		write(player.JoinIndex)
+		write(player.VirtualGridX)
+		write(player.VirtualGridY)
+		write(player.FramesToRecover)
+		write(player.Hp)
+		write(player.VelY)
+	}
+	write(int32(len(rdf.MeleeBullets)))
+	for _, meleeBullet := range rdf.MeleeBullets {
+		write(meleeBullet.OffenderJoinIndex)
+		write(meleeBullet.OriginatedRenderFrameId)
+	}
+	write(int32(len(rdf.FireballBullets)))
+	for _, fireball := range rdf.FireballBullets {
+		write(fireball.OffenderJoinIndex)
+		write(fireball.OriginatedRenderFrameId)
+		write(fireball.VirtualGridX)
+		write(fireball.VirtualGridY)
+		write(fireball.VelX)
+		write(fireball.VelY)
+	}
+
+	return h.Sum64()
+}
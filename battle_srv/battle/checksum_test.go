@@ -0,0 +1,59 @@
+package battle
+
+import (
+	. "battle_srv/protos"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+)
+
+func newTestChecksumFrame() *RoomDownsyncFrame {
+	return &RoomDownsyncFrame{
+		Id: 120,
+		Players: map[int32]*PlayerDownsync{
+			10: {JoinIndex: 1, VirtualGridX: -1200, VirtualGridY: 340, FramesToRecover: 3, Hp: 80, VelY: -20},
+			7:  {JoinIndex: 2, VirtualGridX: 900, VirtualGridY: 340, FramesToRecover: 0, Hp: 100, VelY: 0},
+		},
+		MeleeBullets:    []*MeleeBullet{{OffenderJoinIndex: 2, OriginatedRenderFrameId: 118, BattleLocalId: 5}},
+		FireballBullets: []*FireballBullet{{OffenderJoinIndex: 1, OriginatedRenderFrameId: 100, VirtualGridX: 400, VirtualGridY: 360, VelX: 4000}},
+		CountdownNanos:  12345,
+	}
+}
+
+func TestChecksumIsDeterministic(t *testing.T) {
+	rdf := newTestChecksumFrame()
+	expected := Checksum(rdf)
+
+	// The Golang map order is randomized per traversal, thus repeated to cover different orders
+	for i := 0; i < 16; i++ {
+		if actual := Checksum(proto.Clone(rdf).(*RoomDownsyncFrame)); expected != actual {
+			t.Fatalf("checksum of a cloned frame changed from %v to %v", expected, actual)
+		}
+	}
+
+	// Excluded fields never affect the checksum
+	excludedChanged := proto.Clone(rdf).(*RoomDownsyncFrame)
+	excludedChanged.CountdownNanos = 0
+	excludedChanged.MeleeBullets[0].BattleLocalId = 6
+	if actual := Checksum(excludedChanged); expected != actual {
+		t.Fatalf("checksum changed from %v to %v by excluded fields", expected, actual)
+	}
+
+	playerChanged := proto.Clone(rdf).(*RoomDownsyncFrame)
+	playerChanged.Players[7].Hp = 99
+	if actual := Checksum(playerChanged); expected == actual {
+		t.Fatalf("checksum unchanged by the hp of a player")
+	}
+
+	fireballChanged := proto.Clone(rdf).(*RoomDownsyncFrame)
+	fireballChanged.FireballBullets[0].VirtualGridX = 401
+	if actual := Checksum(fireballChanged); expected == actual {
+		t.Fatalf("checksum unchanged by the position of a fireball")
+	}
+
+	fireballTurned := proto.Clone(rdf).(*RoomDownsyncFrame)
+	fireballTurned.FireballBullets[0].VelX = -4000
+	if actual := Checksum(fireballTurned); expected == actual {
+		t.Fatalf("checksum unchanged by the velocity of a fireball")
+	}
+}
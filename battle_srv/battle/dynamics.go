@@ -0,0 +1,864 @@
+package battle
+
+import (
+	. "battle_srv/protos"
+	. "dnmshared"
+	. "dnmshared/sharedprotos"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"github.com/solarlune/resolv"
+)
+
+const (
+	COLLISION_CATEGORY_CONTROLLED_PLAYER = (1 << 1)
+	COLLISION_CATEGORY_BARRIER           = (1 << 2)
+
+	COLLISION_MASK_FOR_CONTROLLED_PLAYER = (COLLISION_CATEGORY_BARRIER) // Plus "COLLISION_CATEGORY_CONTROLLED_PLAYER" when "Stage.PlayerCollisionEnabled"
+	COLLISION_MASK_FOR_BARRIER           = (COLLISION_CATEGORY_CONTROLLED_PLAYER)
+
+	COLLISION_PLAYER_INDEX_PREFIX  = (1 << 17)
+	COLLISION_BARRIER_INDEX_PREFIX = (1 << 16)
+	COLLISION_BULLET_INDEX_PREFIX  = (1 << 15)
+)
+
+const (
+	ATK_CHARACTER_STATE_IDLE1   = 0
+	ATK_CHARACTER_STATE_WALKING = 1
+	ATK_CHARACTER_STATE_ATK1    = 2
+	ATK_CHARACTER_STATE_ATKED1  = 3
+	ATK_CHARACTER_STATE_KO      = 4 // Knocked out by "Hp" depleted, neither takes inputs nor collides until respawned
+	ATK_CHARACTER_STATE_GUARD   = 5 // Holding the guard button, immobile but able to turn around
+	ATK_CHARACTER_STATE_BLOCKED = 6 // In block stun
+)
+
+const (
+	BATTLE_MODE_TOP_DOWN   = 0 // Moving freely in both axes
+	BATTLE_MODE_PLATFORMER = 1 // Moving horizontally by inputs, vertically by gravity and jumping with BtnD
+)
+
+const (
+	SKILL_RELEASE_TRIGGER_TYPE_RISING_EDGE  = 1
+	SKILL_RELEASE_TRIGGER_TYPE_FALLING_EDGE = 2
+
+	BLOCKED_PUSHBACK_DIVIDER = 2 // A blocked bullet pushes the defender back by "Pushback/BLOCKED_PUSHBACK_DIVIDER" in virtual grid units, truncated towards zero
+)
+
+// These directions are chosen such that when speed is changed to "(speedX+delta, speedY+delta)" for any of them, the direction is unchanged.
+var DIRECTION_DECODER = [][]int32{
+	{0, 0},
+	{0, +2},
+	{0, -2},
+	{+2, 0},
+	{-2, 0},
+	{+1, +1},
+	{-1, -1},
+	{+1, -1},
+	{-1, +1},
+}
+
+/*
+A "Simulator" only caches the "resolv.Space" and the colliders of a "Stage" to save heap allocation between consecutive render frames, i.e. every collider position is reset from the input "RoomDownsyncFrame" at the beginning of "Step" and every bullet collider is removed at its end.
+
+Therefore the output of "Step" depends ONLY on its inputs and the "Stage", no matter what the previously stepped frames were -- which is the key for being rollback-compatible. However a "Simulator" is NOT thread-safe, use one instance per goroutine.
+*/
+type Simulator struct {
+	stage           *Stage
+	space           *resolv.Space
+	playerColliders []*resolv.Object // Indexed by "joinIndex-1"
+
+	// The following are only used when "stage.FixedPointCollisionEnabled", see "calcEffPushbacksInVirtualGrid"
+	barrierPolygons        []*IntPolygon2D
+	oneWayPlatformPolygons []*IntPolygon2D
+	playerPolygons         []*IntPolygon2D // Indexed by "joinIndex-1", reset from the current positions for bullets and then from the moved positions for pushbacks at each "Step"
+	bulletPolygon          *IntPolygon2D   // Reused by every bullet
+}
+
+func NewSimulator(stage *Stage) *Simulator {
+	space := resolv.NewSpace(int(stage.SpaceW), int(stage.SpaceH), stage.CellSize, stage.CellSize)
+	for _, barrier := range stage.Barriers {
+		barrierCollider := GenerateConvexPolygonCollider(barrier, stage.SpaceOffsetX, stage.SpaceOffsetY, "Barrier")
+		space.Add(barrierCollider)
+	}
+	if stage.IsPlatformer() {
+		for _, oneWayPlatform := range stage.OneWayPlatforms {
+			space.Add(GenerateConvexPolygonCollider(oneWayPlatform, stage.SpaceOffsetX, stage.SpaceOffsetY, "OneWayPlatform"))
+		}
+	}
+
+	pS := &Simulator{
+		stage:           stage,
+		space:           space,
+		playerColliders: make([]*resolv.Object, stage.Capacity),
+	}
+	if stage.FixedPointCollisionEnabled {
+		pS.barrierPolygons = make([]*IntPolygon2D, 0, len(stage.Barriers))
+		for _, barrier := range stage.Barriers {
+			pS.barrierPolygons = append(pS.barrierPolygons, Polygon2DToIntPolygon2D(barrier, stage.WorldToVirtualGridRatio))
+		}
+		pS.oneWayPlatformPolygons = make([]*IntPolygon2D, 0, len(stage.OneWayPlatforms))
+		if stage.IsPlatformer() {
+			for _, oneWayPlatform := range stage.OneWayPlatforms {
+				pS.oneWayPlatformPolygons = append(pS.oneWayPlatformPolygons, Polygon2DToIntPolygon2D(oneWayPlatform, stage.WorldToVirtualGridRatio))
+			}
+		}
+		pS.playerPolygons = make([]*IntPolygon2D, stage.Capacity)
+		for i := range pS.playerPolygons {
+			pS.playerPolygons[i] = &IntPolygon2D{}
+		}
+		pS.bulletPolygon = &IntPolygon2D{}
+	}
+	return pS
+}
+
+func (pS *Simulator) Stage() *Stage {
+	return pS.stage
+}
+
+// A one-off convenience for offline usage, e.g. tools and tests, prefer reusing a "Simulator" when stepping through many frames.
+func ApplyInputFrameDownsyncDynamicsOnSingleRenderFrame(stage *Stage, delayedInputFrame, delayedInputFrameForPrevRenderFrame *InputFrameDownsync, currRenderFrame *RoomDownsyncFrame) *RoomDownsyncFrame {
+	return NewSimulator(stage).Step(delayedInputFrame, delayedInputFrameForPrevRenderFrame, currRenderFrame)
+}
+
+// TODO: Write unit-test for this function to compare with its frontend counter part
+func (pS *Simulator) Step(delayedInputFrame, delayedInputFrameForPrevRenderFrame *InputFrameDownsync, currRenderFrame *RoomDownsyncFrame) *RoomDownsyncFrame {
+	stage := pS.stage
+	// [WARNING] Traversal of a Golang map is randomized, thus all the following traversals are done by "joinIndex" to guarantee determinism.
+	currPlayers := make([]*PlayerDownsync, stage.Capacity)
+	for _, currPlayerDownsync := range currRenderFrame.Players {
+		joinIndex := currPlayerDownsync.JoinIndex
+		if 0 >= joinIndex || int(joinIndex) > stage.Capacity {
+			panic(fmt.Sprintf("Invalid joinIndex=%v of playerId=%v at currRenderFrame.Id=%v, capacity=%v", joinIndex, currPlayerDownsync.Id, currRenderFrame.Id, stage.Capacity))
+		}
+		currPlayers[joinIndex-1] = currPlayerDownsync
+	}
+
+	// TODO: Derive "nextRenderFramePlayers[*].CharacterState" as the frontend counter-part!
+	nextRenderFramePlayers := make(map[int32]*PlayerDownsync, stage.Capacity)
+	nextPlayers := make([]*PlayerDownsync, stage.Capacity)
+	// Make a copy first
+	for i, currPlayerDownsync := range currPlayers {
+		if nil == currPlayerDownsync {
+			continue
+		}
+		playerId := currPlayerDownsync.Id
+		nextPlayers[i] = &PlayerDownsync{
+			Id:              playerId,
+			VirtualGridX:    currPlayerDownsync.VirtualGridX,
+			VirtualGridY:    currPlayerDownsync.VirtualGridY,
+			DirX:            currPlayerDownsync.DirX,
+			DirY:            currPlayerDownsync.DirY,
+			CharacterState:  currPlayerDownsync.CharacterState,
+			Speed:           currPlayerDownsync.Speed,
+			BattleState:     currPlayerDownsync.BattleState,
+			Score:           currPlayerDownsync.Score,
+			Removed:         currPlayerDownsync.Removed,
+			JoinIndex:       currPlayerDownsync.JoinIndex,
+			ColliderRadius:  currPlayerDownsync.ColliderRadius,
+			FramesToRecover: currPlayerDownsync.FramesToRecover - 1,
+			Hp:              currPlayerDownsync.Hp,
+			MaxHp:           currPlayerDownsync.MaxHp,
+			SpeciesId:       currPlayerDownsync.SpeciesId,
+			TeamId:          currPlayerDownsync.TeamId,
+			VelY:            currPlayerDownsync.VelY,
+			Grounded:        currPlayerDownsync.Grounded,
+			HitsLanded:      currPlayerDownsync.HitsLanded,
+		}
+		if nextPlayers[i].FramesToRecover < 0 {
+			nextPlayers[i].FramesToRecover = 0
+		}
+		nextRenderFramePlayers[playerId] = nextPlayers[i]
+	}
+
+	toRet := &RoomDownsyncFrame{
+		Id:                   currRenderFrame.Id + 1,
+		Players:              nextRenderFramePlayers,
+		CountdownNanos:       (stage.BattleDurationNanos - int64(currRenderFrame.Id)*stage.RollbackEstimatedDtNanos),
+		MeleeBullets:         make([]*MeleeBullet, 0), // Is there any better way to reduce malloc/free impact, e.g. smart prediction for fixed memory allocation?
+		FireballBullets:      make([]*FireballBullet, 0),
+		BulletLocalIdCounter: currRenderFrame.BulletLocalIdCounter,
+	}
+
+	bulletPushbacks := make([]IntVec2D, stage.Capacity) // In virtual grid units, guaranteed determinism regardless of traversal order
+	effPushbacks := make([]Vec2D, stage.Capacity)       // Guaranteed determinism regardless of traversal order
+
+	// The same movements as of "pS.playerColliders" but tracked in virtual grid units, only used when "stage.FixedPointCollisionEnabled"
+	movedVirtualGrids := make([]IntVec2D, stage.Capacity)
+	effPushbacksInVirtualGrid := make([]IntVec2D, stage.Capacity)
+
+	// Reset playerCollider position from the "virtual grid position"
+	for i, currPlayerDownsync := range currPlayers {
+		playerCollider := pS.playerColliders[i]
+		if nil == currPlayerDownsync || ATK_CHARACTER_STATE_KO == currPlayerDownsync.CharacterState {
+			if nil != playerCollider {
+				pS.space.Remove(playerCollider)
+				pS.playerColliders[i] = nil
+			}
+			continue
+		}
+		colliderWidth := currPlayerDownsync.ColliderRadius * 2
+		if nil == playerCollider || colliderWidth != playerCollider.W {
+			if nil != playerCollider {
+				pS.space.Remove(playerCollider)
+			}
+			playerCollider = GenerateRectColliderInCollisionSpace(0, 0, colliderWidth, colliderWidth, "Player")
+			pS.space.Add(playerCollider)
+			pS.playerColliders[i] = playerCollider
+		}
+		playerCollider.Data = currPlayerDownsync
+		movedVirtualGrids[i] = IntVec2D{X: int64(currPlayerDownsync.VirtualGridX), Y: int64(currPlayerDownsync.VirtualGridY)}
+		playerCollider.X, playerCollider.Y = VirtualGridToPolygonColliderAnchorPos(currPlayerDownsync.VirtualGridX, currPlayerDownsync.VirtualGridY, currPlayerDownsync.ColliderRadius, currPlayerDownsync.ColliderRadius, stage.SpaceOffsetX, stage.SpaceOffsetY, stage.VirtualGridToWorldRatio)
+		playerCollider.Update()
+	}
+
+	// The guard is held by the delayed input of the current render frame, the same one as processed for movements below
+	guardLevels := make([]int32, stage.Capacity)
+	if nil != delayedInputFrame {
+		for i, currPlayerDownsync := range currPlayers {
+			if nil == currPlayerDownsync {
+				continue
+			}
+			guardLevels[i] = DecodeInput(delayedInputFrame.InputList[currPlayerDownsync.JoinIndex-1]).GuardLevel
+		}
+	}
+
+	if stage.FixedPointCollisionEnabled {
+		pS.resetPlayerPolygons(currPlayers, movedVirtualGrids)
+	}
+
+	// Check bullet-anything collisions first, because the pushbacks caused by bullets might later be reverted by player-barrier collision
+	bulletColliders := make([]*resolv.Object, 0, len(currRenderFrame.MeleeBullets)) // Will all be removed at the end of `Step` due to the need for being rollback-compatible
+	removedBulletsAtCurrFrame := make(map[int32]int32, 0)
+	for _, meleeBullet := range currRenderFrame.MeleeBullets {
+		if (meleeBullet.OriginatedRenderFrameId+meleeBullet.StartupFrames <= currRenderFrame.Id) && (meleeBullet.OriginatedRenderFrameId+meleeBullet.StartupFrames+meleeBullet.ActiveFrames > currRenderFrame.Id) {
+			offender := currRenderFrame.Players[meleeBullet.OffenderPlayerId]
+			if nil == offender {
+				continue
+			}
+
+			xfac := float64(1.0) // By now, straight Punch offset doesn't respect "y-axis"
+			if 0 > offender.DirX {
+				xfac = float64(-1.0)
+			}
+			if stage.FixedPointCollisionEnabled {
+				if collided := pS.checkMeleeBulletCollisionInVirtualGrid(meleeBullet, offender, xfac, currPlayers, nextPlayers, guardLevels, bulletPushbacks, currRenderFrame); collided {
+					removedBulletsAtCurrFrame[meleeBullet.BattleLocalId] = 1
+				}
+				continue
+			}
+			offenderWx, offenderWy := VirtualGridToWorldPos(offender.VirtualGridX, offender.VirtualGridY, stage.VirtualGridToWorldRatio)
+			bulletWx, bulletWy := offenderWx+xfac*meleeBullet.HitboxOffset, offenderWy
+
+			newBulletCollider := GenerateRectCollider(bulletWx, bulletWy, meleeBullet.HitboxSize.X, meleeBullet.HitboxSize.Y, stage.SpaceOffsetX, stage.SpaceOffsetY, "MeleeBullet")
+			newBulletCollider.Data = meleeBullet
+			pS.space.Add(newBulletCollider)
+			bulletColliders = append(bulletColliders, newBulletCollider)
+
+			Logger.Debug(fmt.Sprintf("A meleeBullet is added to collisionSys at currRenderFrame.id=%v as start-up frames ended and active frame is not yet ended: %v, xfac=%v", currRenderFrame.Id, ConvexPolygonStr(newBulletCollider.Shape.(*resolv.ConvexPolygon)), xfac))
+		}
+	}
+
+	for _, bulletCollider := range bulletColliders {
+		shouldRemove := false
+		meleeBullet := bulletCollider.Data.(*MeleeBullet)
+		bulletShape := bulletCollider.Shape.(*resolv.ConvexPolygon)
+		if collision := bulletCollider.Check(0, 0); collision != nil {
+			offender := currRenderFrame.Players[meleeBullet.OffenderPlayerId]
+			for _, obj := range collision.Objects {
+				defenderShape := obj.Shape.(*resolv.ConvexPolygon)
+				switch t := obj.Data.(type) {
+				case *PlayerDownsync:
+					if stage.CanHit(offender, t) {
+						if overlapped, _, _, _ := CalcPushbacks(0, 0, bulletShape, defenderShape); overlapped {
+							xfac := float64(1.0) // By now, straight Punch offset doesn't respect "y-axis"
+							if 0 > offender.DirX {
+								xfac = float64(-1.0)
+							}
+							onMeleeBulletHit(stage, meleeBullet, offender, t, xfac, nextPlayers, guardLevels, bulletPushbacks, currRenderFrame)
+							Logger.Debug(fmt.Sprintf("A meleeBullet collides w/ player at currRenderFrame.id=%v: b=%v, p=%v", currRenderFrame.Id, ConvexPolygonStr(bulletShape), ConvexPolygonStr(defenderShape)))
+						}
+					}
+				default:
+					Logger.Debug(fmt.Sprintf("Bullet %v collided with non-player %v: currRenderFrame.Id=%v, objDataType=%t, objData=%v", ConvexPolygonStr(bulletShape), ConvexPolygonStr(defenderShape), currRenderFrame.Id, obj.Data, obj.Data))
+				}
+			}
+			shouldRemove = true
+		}
+		if shouldRemove {
+			removedBulletsAtCurrFrame[meleeBullet.BattleLocalId] = 1
+		}
+	}
+
+	for _, bulletCollider := range bulletColliders {
+		pS.space.Remove(bulletCollider)
+	}
+
+	for _, meleeBullet := range currRenderFrame.MeleeBullets {
+		if _, existent := removedBulletsAtCurrFrame[meleeBullet.BattleLocalId]; existent {
+			continue
+		}
+		toRet.MeleeBullets = append(toRet.MeleeBullets, meleeBullet)
+	}
+
+	// Each fireball is cloned into "toRet" because its position changes every render frame, unlike a "MeleeBullet" which is immutable once fired
+	for _, fireball := range currRenderFrame.FireballBullets {
+		launchedRenderFrameId := fireball.OriginatedRenderFrameId + fireball.StartupFrames
+		if launchedRenderFrameId <= fireball.OriginatedRenderFrameId {
+			// A fireball fired at "OriginatedRenderFrameId" first appears in the next render frame, thus never launched otherwise
+			launchedRenderFrameId = fireball.OriginatedRenderFrameId + 1
+		}
+		if launchedRenderFrameId+fireball.ActiveFrames <= currRenderFrame.Id {
+			continue
+		}
+		nextFireball := proto.Clone(fireball).(*FireballBullet)
+		if launchedRenderFrameId > currRenderFrame.Id {
+			toRet.FireballBullets = append(toRet.FireballBullets, nextFireball)
+			continue
+		}
+		if launchedRenderFrameId == currRenderFrame.Id {
+			offender := currRenderFrame.Players[fireball.OffenderPlayerId]
+			if nil == offender || ATK_CHARACTER_STATE_KO == offender.CharacterState {
+				continue
+			}
+			xfac := int32(1) // By now, a fireball doesn't respect "y-axis" either
+			if 0 > offender.DirX {
+				xfac = int32(-1)
+			}
+			nextFireball.VirtualGridX, nextFireball.VirtualGridY = offender.VirtualGridX+xfac*fireball.HitboxOffset, offender.VirtualGridY
+			nextFireball.VelX, nextFireball.VelY = xfac*fireball.Speed, 0
+		}
+
+		if destroyed := pS.checkFireballCollision(nextFireball, currRenderFrame, currPlayers, nextPlayers, guardLevels, bulletPushbacks); destroyed {
+			continue
+		}
+		nextFireball.VirtualGridX += nextFireball.VelX
+		nextFireball.VirtualGridY += nextFireball.VelY
+		toRet.FireballBullets = append(toRet.FireballBullets, nextFireball)
+	}
+
+	if nil != delayedInputFrame {
+		inputList := delayedInputFrame.InputList
+		// Process player inputs
+		for i, currPlayerDownsync := range currPlayers {
+			if nil == currPlayerDownsync {
+				continue
+			}
+			playerId := currPlayerDownsync.Id
+			joinIndex := currPlayerDownsync.JoinIndex
+			if ATK_CHARACTER_STATE_KO == currPlayerDownsync.CharacterState {
+				// No collider for this player, see the respawning below
+				continue
+			}
+			playerCollider := pS.playerColliders[i]
+			thatPlayerInNextFrame := nextPlayers[i]
+			decodedInput := DecodeInput(inputList[joinIndex-1])
+			var prevDecodedInput *InputFrameDecoded = nil
+			if nil != delayedInputFrameForPrevRenderFrame {
+				prevDecodedInput = DecodeInput(delayedInputFrameForPrevRenderFrame.InputList[joinIndex-1])
+			}
+			if stage.IsPlatformer() {
+				// The y-axis is only driven by gravity and jumping, which applies even when this player can't take any input
+				decodedInput.Dy = 0
+				pS.moveVertically(decodedInput, prevDecodedInput, currPlayerDownsync, thatPlayerInNextFrame, playerCollider, &movedVirtualGrids[i])
+			}
+			if 0 < thatPlayerInNextFrame.FramesToRecover || ATK_CHARACTER_STATE_KO == thatPlayerInNextFrame.CharacterState {
+				// No need to process inputs for this player, but there might be bullet pushbacks on this player
+				bulletPushbackWx, bulletPushbackWy := VirtualGridToWorldPos(int32(bulletPushbacks[joinIndex-1].X), int32(bulletPushbacks[joinIndex-1].Y), stage.VirtualGridToWorldRatio)
+				playerCollider.X += bulletPushbackWx
+				playerCollider.Y += bulletPushbackWy
+				// Update in the collision system
+				playerCollider.Update()
+				movedVirtualGrids[i].X += bulletPushbacks[joinIndex-1].X
+				movedVirtualGrids[i].Y += bulletPushbacks[joinIndex-1].Y
+				if 0 != bulletPushbacks[joinIndex-1].X || 0 != bulletPushbacks[joinIndex-1].Y {
+					Logger.Debug(fmt.Sprintf("playerId=%v is pushed back by (%v, %v) in virtual grids by bullet impacts, now its framesToRecover is %d at currRenderFrame.id=%v", playerId, bulletPushbacks[joinIndex-1].X, bulletPushbacks[joinIndex-1].Y, thatPlayerInNextFrame.FramesToRecover, currRenderFrame.Id))
+				}
+				continue
+			}
+
+			if edgeTriggered := pS.triggerBtnEdges(decodedInput, prevDecodedInput, currPlayerDownsync, thatPlayerInNextFrame, currRenderFrame, toRet); edgeTriggered {
+				Logger.Debug(fmt.Sprintf("playerId=%v triggered a button edge at currRenderFrame.id=%v, delayedInputFrame.id=%v", playerId, currRenderFrame.Id, delayedInputFrame.InputFrameId))
+			} else if 0 < decodedInput.GuardLevel {
+				// Guarding, only turning around is allowed
+				if 0 != decodedInput.Dx || 0 != decodedInput.Dy {
+					thatPlayerInNextFrame.DirX = decodedInput.Dx
+					thatPlayerInNextFrame.DirY = decodedInput.Dy
+				}
+				thatPlayerInNextFrame.CharacterState = ATK_CHARACTER_STATE_GUARD
+				continue
+			} else {
+				// No bullet trigger, process movement inputs
+				if 0 != decodedInput.Dx || 0 != decodedInput.Dy {
+					thatPlayerInNextFrame.DirX = decodedInput.Dx
+					thatPlayerInNextFrame.DirY = decodedInput.Dy
+					thatPlayerInNextFrame.CharacterState = ATK_CHARACTER_STATE_WALKING
+				} else {
+					thatPlayerInNextFrame.CharacterState = ATK_CHARACTER_STATE_IDLE1
+				}
+			}
+
+			movementVx, movementVy := decodedInput.Dx+decodedInput.Dx*currPlayerDownsync.Speed, decodedInput.Dy+decodedInput.Dy*currPlayerDownsync.Speed
+			movementX, movementY := VirtualGridToWorldPos(movementVx, movementVy, stage.VirtualGridToWorldRatio)
+			playerCollider.X += movementX
+			playerCollider.Y += movementY
+			movedVirtualGrids[i].X += int64(movementVx)
+			movedVirtualGrids[i].Y += int64(movementVy)
+
+			// Update in the collision system
+			playerCollider.Update()
+		}
+
+		// handle pushbacks upon collision after all movements treated as simultaneous
+		if stage.FixedPointCollisionEnabled {
+			pS.calcEffPushbacksInVirtualGrid(currPlayers, nextPlayers, movedVirtualGrids, effPushbacksInVirtualGrid)
+		} else {
+			pS.calcEffPushbacks(currPlayers, nextPlayers, effPushbacks)
+		}
+
+		for i, currPlayerDownsync := range currPlayers {
+			if nil == currPlayerDownsync {
+				continue
+			}
+			if ATK_CHARACTER_STATE_KO == currPlayerDownsync.CharacterState {
+				if 0 < stage.KoRespawnFrames && 0 == nextPlayers[i].FramesToRecover {
+					respawn(stage, nextPlayers[i])
+				}
+				continue
+			}
+			playerCollider := pS.playerColliders[i]
+
+			// Update "virtual grid position"
+			var newVx, newVy int32
+			effPushbackY := effPushbacks[i].Y
+			if stage.FixedPointCollisionEnabled {
+				newVx, newVy = int32(movedVirtualGrids[i].X-effPushbacksInVirtualGrid[i].X), int32(movedVirtualGrids[i].Y-effPushbacksInVirtualGrid[i].Y)
+				effPushbackY = float64(effPushbacksInVirtualGrid[i].Y) // Only the sign matters below
+			} else {
+				newVx, newVy = PolygonColliderAnchorToVirtualGridPos(playerCollider.X-effPushbacks[i].X, playerCollider.Y-effPushbacks[i].Y, currPlayerDownsync.ColliderRadius, currPlayerDownsync.ColliderRadius, stage.SpaceOffsetX, stage.SpaceOffsetY, stage.WorldToVirtualGridRatio)
+			}
+			thatPlayerInNextFrame := nextPlayers[i]
+			thatPlayerInNextFrame.VirtualGridX, thatPlayerInNextFrame.VirtualGridY = newVx, newVy
+			if stage.IsPlatformer() {
+				// Note that a positive "effPushbackY" moves this player downwards
+				if 0 > effPushbackY && 0 >= thatPlayerInNextFrame.VelY {
+					thatPlayerInNextFrame.Grounded = true
+					thatPlayerInNextFrame.VelY = 0
+				} else if 0 < effPushbackY && 0 < thatPlayerInNextFrame.VelY {
+					// Bumped into a ceiling
+					thatPlayerInNextFrame.VelY = 0
+				}
+			}
+		}
+
+		Logger.Debug(fmt.Sprintf("After Step: currRenderFrame.Id=%v, inputList=%v, currRenderFrame.Players=%v, nextRenderFramePlayers=%v", currRenderFrame.Id, inputList, currRenderFrame.Players, nextRenderFramePlayers))
+	}
+
+	return toRet
+}
+
+/*
+Returns true if any button in "SKILL_BTNS" has a rising-edge or falling-edge, and fires at most one skill bound to such an edge, in the order of "SKILL_BTNS". Movement inputs are not processed when any edge is triggered, the same as when there was only BtnA.
+*/
+func (pS *Simulator) triggerBtnEdges(decodedInput, prevDecodedInput *InputFrameDecoded, currPlayerDownsync, thatPlayerInNextFrame *PlayerDownsync, currRenderFrame, toRet *RoomDownsyncFrame) bool {
+	edgeTriggered := false
+	for _, btn := range SKILL_BTNS {
+		if BTN_D == btn && pS.stage.IsPlatformer() {
+			// Reserved for jumping, see "moveVertically"
+			continue
+		}
+		level, prevLevel := BtnLevel(decodedInput, btn), BtnLevel(prevDecodedInput, btn)
+		if level == prevLevel {
+			continue
+		}
+		edgeTriggered = true
+		releaseTriggerType := int32(SKILL_RELEASE_TRIGGER_TYPE_RISING_EDGE)
+		if level < prevLevel {
+			releaseTriggerType = SKILL_RELEASE_TRIGGER_TYPE_FALLING_EDGE
+		}
+		if fired := fireSkillIfBound(pS.stage, btn, releaseTriggerType, currPlayerDownsync, thatPlayerInNextFrame, currRenderFrame, toRet); fired {
+			break
+		}
+	}
+	return edgeTriggered
+}
+
+// Fires the skill of "releaseTriggerType" bound to "btn" for the species of "currPlayerDownsync" if any, either melee or fireball.
+func fireSkillIfBound(stage *Stage, btn string, releaseTriggerType int32, currPlayerDownsync, thatPlayerInNextFrame *PlayerDownsync, currRenderFrame, toRet *RoomDownsyncFrame) bool {
+	if skillConfig := stage.FindSkill(currPlayerDownsync.SpeciesId, btn, releaseTriggerType); nil != skillConfig {
+		fireMeleeSkill(skillConfig, currPlayerDownsync, thatPlayerInNextFrame, currRenderFrame, toRet)
+		return true
+	}
+	if fireballSkillConfig := stage.FindFireballSkill(currPlayerDownsync.SpeciesId, btn, releaseTriggerType); nil != fireballSkillConfig {
+		fireFireballSkill(fireballSkillConfig, currPlayerDownsync, thatPlayerInNextFrame, currRenderFrame, toRet)
+		return true
+	}
+	return false
+}
+
+// Appends a clone of "skillConfig" to "toRet.MeleeBullets", the offender then can't take any other input until "RecoveryFrames" elapsed.
+func fireMeleeSkill(skillConfig *MeleeBullet, currPlayerDownsync, thatPlayerInNextFrame *PlayerDownsync, currRenderFrame, toRet *RoomDownsyncFrame) {
+	newMeleeBullet := proto.Clone(skillConfig).(*MeleeBullet)
+	newMeleeBullet.BattleLocalId = toRet.BulletLocalIdCounter
+	toRet.BulletLocalIdCounter += 1
+	newMeleeBullet.OffenderJoinIndex = currPlayerDownsync.JoinIndex
+	newMeleeBullet.OffenderPlayerId = currPlayerDownsync.Id
+	newMeleeBullet.OriginatedRenderFrameId = currRenderFrame.Id
+	toRet.MeleeBullets = append(toRet.MeleeBullets, newMeleeBullet)
+	thatPlayerInNextFrame.FramesToRecover = newMeleeBullet.RecoveryFrames
+	thatPlayerInNextFrame.CharacterState = ATK_CHARACTER_STATE_ATK1
+}
+
+// Resets a knocked-out player to its "PlayerStartingPos" with full "Hp", facing the same way as upon "onPlayerAdded" of "Room".
+func respawn(stage *Stage, thatPlayerInNextFrame *PlayerDownsync) {
+	indice := int(thatPlayerInNextFrame.JoinIndex - 1)
+	if indice < len(stage.PlayerStartingPositions) && nil != stage.PlayerStartingPositions[indice] {
+		playerPos := stage.PlayerStartingPositions[indice]
+		thatPlayerInNextFrame.VirtualGridX, thatPlayerInNextFrame.VirtualGridY = WorldToVirtualGridPos(playerPos.X, playerPos.Y, stage.WorldToVirtualGridRatio)
+	}
+	if 0 == (thatPlayerInNextFrame.JoinIndex % 2) {
+		thatPlayerInNextFrame.DirX = -2
+	} else {
+		thatPlayerInNextFrame.DirX = +2
+	}
+	thatPlayerInNextFrame.DirY = 0
+	thatPlayerInNextFrame.VelY = 0
+	thatPlayerInNextFrame.Grounded = false
+	thatPlayerInNextFrame.Hp = thatPlayerInNextFrame.MaxHp
+	thatPlayerInNextFrame.CharacterState = ATK_CHARACTER_STATE_IDLE1
+}
+
+/*
+Only in "BATTLE_MODE_PLATFORMER", moves "playerCollider" by the "VelY" of this render frame, which is either "JumpingInitVelY" upon a rising-edge of BtnD while grounded and not recovering, or that of "currPlayerDownsync" decreased by "GravityY" down to "-MaxFallingVelY".
+
+The gravity is applied even when grounded, such that walking off an edge starts falling right away, while "Grounded" and "VelY" of "thatPlayerInNextFrame" are reset by the pushbacks in "Step".
+*/
+func (pS *Simulator) moveVertically(decodedInput, prevDecodedInput *InputFrameDecoded, currPlayerDownsync, thatPlayerInNextFrame *PlayerDownsync, playerCollider *resolv.Object, movedVirtualGrid *IntVec2D) {
+	stage := pS.stage
+	jumping := currPlayerDownsync.Grounded && 0 == thatPlayerInNextFrame.FramesToRecover && 0 < decodedInput.BtnDLevel && 0 == BtnLevel(prevDecodedInput, BTN_D)
+	if jumping {
+		thatPlayerInNextFrame.VelY = stage.JumpingInitVelY
+	} else {
+		thatPlayerInNextFrame.VelY = currPlayerDownsync.VelY - stage.GravityY
+		if -stage.MaxFallingVelY > thatPlayerInNextFrame.VelY {
+			thatPlayerInNextFrame.VelY = -stage.MaxFallingVelY
+		}
+	}
+	thatPlayerInNextFrame.Grounded = false
+	_, movementY := VirtualGridToWorldPos(0, thatPlayerInNextFrame.VelY, stage.VirtualGridToWorldRatio)
+	playerCollider.Y += movementY
+	playerCollider.Update()
+	movedVirtualGrid.Y += int64(thatPlayerInNextFrame.VelY)
+}
+
+/*
+A one-way platform only pushes a falling player upwards, and only by no more than the falling distance of this render frame (plus a virtual grid unit for rounding), i.e. the player was above the platform in the previous render frame. Otherwise the player passes through it, e.g. jumping from below or walking in from a side.
+
+Both "pushbackX" and "pushbackY" are in virtual grid units.
+*/
+func landsOnOneWayPlatform(thatPlayerInNextFrame *PlayerDownsync, pushbackX, pushbackY float64) bool {
+	if 0 < thatPlayerInNextFrame.VelY || 0 != pushbackX || 0 <= pushbackY {
+		return false
+	}
+	return -pushbackY <= float64(-thatPlayerInNextFrame.VelY+1)
+}
+
+/*
+Accumulates into "effPushbacks" the pushbacks by "resolv" on each player collider already moved in "Step", in world coordinates.
+
+When "stage.PlayerCollisionEnabled", each of an overlapped pair of players takes half of the pushback, which is symmetric and independent of the traversal order because no collider is moved until all "effPushbacks" are accumulated. Otherwise players pass through each other.
+*/
+func (pS *Simulator) calcEffPushbacks(currPlayers, nextPlayers []*PlayerDownsync, effPushbacks []Vec2D) {
+	stage := pS.stage
+	for i, currPlayerDownsync := range currPlayers {
+		if nil == currPlayerDownsync || ATK_CHARACTER_STATE_KO == currPlayerDownsync.CharacterState {
+			continue
+		}
+		playerCollider := pS.playerColliders[i]
+		if collision := playerCollider.Check(0, 0); collision != nil {
+			playerShape := playerCollider.Shape.(*resolv.ConvexPolygon)
+			for _, obj := range collision.Objects {
+				barrierShape := obj.Shape.(*resolv.ConvexPolygon)
+				if obj.HasTags("Player") && !stage.PlayerCollisionEnabled {
+					continue
+				}
+				if overlapped, pushbackX, pushbackY, overlapResult := CalcPushbacks(0, 0, playerShape, barrierShape); overlapped {
+					if obj.HasTags("Player") {
+						pushbackX, pushbackY = pushbackX*0.5, pushbackY*0.5
+					}
+					if obj.HasTags("OneWayPlatform") && !landsOnOneWayPlatform(nextPlayers[i], pushbackX*stage.WorldToVirtualGridRatio, pushbackY*stage.WorldToVirtualGridRatio) {
+						continue
+					}
+					Logger.Debug(fmt.Sprintf("Overlapped: a=%v, b=%v, pushbackX=%v, pushbackY=%v", ConvexPolygonStr(playerShape), ConvexPolygonStr(barrierShape), pushbackX, pushbackY))
+					effPushbacks[i].X += pushbackX
+					effPushbacks[i].Y += pushbackY
+				} else {
+					Logger.Debug(fmt.Sprintf("Collided BUT not overlapped: a=%v, b=%v, overlapResult=%v", ConvexPolygonStr(playerShape), ConvexPolygonStr(barrierShape), overlapResult))
+				}
+			}
+		}
+	}
+}
+
+/*
+The "stage.FixedPointCollisionEnabled" counterpart of "calcEffPushbacks", accumulating into "effPushbacks" by "CalcPushbacksInVirtualGrid" against all barriers, one-way platforms and other players if "stage.PlayerCollisionEnabled". The halved pushback between a pair of players is truncated towards zero, thus still symmetric. There's deliberately no broadphase, because the cells of a "resolv.Space" are indexed by floating point positions.
+*/
+func (pS *Simulator) calcEffPushbacksInVirtualGrid(currPlayers, nextPlayers []*PlayerDownsync, movedVirtualGrids []IntVec2D, effPushbacks []IntVec2D) {
+	stage := pS.stage
+	pS.resetPlayerPolygons(currPlayers, movedVirtualGrids)
+
+	for i, currPlayerDownsync := range currPlayers {
+		if nil == currPlayerDownsync || ATK_CHARACTER_STATE_KO == currPlayerDownsync.CharacterState {
+			continue
+		}
+		playerPolygon := pS.playerPolygons[i]
+		for _, barrierPolygon := range pS.barrierPolygons {
+			if overlapped, pushbackX, pushbackY := CalcPushbacksInVirtualGrid(playerPolygon, barrierPolygon); overlapped {
+				effPushbacks[i].X += int64(pushbackX)
+				effPushbacks[i].Y += int64(pushbackY)
+			}
+		}
+		for _, oneWayPlatformPolygon := range pS.oneWayPlatformPolygons {
+			if overlapped, pushbackX, pushbackY := CalcPushbacksInVirtualGrid(playerPolygon, oneWayPlatformPolygon); overlapped && landsOnOneWayPlatform(nextPlayers[i], float64(pushbackX), float64(pushbackY)) {
+				effPushbacks[i].X += int64(pushbackX)
+				effPushbacks[i].Y += int64(pushbackY)
+			}
+		}
+		if !stage.PlayerCollisionEnabled {
+			continue
+		}
+		for j, otherPlayerDownsync := range currPlayers {
+			if i == j || nil == otherPlayerDownsync || ATK_CHARACTER_STATE_KO == otherPlayerDownsync.CharacterState {
+				continue
+			}
+			if overlapped, pushbackX, pushbackY := CalcPushbacksInVirtualGrid(playerPolygon, pS.playerPolygons[j]); overlapped {
+				effPushbacks[i].X += int64(pushbackX / 2)
+				effPushbacks[i].Y += int64(pushbackY / 2)
+			}
+		}
+	}
+}
+
+// Resets "pS.playerPolygons" centered at "virtualGrids", except for knocked-out players who have no collider.
+func (pS *Simulator) resetPlayerPolygons(currPlayers []*PlayerDownsync, virtualGrids []IntVec2D) {
+	for i, currPlayerDownsync := range currPlayers {
+		if nil == currPlayerDownsync || ATK_CHARACTER_STATE_KO == currPlayerDownsync.CharacterState {
+			continue
+		}
+		halfW, halfH := WorldToVirtualGridPos(currPlayerDownsync.ColliderRadius, currPlayerDownsync.ColliderRadius, pS.stage.WorldToVirtualGridRatio)
+		SetIntRectPolygon2D(pS.playerPolygons[i], virtualGrids[i].X, virtualGrids[i].Y, int64(halfW), int64(halfH))
+	}
+}
+
+/*
+Returns true if players of at most one team are not knocked out in "rdf", where each team is a side, see "TeamIdOf". Always false when knocked-out players respawn, i.e. "0 < stage.KoRespawnFrames".
+*/
+func IsDecidedByKo(stage *Stage, rdf *RoomDownsyncFrame) bool {
+	if 0 < stage.KoRespawnFrames {
+		return false
+	}
+	teams := make(map[int32]bool, len(rdf.Players))
+	aliveTeams := make(map[int32]bool, len(rdf.Players))
+	for _, player := range rdf.Players {
+		teamId := EffectiveTeamIdOf(player)
+		teams[teamId] = true
+		if ATK_CHARACTER_STATE_KO != player.CharacterState {
+			aliveTeams[teamId] = true
+		}
+	}
+	return 1 >= len(aliveTeams) && 1 < len(teams)
+}
+
+// A defender blocks if it's holding the guard, not in any recovery other than a previous block stun, and facing the bullet source at "fromVirtualGridX" by "DirX".
+func isBlocking(defender *PlayerDownsync, fromVirtualGridX int32, guardLevel int32) bool {
+	if 0 >= guardLevel {
+		return false
+	}
+	if 0 < defender.FramesToRecover && ATK_CHARACTER_STATE_BLOCKED != defender.CharacterState {
+		return false
+	}
+	dx := fromVirtualGridX - defender.VirtualGridX
+	return (0 < dx && 0 < defender.DirX) || (0 > dx && 0 > defender.DirX)
+}
+
+/*
+Overrides the recovery of the offender by "recoveryFramesOnConnection" counted from "meleeBullet.OriginatedRenderFrameId", i.e. "RecoveryFramesOnHit" or "RecoveryFramesOnBlock" instead of "RecoveryFrames" which only applies to a whiffed bullet.
+*/
+func onMeleeBulletConnected(meleeBullet *MeleeBullet, recoveryFramesOnConnection int32, offenderInNextFrame *PlayerDownsync, currRenderFrame *RoomDownsyncFrame) {
+	if ATK_CHARACTER_STATE_ATK1 != offenderInNextFrame.CharacterState {
+		// The offender is already hit or knocked out by another bullet
+		return
+	}
+	framesToRecover := recoveryFramesOnConnection - (currRenderFrame.Id - meleeBullet.OriginatedRenderFrameId)
+	if 0 > framesToRecover {
+		framesToRecover = 0
+	}
+	offenderInNextFrame.FramesToRecover = framesToRecover
+}
+
+// Appends a clone of "skillConfig" to "toRet.FireballBullets", which is launched after "StartupFrames", see "Step".
+func fireFireballSkill(skillConfig *FireballBullet, currPlayerDownsync, thatPlayerInNextFrame *PlayerDownsync, currRenderFrame, toRet *RoomDownsyncFrame) {
+	newFireball := proto.Clone(skillConfig).(*FireballBullet)
+	newFireball.BattleLocalId = toRet.BulletLocalIdCounter
+	toRet.BulletLocalIdCounter += 1
+	newFireball.OffenderJoinIndex = currPlayerDownsync.JoinIndex
+	newFireball.OffenderPlayerId = currPlayerDownsync.Id
+	newFireball.OriginatedRenderFrameId = currRenderFrame.Id
+	toRet.FireballBullets = append(toRet.FireballBullets, newFireball)
+	thatPlayerInNextFrame.FramesToRecover = newFireball.RecoveryFrames
+	thatPlayerInNextFrame.CharacterState = ATK_CHARACTER_STATE_ATK1
+}
+
+/*
+Returns true if the launched "fireball" hits any player other than its offender or any barrier, in which case it should be destroyed. The collider is removed right away due to the need for being rollback-compatible.
+*/
+func (pS *Simulator) checkFireballCollision(fireball *FireballBullet, currRenderFrame *RoomDownsyncFrame, currPlayers, nextPlayers []*PlayerDownsync, guardLevels []int32, bulletPushbacks []IntVec2D) bool {
+	stage := pS.stage
+	xfac := float64(1.0)
+	if 0 > fireball.VelX {
+		xfac = float64(-1.0)
+	}
+	if stage.FixedPointCollisionEnabled {
+		SetIntRectPolygon2D(pS.bulletPolygon, int64(fireball.VirtualGridX), int64(fireball.VirtualGridY), int64(fireball.HitboxSizeX/2), int64(fireball.HitboxSizeY/2))
+		destroyed := false
+		for i, currPlayerDownsync := range currPlayers {
+			if nil == currPlayerDownsync || ATK_CHARACTER_STATE_KO == currPlayerDownsync.CharacterState {
+				continue
+			}
+			if overlapped, _, _ := CalcPushbacksInVirtualGrid(pS.bulletPolygon, pS.playerPolygons[i]); overlapped && onFireballHit(stage, fireball, currPlayerDownsync, xfac, nextPlayers, guardLevels, bulletPushbacks, currRenderFrame) {
+				destroyed = true
+			}
+		}
+		for _, barrierPolygon := range pS.barrierPolygons {
+			if overlapped, _, _ := CalcPushbacksInVirtualGrid(pS.bulletPolygon, barrierPolygon); overlapped {
+				Logger.Debug(fmt.Sprintf("A fireball of playerId=%v is destroyed by a barrier at currRenderFrame.id=%v", fireball.OffenderPlayerId, currRenderFrame.Id))
+				destroyed = true
+			}
+		}
+		return destroyed
+	}
+
+	wx, wy := VirtualGridToWorldPos(fireball.VirtualGridX, fireball.VirtualGridY, stage.VirtualGridToWorldRatio)
+	ww, wh := VirtualGridToWorldPos(fireball.HitboxSizeX, fireball.HitboxSizeY, stage.VirtualGridToWorldRatio)
+	fireballCollider := GenerateRectCollider(wx, wy, ww, wh, stage.SpaceOffsetX, stage.SpaceOffsetY, "FireballBullet")
+	pS.space.Add(fireballCollider)
+	defer pS.space.Remove(fireballCollider)
+
+	collision := fireballCollider.Check(0, 0)
+	if nil == collision {
+		return false
+	}
+	fireballShape := fireballCollider.Shape.(*resolv.ConvexPolygon)
+	destroyed := false
+	for _, obj := range collision.Objects {
+		if overlapped, _, _, _ := CalcPushbacks(0, 0, fireballShape, obj.Shape.(*resolv.ConvexPolygon)); !overlapped {
+			continue
+		}
+		switch t := obj.Data.(type) {
+		case *PlayerDownsync:
+			if onFireballHit(stage, fireball, t, xfac, nextPlayers, guardLevels, bulletPushbacks, currRenderFrame) {
+				destroyed = true
+			}
+		default:
+			if obj.HasTags("Barrier") {
+				Logger.Debug(fmt.Sprintf("A fireball of playerId=%v is destroyed by a barrier at currRenderFrame.id=%v: %v", fireball.OffenderPlayerId, currRenderFrame.Id, ConvexPolygonStr(fireballShape)))
+				destroyed = true
+			}
+		}
+	}
+	return destroyed
+}
+
+// Returns false if the "fireball" passes through "defender", i.e. its offender or a teammate of the offender.
+func onFireballHit(stage *Stage, fireball *FireballBullet, defender *PlayerDownsync, xfac float64, nextPlayers []*PlayerDownsync, guardLevels []int32, bulletPushbacks []IntVec2D, currRenderFrame *RoomDownsyncFrame) bool {
+	offender := currRenderFrame.Players[fireball.OffenderPlayerId]
+	if fireball.OffenderPlayerId == defender.Id || (nil != offender && !stage.CanHit(offender, defender)) {
+		return false
+	}
+	var offenderInNextFrame *PlayerDownsync = nil
+	if nil != offender {
+		offenderInNextFrame = nextPlayers[offender.JoinIndex-1]
+	}
+	applyBulletImpact(stage, fireball, fireball.Pushback, offenderInNextFrame, defender, nextPlayers[defender.JoinIndex-1], isBlocking(defender, fireball.VirtualGridX, guardLevels[defender.JoinIndex-1]), xfac, &bulletPushbacks[defender.JoinIndex-1], currRenderFrame.Id)
+	return true
+}
+
+/*
+The "stage.FixedPointCollisionEnabled" counterpart of checking a "meleeBullet" collider by "resolv", returns true if it overlaps any player, including its offender, or any barrier, in which case it should be removed.
+*/
+func (pS *Simulator) checkMeleeBulletCollisionInVirtualGrid(meleeBullet *MeleeBullet, offender *PlayerDownsync, xfac float64, currPlayers, nextPlayers []*PlayerDownsync, guardLevels []int32, bulletPushbacks []IntVec2D, currRenderFrame *RoomDownsyncFrame) bool {
+	stage := pS.stage
+	// Only the configs are converted, each rounded once the same as "Polygon2DToIntPolygon2D"
+	hitboxOffset, _ := WorldToVirtualGridPos(meleeBullet.HitboxOffset, 0, stage.WorldToVirtualGridRatio)
+	hitboxSizeX, hitboxSizeY := WorldToVirtualGridPos(meleeBullet.HitboxSize.X, meleeBullet.HitboxSize.Y, stage.WorldToVirtualGridRatio)
+	SetIntRectPolygon2D(pS.bulletPolygon, int64(offender.VirtualGridX)+int64(xfac)*int64(hitboxOffset), int64(offender.VirtualGridY), int64(hitboxSizeX/2), int64(hitboxSizeY/2))
+
+	collided := false
+	for i, currPlayerDownsync := range currPlayers {
+		if nil == currPlayerDownsync || ATK_CHARACTER_STATE_KO == currPlayerDownsync.CharacterState {
+			continue
+		}
+		if overlapped, _, _ := CalcPushbacksInVirtualGrid(pS.bulletPolygon, pS.playerPolygons[i]); !overlapped {
+			continue
+		}
+		collided = true
+		if stage.CanHit(offender, currPlayerDownsync) {
+			onMeleeBulletHit(stage, meleeBullet, offender, currPlayerDownsync, xfac, nextPlayers, guardLevels, bulletPushbacks, currRenderFrame)
+			Logger.Debug(fmt.Sprintf("A meleeBullet collides w/ playerId=%v at currRenderFrame.id=%v", currPlayerDownsync.Id, currRenderFrame.Id))
+		}
+	}
+	for _, barrierPolygon := range pS.barrierPolygons {
+		if overlapped, _, _ := CalcPushbacksInVirtualGrid(pS.bulletPolygon, barrierPolygon); overlapped {
+			collided = true
+		}
+	}
+	return collided
+}
+
+func onMeleeBulletHit(stage *Stage, meleeBullet *MeleeBullet, offender, defender *PlayerDownsync, xfac float64, nextPlayers []*PlayerDownsync, guardLevels []int32, bulletPushbacks []IntVec2D, currRenderFrame *RoomDownsyncFrame) {
+	pushback, _ := WorldToVirtualGridPos(meleeBullet.Pushback, 0, stage.WorldToVirtualGridRatio)
+	if blocked := applyBulletImpact(stage, meleeBullet, pushback, nextPlayers[offender.JoinIndex-1], defender, nextPlayers[defender.JoinIndex-1], isBlocking(defender, offender.VirtualGridX, guardLevels[defender.JoinIndex-1]), xfac, &bulletPushbacks[defender.JoinIndex-1], currRenderFrame.Id); blocked {
+		onMeleeBulletConnected(meleeBullet, meleeBullet.RecoveryFramesOnBlock, nextPlayers[offender.JoinIndex-1], currRenderFrame)
+	} else {
+		onMeleeBulletConnected(meleeBullet, meleeBullet.RecoveryFramesOnHit, nextPlayers[offender.JoinIndex-1], currRenderFrame)
+	}
+}
+
+// Implemented by both "MeleeBullet" and "FireballBullet" by their generated getters.
+type bulletImpact interface {
+	GetOffenderPlayerId() int32
+	GetHitStunFrames() int32
+	GetBlockStunFrames() int32
+	GetDamage() int32
+}
+
+// Returns "blocking", i.e. whether the defender takes block stun and reduced pushback instead of hit stun, pushback and damage. The "pushback" is in virtual grid units, and the "offenderInNextFrame" is nil if the offender of a launched fireball is gone.
+func applyBulletImpact(stage *Stage, bullet bulletImpact, pushback int32, offenderInNextFrame, defender, defenderInNextFrame *PlayerDownsync, blocking bool, xfac float64, defenderBulletPushback *IntVec2D, currRenderFrameId int32) bool {
+	if ATK_CHARACTER_STATE_KO == defenderInNextFrame.CharacterState {
+		// Already knocked out by another bullet in the same render frame
+		return false
+	}
+	if blocking {
+		defenderBulletPushback.X += int64(xfac) * int64(pushback/BLOCKED_PUSHBACK_DIVIDER)
+		defenderInNextFrame.CharacterState = ATK_CHARACTER_STATE_BLOCKED
+		if bullet.GetBlockStunFrames() > defenderInNextFrame.FramesToRecover {
+			defenderInNextFrame.FramesToRecover = bullet.GetBlockStunFrames()
+		}
+		Logger.Debug(fmt.Sprintf("A bullet of playerId=%v is blocked by playerId=%v at currRenderFrame.id=%v", bullet.GetOffenderPlayerId(), defender.Id, currRenderFrameId))
+		return true
+	}
+	defenderBulletPushback.X += int64(xfac) * int64(pushback)
+	defenderInNextFrame.CharacterState = ATK_CHARACTER_STATE_ATKED1
+	if bullet.GetHitStunFrames() > defenderInNextFrame.FramesToRecover {
+		defenderInNextFrame.FramesToRecover = bullet.GetHitStunFrames()
+	}
+	defenderInNextFrame.Hp -= bullet.GetDamage()
+	if nil != offenderInNextFrame {
+		offenderInNextFrame.HitsLanded++
+	}
+	if 0 >= defenderInNextFrame.Hp {
+		defenderInNextFrame.Hp = 0
+		defenderInNextFrame.CharacterState = ATK_CHARACTER_STATE_KO
+		defenderInNextFrame.FramesToRecover = stage.KoRespawnFrames
+		Logger.Debug(fmt.Sprintf("playerId=%v is knocked out by playerId=%v at currRenderFrame.id=%v", defender.Id, bullet.GetOffenderPlayerId(), currRenderFrameId))
+	}
+	return false
+}
@@ -0,0 +1,349 @@
+package battle
+
+import (
+	. "battle_srv/protos"
+	. "dnmshared/sharedprotos"
+	"github.com/golang/protobuf/proto"
+	"testing"
+)
+
+func newTestStage() *Stage {
+	return &Stage{
+		Capacity:     2,
+		SpaceW:       1024,
+		SpaceH:       1024,
+		SpaceOffsetX: 512,
+		SpaceOffsetY: 512,
+		CellSize:     4,
+		Barriers: []*Polygon2D{
+			&Polygon2D{
+				Anchor: &Vec2D{X: 100, Y: -50},
+				Points: []*Vec2D{&Vec2D{X: 0, Y: 0}, &Vec2D{X: 32, Y: 0}, &Vec2D{X: 32, Y: 100}, &Vec2D{X: 0, Y: 100}},
+			},
+		},
+		WorldToVirtualGridRatio:  1000,
+		VirtualGridToWorldRatio:  0.001,
+		BattleDurationNanos:      30000000000,
+		RollbackEstimatedDtNanos: 16666666,
+		MeleeSkillConfig: map[int32]*MeleeBullet{
+			1: &MeleeBullet{
+				StartupFrames:  2,
+				ActiveFrames:   3,
+				RecoveryFrames: 10,
+				HitboxOffset:   24,
+				HitboxSize:     &Vec2D{X: 45, Y: 32},
+				HitStunFrames:  6,
+				Pushback:       11,
+
+				ReleaseTriggerType: SKILL_RELEASE_TRIGGER_TYPE_RISING_EDGE,
+			},
+			2: &MeleeBullet{
+				StartupFrames:  1,
+				ActiveFrames:   2,
+				RecoveryFrames: 8,
+				HitboxOffset:   24,
+				HitboxSize:     &Vec2D{X: 45, Y: 32},
+				HitStunFrames:  6,
+				Pushback:       11,
+
+				ReleaseTriggerType: SKILL_RELEASE_TRIGGER_TYPE_FALLING_EDGE,
+			},
+		},
+		FireballSkillConfig: map[int32]*FireballBullet{
+			3: &FireballBullet{
+				StartupFrames:  1,
+				ActiveFrames:   30,
+				RecoveryFrames: 10,
+				HitboxOffset:   16000,
+				HitboxSizeX:    8000,
+				HitboxSizeY:    8000,
+				Speed:          4000,
+				HitStunFrames:  6,
+				Pushback:       4000,
+				Damage:         7,
+
+				ReleaseTriggerType: SKILL_RELEASE_TRIGGER_TYPE_RISING_EDGE,
+			},
+		},
+		CharacterSkillBindings: map[int32]*CharacterSkillBinding{
+			0: &CharacterSkillBinding{BtnToSkillId: map[string]int32{BTN_A: 1}},
+			1: &CharacterSkillBinding{BtnToSkillId: map[string]int32{BTN_A: 2}},
+			2: &CharacterSkillBinding{BtnToSkillId: map[string]int32{BTN_A: 3}},
+		},
+	}
+}
+
+func newTestKickoffFrame() *RoomDownsyncFrame {
+	return &RoomDownsyncFrame{
+		Id: 0,
+		Players: map[int32]*PlayerDownsync{
+			10: &PlayerDownsync{Id: 10, JoinIndex: 1, VirtualGridX: 0, VirtualGridY: 0, DirX: 2, Speed: 2000, ColliderRadius: 16, Hp: 100, MaxHp: 100},
+			20: &PlayerDownsync{Id: 20, JoinIndex: 2, VirtualGridX: 40000, VirtualGridY: 0, DirX: -2, Speed: 2000, ColliderRadius: 16, Hp: 100, MaxHp: 100},
+		},
+	}
+}
+
+func TestStepIsDeterministicRegardlessOfSimulatorHistory(t *testing.T) {
+	stage := newTestStage()
+	inputs := []uint64{3, 19, 4, 3, 16, 0, 3, 3, 5, 0}
+
+	// Step through all frames with a single reused simulator.
+	reused := NewSimulator(stage)
+	frames := []*RoomDownsyncFrame{newTestKickoffFrame()}
+	var prevInputFrame *InputFrameDownsync = nil
+	for i, encoded := range inputs {
+		inputFrame := &InputFrameDownsync{InputFrameId: int32(i), InputList: []uint64{encoded, inputs[len(inputs)-1-i]}}
+		frames = append(frames, reused.Step(inputFrame, prevInputFrame, frames[i]))
+		prevInputFrame = inputFrame
+	}
+
+	// Re-step each frame with a fresh simulator, as if rolled back to it.
+	prevInputFrame = nil
+	for i, encoded := range inputs {
+		inputFrame := &InputFrameDownsync{InputFrameId: int32(i), InputList: []uint64{encoded, inputs[len(inputs)-1-i]}}
+		recalculated := ApplyInputFrameDownsyncDynamicsOnSingleRenderFrame(stage, inputFrame, prevInputFrame, frames[i])
+		if !proto.Equal(recalculated, frames[i+1]) {
+			t.Fatalf("frame %d differs:\nreused=%v\nfresh=%v", i+1, frames[i+1], recalculated)
+		}
+		prevInputFrame = inputFrame
+	}
+
+	if 0 == frames[len(frames)-1].BulletLocalIdCounter {
+		t.Fatalf("expected at least one bullet to be fired")
+	}
+}
+
+func TestSkillIsFiredUponItsReleaseTriggerType(t *testing.T) {
+	stage := newTestStage()
+	kickoffFrame := newTestKickoffFrame()
+	kickoffFrame.Players[20].SpeciesId = 1 // bound to the falling-edge skill
+
+	pressed := &InputFrameDownsync{InputFrameId: 0, InputList: []uint64{16, 16}}
+	released := &InputFrameDownsync{InputFrameId: 1, InputList: []uint64{0, 0}}
+	afterPress := ApplyInputFrameDownsyncDynamicsOnSingleRenderFrame(stage, pressed, nil, kickoffFrame)
+	if 1 != len(afterPress.MeleeBullets) || 1 != afterPress.MeleeBullets[0].OffenderJoinIndex {
+		t.Fatalf("expected only the rising-edge skill of joinIndex=1 upon pressing, got %v", afterPress.MeleeBullets)
+	}
+
+	afterRelease := ApplyInputFrameDownsyncDynamicsOnSingleRenderFrame(stage, released, pressed, afterPress)
+	fired := false
+	for _, meleeBullet := range afterRelease.MeleeBullets {
+		if 2 == meleeBullet.OffenderJoinIndex {
+			fired = true
+		}
+	}
+	if !fired {
+		t.Fatalf("expected the falling-edge skill of joinIndex=2 upon releasing, got %v", afterRelease.MeleeBullets)
+	}
+}
+
+func TestKnockedOutPlayerIsEliminatedOrRespawned(t *testing.T) {
+	for _, koRespawnFrames := range []int32{0, 4} {
+		stage := newTestStage()
+		stage.KoRespawnFrames = koRespawnFrames
+		stage.MeleeSkillConfig[1].Damage = 5
+		stage.PlayerStartingPositions = []*Vec2D{&Vec2D{X: -100, Y: 0}, &Vec2D{X: 200, Y: 0}}
+		simulator := NewSimulator(stage)
+		rdf := newTestKickoffFrame()
+		rdf.Players[20].Hp = 5
+
+		var prevInputFrame *InputFrameDownsync = nil
+		koRenderFrameId := int32(-1)
+		for i := 0; i < 16; i++ {
+			inputFrame := &InputFrameDownsync{InputFrameId: int32(i), InputList: []uint64{16, 0}}
+			rdf = simulator.Step(inputFrame, prevInputFrame, rdf)
+			prevInputFrame = inputFrame
+			if 0 > koRenderFrameId && ATK_CHARACTER_STATE_KO == rdf.Players[20].CharacterState {
+				koRenderFrameId = rdf.Id
+				if 0 != rdf.Players[20].Hp {
+					t.Fatalf("expected Hp of a knocked-out player to be 0, got %v", rdf.Players[20].Hp)
+				}
+				if (0 == koRespawnFrames) != IsDecidedByKo(stage, rdf) {
+					t.Fatalf("unexpected IsDecidedByKo with koRespawnFrames=%v", koRespawnFrames)
+				}
+			}
+		}
+		if 0 > koRenderFrameId {
+			t.Fatalf("expected playerId=20 to be knocked out with koRespawnFrames=%v", koRespawnFrames)
+		}
+		if 0 == koRespawnFrames {
+			if ATK_CHARACTER_STATE_KO != rdf.Players[20].CharacterState {
+				t.Fatalf("expected playerId=20 to stay knocked out, got %v", rdf.Players[20])
+			}
+		} else if ATK_CHARACTER_STATE_KO == rdf.Players[20].CharacterState || 100 != rdf.Players[20].Hp || 200000 != rdf.Players[20].VirtualGridX {
+			t.Fatalf("expected playerId=20 to respawn with full Hp at its starting position, got %v", rdf.Players[20])
+		}
+	}
+}
+
+func TestGuardingPlayerFacingTheOffenderBlocks(t *testing.T) {
+	for _, fixedPointCollisionEnabled := range []bool{false, true} {
+		for _, defenderDirX := range []int32{-2, +2} {
+			stage := newTestStage()
+			stage.FixedPointCollisionEnabled = fixedPointCollisionEnabled
+			stage.MeleeSkillConfig[1].Damage = 5
+			stage.MeleeSkillConfig[1].BlockStunFrames = 3
+			simulator := NewSimulator(stage)
+			rdf := newTestKickoffFrame()
+			rdf.Players[20].DirX = defenderDirX
+
+			var prevInputFrame *InputFrameDownsync = nil
+			blocked := false
+			for i := 0; i < 8; i++ {
+				inputFrame := &InputFrameDownsync{InputFrameId: int32(i), InputList: []uint64{16, 32}}
+				rdf = simulator.Step(inputFrame, prevInputFrame, rdf)
+				prevInputFrame = inputFrame
+				if ATK_CHARACTER_STATE_BLOCKED == rdf.Players[20].CharacterState {
+					blocked = true
+				}
+			}
+			facingOffender := (0 > defenderDirX)
+			if facingOffender != blocked {
+				t.Fatalf("expected blocked=%v with defender dirX=%v and fixedPointCollisionEnabled=%v", facingOffender, defenderDirX, fixedPointCollisionEnabled)
+			}
+			if facingOffender != (100 == rdf.Players[20].Hp) {
+				t.Fatalf("expected no damage only upon blocking, got hp=%v with defender dirX=%v and fixedPointCollisionEnabled=%v", rdf.Players[20].Hp, defenderDirX, fixedPointCollisionEnabled)
+			}
+			if facingOffender != (0 == rdf.Players[10].HitsLanded) {
+				t.Fatalf("expected a hit landed only without blocking, got hitsLanded=%v with defender dirX=%v and fixedPointCollisionEnabled=%v", rdf.Players[10].HitsLanded, defenderDirX, fixedPointCollisionEnabled)
+			}
+		}
+	}
+}
+
+func TestFireballHitsPlayerOrIsDestroyedByBarrier(t *testing.T) {
+	for _, fixedPointCollisionEnabled := range []bool{false, true} {
+		// "StartupFrames == 0" is rejected by the skill configs, but still launched in the next render frame
+		for _, startupFrames := range []int32{0, 1} {
+			for _, defenderVirtualGridY := range []int32{0, 300000} {
+				stage := newTestStage()
+				stage.FixedPointCollisionEnabled = fixedPointCollisionEnabled
+				stage.FireballSkillConfig[3].StartupFrames = startupFrames
+				simulator := NewSimulator(stage)
+				rdf := newTestKickoffFrame()
+				rdf.Players[10].SpeciesId = 2
+				rdf.Players[20].VirtualGridY = defenderVirtualGridY
+
+				var prevInputFrame *InputFrameDownsync = nil
+				launched := false
+				for i := 0; i < 30; i++ {
+					inputFrame := &InputFrameDownsync{InputFrameId: int32(i), InputList: []uint64{16, 0}}
+					rdf = simulator.Step(inputFrame, prevInputFrame, rdf)
+					prevInputFrame = inputFrame
+					for _, fireball := range rdf.FireballBullets {
+						if 0 != fireball.VelX {
+							launched = true
+						}
+						if 100000 < fireball.VirtualGridX {
+							t.Fatalf("expected the fireball to be destroyed by the barrier, got %v", fireball)
+						}
+					}
+				}
+				if !launched || 0 != len(rdf.FireballBullets) {
+					t.Fatalf("expected the fireball to be launched and then destroyed, got fixedPointCollisionEnabled=%v, startupFrames=%v, launched=%v, fireballs=%v", fixedPointCollisionEnabled, startupFrames, launched, rdf.FireballBullets)
+				}
+				expectedHp, expectedHitsLanded := int32(100), int32(0)
+				if 0 == defenderVirtualGridY {
+					expectedHp -= stage.FireballSkillConfig[3].Damage
+					expectedHitsLanded = 1
+				}
+				if expectedHp != rdf.Players[20].Hp {
+					t.Fatalf("expected hp=%v of the defender at virtualGridY=%v and startupFrames=%v, got %v", expectedHp, defenderVirtualGridY, startupFrames, rdf.Players[20].Hp)
+				}
+				if expectedHitsLanded != rdf.Players[10].HitsLanded {
+					t.Fatalf("expected hitsLanded=%v of the offender with the defender at virtualGridY=%v, got %v", expectedHitsLanded, defenderVirtualGridY, rdf.Players[10].HitsLanded)
+				}
+			}
+		}
+	}
+}
+
+func TestPlatformerPlayerJumpsThroughOneWayPlatformAndLandsOnIt(t *testing.T) {
+	for _, fixedPointCollisionEnabled := range []bool{false, true} {
+		stage := newTestStage()
+		stage.BattleMode = BATTLE_MODE_PLATFORMER
+		stage.GravityY, stage.JumpingInitVelY, stage.MaxFallingVelY = 500, 10000, 8000
+		stage.FixedPointCollisionEnabled = fixedPointCollisionEnabled
+		stage.Barriers = []*Polygon2D{
+			&Polygon2D{
+				Anchor: &Vec2D{X: -200, Y: -100},
+				Points: []*Vec2D{&Vec2D{X: 0, Y: 0}, &Vec2D{X: 400, Y: 0}, &Vec2D{X: 400, Y: 20}, &Vec2D{X: 0, Y: 20}},
+			},
+		}
+		stage.OneWayPlatforms = []*Polygon2D{
+			&Polygon2D{
+				Anchor: &Vec2D{X: -50, Y: 0},
+				Points: []*Vec2D{&Vec2D{X: 0, Y: 0}, &Vec2D{X: 100, Y: 0}, &Vec2D{X: 100, Y: 4}, &Vec2D{X: 0, Y: 4}},
+			},
+		}
+		simulator := NewSimulator(stage)
+		rdf := newTestKickoffFrame()
+		rdf.Players[10].VirtualGridY = -64000 // Standing on the floor
+		rdf.Players[20].VirtualGridX, rdf.Players[20].VirtualGridY = 150000, -64000
+
+		idle := &InputFrameDownsync{InputFrameId: 0, InputList: []uint64{0, 0}}
+		rdf = simulator.Step(idle, idle, rdf)
+		if !rdf.Players[10].Grounded || -64000 != rdf.Players[10].VirtualGridY {
+			t.Fatalf("expected the player to stand on the floor, fixedPointCollisionEnabled=%v, got %v", fixedPointCollisionEnabled, rdf.Players[10])
+		}
+
+		jumping := &InputFrameDownsync{InputFrameId: 1, InputList: []uint64{1 << INPUT_BTN_D_SHIFT, 0}}
+		rdf = simulator.Step(jumping, idle, rdf)
+		if rdf.Players[10].Grounded || stage.JumpingInitVelY != rdf.Players[10].VelY || -64000 >= rdf.Players[10].VirtualGridY {
+			t.Fatalf("expected the player to take off upon a rising-edge of BtnD, fixedPointCollisionEnabled=%v, got %v", fixedPointCollisionEnabled, rdf.Players[10])
+		}
+		for i := 0; i < 60; i++ {
+			rdf = simulator.Step(jumping, jumping, rdf)
+		}
+		if !rdf.Players[10].Grounded || 20000 != rdf.Players[10].VirtualGridY {
+			t.Fatalf("expected the player to pass through the one-way platform from below and land on it, fixedPointCollisionEnabled=%v, got %v", fixedPointCollisionEnabled, rdf.Players[10])
+		}
+		if !rdf.Players[20].Grounded || -64000 != rdf.Players[20].VirtualGridY {
+			t.Fatalf("expected the other player to keep standing on the floor, fixedPointCollisionEnabled=%v, got %v", fixedPointCollisionEnabled, rdf.Players[20])
+		}
+	}
+}
+
+func TestFixedPointCollisionStopsPlayerExactlyAtBarrier(t *testing.T) {
+	stage := newTestStage()
+	stage.FixedPointCollisionEnabled = true
+	simulator := NewSimulator(stage)
+	rdf := newTestKickoffFrame()
+	rdf.Players[20].VirtualGridX = -200000 // Out of the way
+
+	walkingRight := &InputFrameDownsync{InputFrameId: 0, InputList: []uint64{3, 0}}
+	for i := 0; i < 30; i++ {
+		rdf = simulator.Step(walkingRight, walkingRight, rdf)
+	}
+	// The barrier spans [100, 132] in world x-coordinates, thus a player of "ColliderRadius == 16" stops at 84
+	if 84000 != rdf.Players[10].VirtualGridX || 0 != rdf.Players[10].VirtualGridY {
+		t.Fatalf("expected the player to stop exactly at the barrier, got %v", rdf.Players[10])
+	}
+}
+
+func TestPlayersPushEachOtherBackSymmetricallyOrPassThrough(t *testing.T) {
+	walkingTowardsEachOther := &InputFrameDownsync{InputFrameId: 0, InputList: []uint64{3, 4}}
+	for _, fixedPointCollisionEnabled := range []bool{false, true} {
+		for _, playerCollisionEnabled := range []bool{false, true} {
+			stage := newTestStage()
+			stage.FixedPointCollisionEnabled = fixedPointCollisionEnabled
+			stage.PlayerCollisionEnabled = playerCollisionEnabled
+			simulator := NewSimulator(stage)
+			rdf := newTestKickoffFrame()
+			for i := 0; i < 20; i++ {
+				rdf = simulator.Step(walkingTowardsEachOther, walkingTowardsEachOther, rdf)
+			}
+			x1, x2 := rdf.Players[10].VirtualGridX, rdf.Players[20].VirtualGridX
+			if !playerCollisionEnabled {
+				if x1 <= x2 {
+					t.Fatalf("expected the players to pass through each other, fixedPointCollisionEnabled=%v, got x1=%v, x2=%v", fixedPointCollisionEnabled, x1, x2)
+				}
+				continue
+			}
+			// Both players started 40 apart and moved at the same speed, thus should be touching each other around the midpoint
+			if sum, gap := x1+x2, x2-x1; 1 < sum-40000 || -1 > sum-40000 || 1 < gap-32000 || -1 > gap-32000 {
+				t.Fatalf("expected the players to be pushed back symmetrically, fixedPointCollisionEnabled=%v, got x1=%v, x2=%v", fixedPointCollisionEnabled, x1, x2)
+			}
+		}
+	}
+}
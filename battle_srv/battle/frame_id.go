@@ -0,0 +1,77 @@
+package battle
+
+import (
+	. "battle_srv/protos"
+	"math"
+)
+
+func ConvertToInputFrameId(renderFrameId int32, inputDelayFrames int32, inputScaleFrames uint32) int32 {
+	// Specifically when "renderFrameId < inputDelayFrames", the result is negative, i.e. no inputFrame is used.
+	return ((renderFrameId - inputDelayFrames) >> inputScaleFrames)
+}
+
+func ConvertToGeneratingRenderFrameId(inputFrameId int32, inputScaleFrames uint32) int32 {
+	return (inputFrameId << inputScaleFrames)
+}
+
+func ConvertToFirstUsedRenderFrameId(inputFrameId int32, inputDelayFrames int32, inputScaleFrames uint32) int32 {
+	return ((inputFrameId << inputScaleFrames) + inputDelayFrames)
+}
+
+func ConvertToLastUsedRenderFrameId(inputFrameId int32, inputDelayFrames int32, inputScaleFrames uint32) int32 {
+	return ((inputFrameId << inputScaleFrames) + inputDelayFrames + (1 << inputScaleFrames) - 1)
+}
+
+/*
+The following "Scheduled" variants take the "InputDelayFramesSwitch"es renegotiated during a battle into account. For a renderFrameId within "[switches[j].FromRenderFrameId, switches[j+1].FromRenderFrameId)", the delayed inputFrameId is calculated by "switches[j].InputDelayFrames" but never less than that of "switches[j].FromRenderFrameId-1", i.e.
+- upon an increased delay, the last used inputFrame is held (instead of re-applying the older ones) until the new delay catches up, and
+- upon a decreased delay, some inputFrames are skipped,
+such that the delayed inputFrameId is always non-decreasing w.r.t. renderFrameId. With empty "switches" they're identical to the non-scheduled ones.
+*/
+func ConvertToScheduledInputFrameId(renderFrameId int32, inputDelayFrames int32, switches []*InputDelayFramesSwitch, inputScaleFrames uint32) int32 {
+	held := int32(math.MinInt32)
+	for _, theSwitch := range switches {
+		if renderFrameId < theSwitch.FromRenderFrameId {
+			break
+		}
+		held = maxInt32(ConvertToInputFrameId(theSwitch.FromRenderFrameId-1, inputDelayFrames, inputScaleFrames), held)
+		inputDelayFrames = theSwitch.InputDelayFrames
+	}
+	return maxInt32(ConvertToInputFrameId(renderFrameId, inputDelayFrames, inputScaleFrames), held)
+}
+
+func ConvertToScheduledFirstUsedRenderFrameId(inputFrameId int32, inputDelayFrames int32, switches []*InputDelayFramesSwitch, inputScaleFrames uint32) int32 {
+	// If "inputFrameId" is skipped, it's the first renderFrameId using a later inputFrame
+	return ConvertToScheduledLastUsedRenderFrameId(inputFrameId-1, inputDelayFrames, switches, inputScaleFrames) + 1
+}
+
+// The last renderFrameId whose delayed inputFrameId is no larger than "inputFrameId".
+func ConvertToScheduledLastUsedRenderFrameId(inputFrameId int32, inputDelayFrames int32, switches []*InputDelayFramesSwitch, inputScaleFrames uint32) int32 {
+	// The delayed inputFrameId at "switches[j].FromRenderFrameId-1", i.e. the least one used by "switches[j]", is "helds[j]"
+	helds := make([]int32, len(switches))
+	held, delay := int32(math.MinInt32), inputDelayFrames
+	for j, theSwitch := range switches {
+		held = maxInt32(ConvertToInputFrameId(theSwitch.FromRenderFrameId-1, delay, inputScaleFrames), held)
+		helds[j] = held
+		delay = theSwitch.InputDelayFrames
+	}
+	for j := len(switches) - 1; 0 <= j; j-- {
+		if helds[j] > inputFrameId {
+			continue
+		}
+		candidate := ConvertToLastUsedRenderFrameId(inputFrameId, switches[j].InputDelayFrames, inputScaleFrames)
+		if candidate < switches[j].FromRenderFrameId {
+			// Only "helds[j]" used up to "switches[j].FromRenderFrameId-1" is no larger than "inputFrameId"
+			return switches[j].FromRenderFrameId - 1
+		}
+		return candidate
+	}
+	return ConvertToLastUsedRenderFrameId(inputFrameId, inputDelayFrames, inputScaleFrames)
+}
+
+func maxInt32(a, b int32) int32 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,44 @@
+package battle
+
+import (
+	. "battle_srv/protos"
+	"testing"
+)
+
+func TestScheduledConversionsMatchBruteForce(t *testing.T) {
+	inputScaleFrames := uint32(2)
+	switches := []*InputDelayFramesSwitch{
+		&InputDelayFramesSwitch{FromRenderFrameId: 40, InputDelayFrames: 16}, // increased
+		&InputDelayFramesSwitch{FromRenderFrameId: 80, InputDelayFrames: 4},  // decreased
+		&InputDelayFramesSwitch{FromRenderFrameId: 120, InputDelayFrames: 8},
+	}
+	lastRenderFrameId := int32(200)
+	inputFrameIds := make([]int32, lastRenderFrameId+1)
+	for renderFrameId := int32(0); renderFrameId <= lastRenderFrameId; renderFrameId++ {
+		inputFrameIds[renderFrameId] = ConvertToScheduledInputFrameId(renderFrameId, 8, switches, inputScaleFrames)
+		if 0 < renderFrameId && inputFrameIds[renderFrameId] < inputFrameIds[renderFrameId-1] {
+			t.Fatalf("delayed inputFrameId decreases at renderFrameId=%v", renderFrameId)
+		}
+	}
+	if expected := ConvertToInputFrameId(79, 16, inputScaleFrames); expected != inputFrameIds[79] {
+		t.Fatalf("expected %v at renderFrameId=79, got %v", expected, inputFrameIds[79])
+	}
+
+	for inputFrameId := int32(0); inputFrameId < inputFrameIds[lastRenderFrameId-32]; inputFrameId++ {
+		expectedLastUsed, expectedFirstUsed := int32(-1), int32(-1)
+		for renderFrameId := int32(0); renderFrameId <= lastRenderFrameId; renderFrameId++ {
+			if inputFrameIds[renderFrameId] <= inputFrameId {
+				expectedLastUsed = renderFrameId
+			}
+			if -1 == expectedFirstUsed && inputFrameIds[renderFrameId] >= inputFrameId {
+				expectedFirstUsed = renderFrameId
+			}
+		}
+		if actual := ConvertToScheduledLastUsedRenderFrameId(inputFrameId, 8, switches, inputScaleFrames); expectedLastUsed != actual {
+			t.Fatalf("lastUsed of inputFrameId=%v, expected=%v, actual=%v", inputFrameId, expectedLastUsed, actual)
+		}
+		if actual := ConvertToScheduledFirstUsedRenderFrameId(inputFrameId, 8, switches, inputScaleFrames); expectedFirstUsed != actual {
+			t.Fatalf("firstUsed of inputFrameId=%v, expected=%v, actual=%v", inputFrameId, expectedFirstUsed, actual)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package battle
+
+import (
+	. "battle_srv/protos"
+)
+
+/*
+The layout of an encoded input of version "INPUT_ENCODING_VERSION", from the lowest bit.
+
+- bits 0~3: the index of "DIRECTION_DECODER"
+- bit 4: BtnA
+- bit 5: guard
+- bit 6: BtnB
+- bit 7: BtnC
+- bit 8: BtnD, i.e. dash/jump
+
+Version 1 only had the direction, BtnA and guard bits. Bump the version whenever any existing bit is reassigned, a client of another version is rejected by "ws.Serve" instead of having its inputs misinterpreted.
+*/
+const (
+	INPUT_ENCODING_VERSION = 2
+
+	INPUT_DIRECTION_MASK = uint64(15)
+
+	INPUT_BTN_A_SHIFT = 4
+	INPUT_GUARD_SHIFT = 5
+	INPUT_BTN_B_SHIFT = 6
+	INPUT_BTN_C_SHIFT = 7
+	INPUT_BTN_D_SHIFT = 8
+
+	INPUT_DEFINED_BITS_MASK = uint64((1 << (INPUT_BTN_D_SHIFT + 1)) - 1)
+
+	/*
+	   The bits carried forward to predict a non-confirmed input by the previous one, i.e. the held levels. Unlike masking every button out, a held button then never has an edge generated by prediction, e.g. a phantom falling-edge attack of a lagging player who's holding it, and a guarding player keeps guarding instead of taking full hits.
+
+	   [WARNING] MUST be the same as that of "getCachedInputFrameDownsyncWithPrediction" in "Map.js" of the frontend.
+	*/
+	INPUT_PREDICTED_BITS_MASK = INPUT_DIRECTION_MASK | uint64(1<<INPUT_GUARD_SHIFT) | uint64(1<<INPUT_BTN_A_SHIFT) | uint64(1<<INPUT_BTN_B_SHIFT) | uint64(1<<INPUT_BTN_C_SHIFT) | uint64(1<<INPUT_BTN_D_SHIFT)
+)
+
+const (
+	// The keys of "CharacterSkillBinding.BtnToSkillId"
+	BTN_A = "BtnA"
+	BTN_B = "BtnB"
+	BTN_C = "BtnC"
+	BTN_D = "BtnD"
+)
+
+// The buttons to which a skill can be bound, in the order of precedence when edges of several buttons are triggered in a same inputFrame.
+var SKILL_BTNS = []string{BTN_A, BTN_B, BTN_C, BTN_D}
+
+/*
+Whether "encodedInput" is composed only of the layout above, i.e. a direction within "DIRECTION_DECODER" and no undefined bit.
+*/
+func IsValidInput(encodedInput uint64) bool {
+	if 0 != (encodedInput &^ INPUT_DEFINED_BITS_MASK) {
+		return false
+	}
+	return (encodedInput & INPUT_DIRECTION_MASK) < uint64(len(DIRECTION_DECODER))
+}
+
+/*
+[WARNING] An invalid direction is decoded as no movement instead of panicking, an upsynced one is rejected by "Room.OnBattleCmdReceived" anyway.
+*/
+func DecodeInput(encodedInput uint64) *InputFrameDecoded {
+	encodedDirection := (encodedInput & INPUT_DIRECTION_MASK)
+	if encodedDirection >= uint64(len(DIRECTION_DECODER)) {
+		encodedDirection = 0
+	}
+	return &InputFrameDecoded{
+		Dx:         DIRECTION_DECODER[encodedDirection][0],
+		Dy:         DIRECTION_DECODER[encodedDirection][1],
+		BtnALevel:  int32((encodedInput >> INPUT_BTN_A_SHIFT) & 1),
+		GuardLevel: int32((encodedInput >> INPUT_GUARD_SHIFT) & 1),
+		BtnBLevel:  int32((encodedInput >> INPUT_BTN_B_SHIFT) & 1),
+		BtnCLevel:  int32((encodedInput >> INPUT_BTN_C_SHIFT) & 1),
+		BtnDLevel:  int32((encodedInput >> INPUT_BTN_D_SHIFT) & 1),
+	}
+}
+
+// Returns the level of "btn" in "SKILL_BTNS", or 0 for a nil "decodedInput", e.g. no previous inputFrame.
+func BtnLevel(decodedInput *InputFrameDecoded, btn string) int32 {
+	if nil == decodedInput {
+		return 0
+	}
+	switch btn {
+	case BTN_A:
+		return decodedInput.BtnALevel
+	case BTN_B:
+		return decodedInput.BtnBLevel
+	case BTN_C:
+		return decodedInput.BtnCLevel
+	case BTN_D:
+		return decodedInput.BtnDLevel
+	default:
+		return 0
+	}
+}
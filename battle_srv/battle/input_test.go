@@ -0,0 +1,55 @@
+package battle
+
+import (
+	. "battle_srv/protos"
+	"testing"
+)
+
+func TestDecodeInputKeepsDirectionAndBtnABits(t *testing.T) {
+	for dirIndex := range DIRECTION_DECODER {
+		encoded := uint64(dirIndex) + (1 << INPUT_BTN_A_SHIFT) + (1 << INPUT_BTN_C_SHIFT)
+		decoded := DecodeInput(encoded)
+		if DIRECTION_DECODER[dirIndex][0] != decoded.Dx || DIRECTION_DECODER[dirIndex][1] != decoded.Dy {
+			t.Fatalf("direction#%v decoded as (%v, %v)", dirIndex, decoded.Dx, decoded.Dy)
+		}
+		if 1 != decoded.BtnALevel || 0 != decoded.GuardLevel || 0 != decoded.BtnBLevel || 1 != decoded.BtnCLevel || 0 != decoded.BtnDLevel {
+			t.Fatalf("unexpected button levels decoded from %v: %v", encoded, decoded)
+		}
+	}
+}
+
+func TestInvalidDirectionIsRejectedAndDecodedAsNoMovement(t *testing.T) {
+	for encodedDirection := uint64(len(DIRECTION_DECODER)); encodedDirection <= INPUT_DIRECTION_MASK; encodedDirection++ {
+		encoded := encodedDirection + (1 << INPUT_BTN_A_SHIFT)
+		if IsValidInput(encoded) {
+			t.Fatalf("direction#%v accepted", encodedDirection)
+		}
+		decoded := DecodeInput(encoded)
+		if 0 != decoded.Dx || 0 != decoded.Dy || 1 != decoded.BtnALevel {
+			t.Fatalf("direction#%v decoded as %v", encodedDirection, decoded)
+		}
+	}
+	if IsValidInput(1 << (INPUT_BTN_D_SHIFT + 1)) {
+		t.Fatalf("undefined bit accepted")
+	}
+	if !IsValidInput(uint64(len(DIRECTION_DECODER)-1) + (1 << INPUT_BTN_D_SHIFT)) {
+		t.Fatalf("valid input rejected")
+	}
+}
+
+func TestSkillBoundToBtnBIsFiredUponItsEdge(t *testing.T) {
+	stage := newTestStage()
+	stage.CharacterSkillBindings[0].BtnToSkillId[BTN_B] = 2 // falling-edge
+	kickoffFrame := newTestKickoffFrame()
+
+	pressed := &InputFrameDownsync{InputFrameId: 0, InputList: []uint64{1 << INPUT_BTN_B_SHIFT, 0}}
+	released := &InputFrameDownsync{InputFrameId: 1, InputList: []uint64{0, 0}}
+	afterPress := ApplyInputFrameDownsyncDynamicsOnSingleRenderFrame(stage, pressed, nil, kickoffFrame)
+	if 0 != len(afterPress.MeleeBullets) {
+		t.Fatalf("expected no bullet upon pressing BtnB bound to a falling-edge skill, got %v", afterPress.MeleeBullets)
+	}
+	afterRelease := ApplyInputFrameDownsyncDynamicsOnSingleRenderFrame(stage, released, pressed, afterPress)
+	if 1 != len(afterRelease.MeleeBullets) || SKILL_RELEASE_TRIGGER_TYPE_FALLING_EDGE != afterRelease.MeleeBullets[0].ReleaseTriggerType {
+		t.Fatalf("expected the falling-edge skill upon releasing BtnB, got %v", afterRelease.MeleeBullets)
+	}
+}
@@ -0,0 +1,115 @@
+package battle
+
+import (
+	. "battle_srv/protos"
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"io"
+)
+
+const (
+	REPLAY_VERSION = int32(1)
+)
+
+/*
+A replay file is a sequence of length-delimited protobuf messages, i.e. each message is prefixed by its byte length as a varint, the same as "writeDelimitedTo" of protobuf-java.
+
+- The first message is a "BattleReplayHeader".
+- All the following messages are all-confirmed "InputFrameDownsync"s with consecutive "InputFrameId"s starting from 0.
+*/
+func WriteReplay(w io.Writer, header *BattleReplayHeader, inputFrames []*InputFrameDownsync) error {
+	if err := writeDelimited(w, header); nil != err {
+		return err
+	}
+	for _, inputFrame := range inputFrames {
+		if err := writeDelimited(w, inputFrame); nil != err {
+			return err
+		}
+	}
+	return nil
+}
+
+func ReadReplay(r io.Reader) (*BattleReplayHeader, []*InputFrameDownsync, error) {
+	br := bufio.NewReader(r)
+	header := &BattleReplayHeader{}
+	if err := readDelimited(br, header); nil != err {
+		return nil, nil, err
+	}
+	if REPLAY_VERSION != header.Version {
+		return nil, nil, fmt.Errorf("unsupported replay version %v, expecting %v", header.Version, REPLAY_VERSION)
+	}
+	inputFrames := make([]*InputFrameDownsync, 0)
+	for {
+		inputFrame := &InputFrameDownsync{}
+		err := readDelimited(br, inputFrame)
+		if io.EOF == err {
+			break
+		}
+		if nil != err {
+			return nil, nil, err
+		}
+		if int32(len(inputFrames)) != inputFrame.InputFrameId {
+			return nil, nil, fmt.Errorf("non-consecutive inputFrameId %v, expecting %v", inputFrame.InputFrameId, len(inputFrames))
+		}
+		inputFrames = append(inputFrames, inputFrame)
+	}
+	return header, inputFrames, nil
+}
+
+// Re-runs the dynamics from the kickoff frame, returns every "RoomDownsyncFrame" that can be derived from the recorded inputFrames, indexed by "RoomDownsyncFrame.Id".
+func ReplayRenderFrames(header *BattleReplayHeader, inputFrames []*InputFrameDownsync) []*RoomDownsyncFrame {
+	bci := header.BciFrame
+	simulator := NewSimulator(NewStage(bci, int(header.Capacity), header.PlayerDefaultSpeed))
+	getInputFrame := func(inputFrameId int32) *InputFrameDownsync {
+		if 0 > inputFrameId || int(inputFrameId) >= len(inputFrames) {
+			return nil
+		}
+		return inputFrames[inputFrameId]
+	}
+
+	renderFrames := []*RoomDownsyncFrame{header.KickoffFrame}
+	if 0 >= len(inputFrames) {
+		return renderFrames
+	}
+	lastRenderFrameId := ConvertToScheduledLastUsedRenderFrameId(int32(len(inputFrames)-1), bci.InputDelayFrames, bci.InputDelayFramesSwitches, bci.InputScaleFrames)
+	for renderFrameId := int32(0); renderFrameId < lastRenderFrameId; renderFrameId++ {
+		delayedInputFrame := getInputFrame(ConvertToScheduledInputFrameId(renderFrameId, bci.InputDelayFrames, bci.InputDelayFramesSwitches, bci.InputScaleFrames))
+		var delayedInputFrameForPrevRenderFrame *InputFrameDownsync = nil
+		if nil != delayedInputFrame {
+			delayedInputFrameForPrevRenderFrame = getInputFrame(ConvertToScheduledInputFrameId(renderFrameId-1, bci.InputDelayFrames, bci.InputDelayFramesSwitches, bci.InputScaleFrames))
+		}
+		renderFrames = append(renderFrames, simulator.Step(delayedInputFrame, delayedInputFrameForPrevRenderFrame, renderFrames[renderFrameId]))
+	}
+	return renderFrames
+}
+
+func writeDelimited(w io.Writer, msg proto.Message) error {
+	theBytes, err := proto.Marshal(msg)
+	if nil != err {
+		return err
+	}
+	lenBuf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(lenBuf, uint64(len(theBytes)))
+	if _, err := w.Write(lenBuf[:n]); nil != err {
+		return err
+	}
+	_, err = w.Write(theBytes)
+	return err
+}
+
+func readDelimited(br *bufio.Reader, msg proto.Message) error {
+	l, err := binary.ReadUvarint(br)
+	if nil != err {
+		return err // Including "io.EOF" when there's no more message
+	}
+	theBytes := make([]byte, l)
+	if _, err := io.ReadFull(br, theBytes); nil != err {
+		if io.EOF == err {
+			return io.ErrUnexpectedEOF // A truncated message is never a clean end
+		}
+		return err
+	}
+	return proto.Unmarshal(theBytes, msg)
+}
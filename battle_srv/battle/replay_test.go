@@ -0,0 +1,65 @@
+package battle
+
+import (
+	. "battle_srv/protos"
+	"bytes"
+	. "dnmshared/sharedprotos"
+	"github.com/golang/protobuf/proto"
+	"testing"
+)
+
+func TestReplayReproducesRenderFrames(t *testing.T) {
+	stage := newTestStage()
+	bci := &BattleColliderInfo{
+		StageDiscreteW: 64,
+		StageDiscreteH: 64,
+		StageTileW:     16,
+		StageTileH:     16,
+		StrToPolygon2DListMap: map[string]*Polygon2DList{
+			"Barrier": &Polygon2DList{Eles: stage.Barriers},
+		},
+		BattleDurationNanos:      stage.BattleDurationNanos,
+		InputDelayFrames:         8,
+		InputScaleFrames:         2,
+		RollbackEstimatedDtNanos: stage.RollbackEstimatedDtNanos,
+		WorldToVirtualGridRatio:  stage.WorldToVirtualGridRatio,
+		VirtualGridToWorldRatio:  stage.VirtualGridToWorldRatio,
+		MeleeSkillConfig:         stage.MeleeSkillConfig,
+		FireballSkillConfig:      stage.FireballSkillConfig,
+		CharacterSkillBindings:   stage.CharacterSkillBindings,
+	}
+	header := &BattleReplayHeader{
+		Version:            REPLAY_VERSION,
+		Capacity:           2,
+		PlayerDefaultSpeed: 2000,
+		BciFrame:           bci,
+		KickoffFrame:       newTestKickoffFrame(),
+	}
+	inputFrames := make([]*InputFrameDownsync, 0)
+	for i, encoded := range []uint64{3, 3, 19, 0, 4, 20, 1, 2} {
+		inputFrames = append(inputFrames, &InputFrameDownsync{InputFrameId: int32(i), InputList: []uint64{encoded, 4}, ConfirmedList: 3})
+	}
+
+	var buf bytes.Buffer
+	if err := WriteReplay(&buf, header, inputFrames); nil != err {
+		t.Fatal(err)
+	}
+	loadedHeader, loadedInputFrames, err := ReadReplay(&buf)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if len(inputFrames) != len(loadedInputFrames) {
+		t.Fatalf("expected %d inputFrames, got %d", len(inputFrames), len(loadedInputFrames))
+	}
+
+	expected := ReplayRenderFrames(header, inputFrames)
+	actual := ReplayRenderFrames(loadedHeader, loadedInputFrames)
+	if len(expected) != len(actual) || int(ConvertToLastUsedRenderFrameId(int32(len(inputFrames)-1), 8, 2))+1 != len(actual) {
+		t.Fatalf("unexpected renderFrame count, expected=%d, actual=%d", len(expected), len(actual))
+	}
+	for i := range expected {
+		if !proto.Equal(expected[i], actual[i]) {
+			t.Fatalf("renderFrame %d differs:\nexpected=%v\nactual=%v", i, expected[i], actual[i])
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package battle
+
+import (
+	. "battle_srv/protos"
+	. "dnmshared"
+	. "dnmshared/sharedprotos"
+)
+
+/*
+[WARNING]
+
+A "Stage" is the IMMUTABLE description of everything the dynamics needs besides "RoomDownsyncFrame" and "InputFrameDownsync", i.e. it MUST NOT be mutated once a battle is started. All of "Room", offline tools, bots and tests should step the same simulation by sharing the same "Stage".
+*/
+type Stage struct {
+	Capacity int
+
+	SpaceW       int32
+	SpaceH       int32
+	SpaceOffsetX float64
+	SpaceOffsetY float64
+	CellSize     int // The cell size of the "resolv.Space", better be the approx minimum distance a player can move per frame in world coordinate
+
+	Barriers                []*Polygon2D // Already aligned to their bounding boxes
+	OneWayPlatforms         []*Polygon2D // The same as "Barriers" but only landed on from above, only used in "BATTLE_MODE_PLATFORMER"
+	PlayerStartingPositions []*Vec2D     // In world coordinates, indexed by "joinIndex-1", see "PlayerStartingPosition"
+
+	WorldToVirtualGridRatio  float64
+	VirtualGridToWorldRatio  float64
+	BattleDurationNanos      int64
+	RollbackEstimatedDtNanos int64
+
+	MeleeSkillConfig       map[int32]*MeleeBullet           // skillId -> skill
+	FireballSkillConfig    map[int32]*FireballBullet        // skillId -> skill
+	CharacterSkillBindings map[int32]*CharacterSkillBinding // speciesId -> binding
+
+	KoRespawnFrames int32 // 0 for elimination, i.e. a knocked-out player never respawns
+
+	BattleMode      int32
+	GravityY        int32 // The following are in virtual grid units, see "BattleColliderInfo"
+	JumpingInitVelY int32
+	MaxFallingVelY  int32
+
+	FixedPointCollisionEnabled bool
+	PlayerCollisionEnabled     bool // Players push each other back if true, otherwise pass through each other
+
+	TeamCount           int32
+	FriendlyFireEnabled bool
+}
+
+func NewStage(bci *BattleColliderInfo, capacity int, playerDefaultSpeed int32) *Stage {
+	spaceW := bci.StageDiscreteW * bci.StageTileW
+	spaceH := bci.StageDiscreteH * bci.StageTileH
+
+	minStep := (int(float64(playerDefaultSpeed)*bci.VirtualGridToWorldRatio) << 1)
+	if 0 >= minStep {
+		minStep = 1
+	}
+
+	barriers := make([]*Polygon2D, 0)
+	if barrierPolygon2DList, existent := bci.StrToPolygon2DListMap["Barrier"]; existent && nil != barrierPolygon2DList {
+		for _, polygon2DUnaligned := range barrierPolygon2DList.Eles {
+			barriers = append(barriers, AlignPolygon2DToBoundingBox(polygon2DUnaligned))
+		}
+	}
+
+	oneWayPlatforms := make([]*Polygon2D, 0)
+	if oneWayPlatformPolygon2DList, existent := bci.StrToPolygon2DListMap["OneWayPlatform"]; existent && nil != oneWayPlatformPolygon2DList {
+		for _, polygon2DUnaligned := range oneWayPlatformPolygon2DList.Eles {
+			oneWayPlatforms = append(oneWayPlatforms, AlignPolygon2DToBoundingBox(polygon2DUnaligned))
+		}
+	}
+
+	playerStartingPositions := make([]*Vec2D, capacity)
+	for i := range playerStartingPositions {
+		playerStartingPositions[i] = PlayerStartingPosition(bci.StrToVec2DListMap, bci.TeamCount, int32(i+1))
+	}
+
+	return &Stage{
+		Capacity:                 capacity,
+		SpaceW:                   spaceW,
+		SpaceH:                   spaceH,
+		SpaceOffsetX:             float64(spaceW) * 0.5,
+		SpaceOffsetY:             float64(spaceH) * 0.5,
+		CellSize:                 minStep,
+		Barriers:                 barriers,
+		OneWayPlatforms:          oneWayPlatforms,
+		PlayerStartingPositions:  playerStartingPositions,
+		WorldToVirtualGridRatio:  bci.WorldToVirtualGridRatio,
+		VirtualGridToWorldRatio:  bci.VirtualGridToWorldRatio,
+		BattleDurationNanos:      bci.BattleDurationNanos,
+		RollbackEstimatedDtNanos: bci.RollbackEstimatedDtNanos,
+		MeleeSkillConfig:         bci.MeleeSkillConfig,
+		FireballSkillConfig:      bci.FireballSkillConfig,
+		CharacterSkillBindings:   bci.CharacterSkillBindings,
+		KoRespawnFrames:          bci.KoRespawnFrames,
+		BattleMode:               bci.BattleMode,
+		GravityY:                 bci.GravityY,
+		JumpingInitVelY:          bci.JumpingInitVelY,
+		MaxFallingVelY:           bci.MaxFallingVelY,
+
+		FixedPointCollisionEnabled: bci.FixedPointCollisionEnabled,
+		PlayerCollisionEnabled:     bci.PlayerCollisionEnabled,
+
+		TeamCount:           bci.TeamCount,
+		FriendlyFireEnabled: bci.FriendlyFireEnabled,
+	}
+}
+
+// Whether a bullet of "offender" affects "defender", i.e. never the offender itself, and a teammate only if "FriendlyFireEnabled".
+func (pStage *Stage) CanHit(offender, defender *PlayerDownsync) bool {
+	if offender.Id == defender.Id {
+		return false
+	}
+	return pStage.FriendlyFireEnabled || EffectiveTeamIdOf(offender) != EffectiveTeamIdOf(defender)
+}
+
+func (pStage *Stage) IsPlatformer() bool {
+	return BATTLE_MODE_PLATFORMER == pStage.BattleMode
+}
+
+func (pStage *Stage) boundSkillId(speciesId int32, btn string) (int32, bool) {
+	binding, existent := pStage.CharacterSkillBindings[speciesId]
+	if !existent || nil == binding {
+		return 0, false
+	}
+	skillId, existent := binding.BtnToSkillId[btn]
+	return skillId, existent
+}
+
+// Returns nil if no melee skill of "releaseTriggerType" is bound to "btn" for "speciesId", e.g. a falling-edge of a button bound to a rising-edge skill.
+func (pStage *Stage) FindSkill(speciesId int32, btn string, releaseTriggerType int32) *MeleeBullet {
+	skillId, existent := pStage.boundSkillId(speciesId, btn)
+	if !existent {
+		return nil
+	}
+	skill, existent := pStage.MeleeSkillConfig[skillId]
+	if !existent || nil == skill || releaseTriggerType != skill.ReleaseTriggerType {
+		return nil
+	}
+	return skill
+}
+
+// The same as "FindSkill" but for fireball skills.
+func (pStage *Stage) FindFireballSkill(speciesId int32, btn string, releaseTriggerType int32) *FireballBullet {
+	skillId, existent := pStage.boundSkillId(speciesId, btn)
+	if !existent {
+		return nil
+	}
+	skill, existent := pStage.FireballSkillConfig[skillId]
+	if !existent || nil == skill || releaseTriggerType != skill.ReleaseTriggerType {
+		return nil
+	}
+	return skill
+}
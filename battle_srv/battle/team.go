@@ -0,0 +1,87 @@
+package battle
+
+import (
+	. "battle_srv/protos"
+	. "dnmshared/sharedprotos"
+	"fmt"
+)
+
+const (
+	// The object group in a tmx file for the starting positions of players of a team, e.g. "PlayerStartingPosTeam1", in the order of joining within the team
+	TEAM_STARTING_POS_GROUP_PREFIX = "PlayerStartingPosTeam"
+)
+
+/*
+Teams are interleaved by "joinIndex" when "1 < teamCount", e.g. joinIndex 1 and 3 versus 2 and 4 of a 2v2, otherwise every player is a team of its own.
+
+Being a pure function of "joinIndex", the teams are deterministic for the backend, the frontends and replays without being told who joined when.
+*/
+func TeamIdOf(joinIndex int32, teamCount int32) int32 {
+	if 1 >= teamCount {
+		return joinIndex
+	}
+	return ((joinIndex - 1) % teamCount) + 1
+}
+
+/*
+Returns the starting position in world coordinates of the player of "joinIndex", i.e. of its team group "PlayerStartingPosTeam<teamId>" if existent in "strToVec2DListMap", otherwise of "PlayerStartingPos" by "joinIndex". Returns nil if there's no such position.
+*/
+func PlayerStartingPosition(strToVec2DListMap map[string]*Vec2DList, teamCount int32, joinIndex int32) *Vec2D {
+	if 1 < teamCount {
+		teamId := TeamIdOf(joinIndex, teamCount)
+		if teamPosList, existent := strToVec2DListMap[fmt.Sprintf("%s%d", TEAM_STARTING_POS_GROUP_PREFIX, teamId)]; existent && nil != teamPosList {
+			indiceInTeam := int((joinIndex - 1) / teamCount)
+			if indiceInTeam < len(teamPosList.Eles) {
+				return teamPosList.Eles[indiceInTeam]
+			}
+			return nil
+		}
+	}
+	playerPosList, existent := strToVec2DListMap["PlayerStartingPos"]
+	if !existent || nil == playerPosList || int(joinIndex) > len(playerPosList.Eles) || 0 >= joinIndex {
+		return nil
+	}
+	return playerPosList.Eles[joinIndex-1]
+}
+
+// A "TeamId" of 0 is from before teams were introduced, e.g. in the kickoff frame of an old replay, thus a team of its own.
+func EffectiveTeamIdOf(player *PlayerDownsync) int32 {
+	if 0 == player.TeamId {
+		return TeamIdOf(player.JoinIndex, 0)
+	}
+	return player.TeamId
+}
+
+/*
+Returns the teamId of the winning team of "rdf", i.e. the one with the most players not knocked out, then with the most total HP, or 0 for a draw.
+*/
+func WinningTeamIdOf(rdf *RoomDownsyncFrame) int32 {
+	aliveCounts := make(map[int32]int32, len(rdf.Players))
+	totalHps := make(map[int32]int32, len(rdf.Players))
+	for _, player := range rdf.Players {
+		teamId := EffectiveTeamIdOf(player)
+		if _, existent := aliveCounts[teamId]; !existent {
+			aliveCounts[teamId], totalHps[teamId] = 0, 0
+		}
+		if ATK_CHARACTER_STATE_KO != player.CharacterState {
+			aliveCounts[teamId]++
+			totalHps[teamId] += player.Hp
+		}
+	}
+	winningTeamId, tied := int32(0), false
+	for teamId, aliveCount := range aliveCounts {
+		if 0 == winningTeamId {
+			winningTeamId = teamId
+			continue
+		}
+		if aliveCount > aliveCounts[winningTeamId] || (aliveCount == aliveCounts[winningTeamId] && totalHps[teamId] > totalHps[winningTeamId]) {
+			winningTeamId, tied = teamId, false
+		} else if aliveCount == aliveCounts[winningTeamId] && totalHps[teamId] == totalHps[winningTeamId] {
+			tied = true
+		}
+	}
+	if tied {
+		return 0
+	}
+	return winningTeamId
+}
@@ -0,0 +1,75 @@
+package battle
+
+import (
+	. "battle_srv/protos"
+	. "dnmshared/sharedprotos"
+	"testing"
+)
+
+func TestTeamsAreInterleavedWithTheirOwnStartingPositions(t *testing.T) {
+	strToVec2DListMap := map[string]*Vec2DList{
+		"PlayerStartingPos":      &Vec2DList{Eles: []*Vec2D{&Vec2D{X: 1, Y: 1}, &Vec2D{X: 2, Y: 2}}},
+		"PlayerStartingPosTeam1": &Vec2DList{Eles: []*Vec2D{&Vec2D{X: 10, Y: 0}, &Vec2D{X: 10, Y: 1}}},
+		"PlayerStartingPosTeam2": &Vec2DList{Eles: []*Vec2D{&Vec2D{X: 20, Y: 0}, &Vec2D{X: 20, Y: 1}}},
+	}
+	expectedTeamIds := []int32{1, 2, 1, 2}
+	expectedPositions := [][2]float64{{10, 0}, {20, 0}, {10, 1}, {20, 1}}
+	for i := range expectedTeamIds {
+		joinIndex := int32(i + 1)
+		if teamId := TeamIdOf(joinIndex, 2); expectedTeamIds[i] != teamId {
+			t.Fatalf("joinIndex=%v should be of teamId=%v, got %v", joinIndex, expectedTeamIds[i], teamId)
+		}
+		pos := PlayerStartingPosition(strToVec2DListMap, 2, joinIndex)
+		if nil == pos || expectedPositions[i][0] != pos.X || expectedPositions[i][1] != pos.Y {
+			t.Fatalf("joinIndex=%v should start at %v, got %v", joinIndex, expectedPositions[i], pos)
+		}
+	}
+	if pos := PlayerStartingPosition(strToVec2DListMap, 2, 5); nil != pos {
+		t.Fatalf("joinIndex=5 should have no starting position, got %v", pos)
+	}
+
+	// Free-for-all falls back to "PlayerStartingPos"
+	if teamId := TeamIdOf(2, 0); 2 != teamId {
+		t.Fatalf("joinIndex=2 of a free-for-all should be a team of its own, got %v", teamId)
+	}
+	if pos := PlayerStartingPosition(strToVec2DListMap, 0, 2); nil == pos || 2 != pos.X {
+		t.Fatalf("joinIndex=2 of a free-for-all should start at the 2nd \"PlayerStartingPos\", got %v", pos)
+	}
+}
+
+func TestFriendlyFireAndKoAreDecidedByTeams(t *testing.T) {
+	stage := newTestStage()
+	stage.Capacity = 4
+	stage.TeamCount = 2
+	rdf := &RoomDownsyncFrame{
+		Players: map[int32]*PlayerDownsync{
+			10: &PlayerDownsync{Id: 10, JoinIndex: 1, TeamId: 1},
+			11: &PlayerDownsync{Id: 11, JoinIndex: 2, TeamId: 2},
+			12: &PlayerDownsync{Id: 12, JoinIndex: 3, TeamId: 1},
+			13: &PlayerDownsync{Id: 13, JoinIndex: 4, TeamId: 2},
+		},
+	}
+
+	if stage.CanHit(rdf.Players[10], rdf.Players[12]) {
+		t.Fatalf("Teammates shouldn't hit each other without friendly fire")
+	}
+	if !stage.CanHit(rdf.Players[10], rdf.Players[11]) {
+		t.Fatalf("Opponents should hit each other")
+	}
+	stage.FriendlyFireEnabled = true
+	if !stage.CanHit(rdf.Players[10], rdf.Players[12]) || stage.CanHit(rdf.Players[10], rdf.Players[10]) {
+		t.Fatalf("Teammates but not oneself should be hit with friendly fire")
+	}
+
+	rdf.Players[11].CharacterState = ATK_CHARACTER_STATE_KO
+	if IsDecidedByKo(stage, rdf) {
+		t.Fatalf("The battle shouldn't be decided while a player of each team is alive")
+	}
+	rdf.Players[13].CharacterState = ATK_CHARACTER_STATE_KO
+	if !IsDecidedByKo(stage, rdf) {
+		t.Fatalf("The battle should be decided once a whole team is knocked out")
+	}
+	if winningTeamId := WinningTeamIdOf(rdf); 1 != winningTeamId {
+		t.Fatalf("Team 1 should win, got %v", winningTeamId)
+	}
+}
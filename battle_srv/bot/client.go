@@ -0,0 +1,364 @@
+package bot
+
+import (
+	"battle_srv/battle"
+	"battle_srv/models"
+	. "battle_srv/protos"
+	"context"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	. "dnmshared"
+)
+
+// All fields are updated atomically, thus safe to be read by another goroutine while "Client.Run" is in progress.
+type Stats struct {
+	ReceivedMsgCount             int64
+	ReceivedBytes                int64
+	SentMsgCount                 int64
+	SentBytes                    int64
+	InputBatchCount              int64
+	ForcedResyncCount            int64
+	UpsyncedInputFrameCount      int64
+	LastAllConfirmedInputFrameId int64
+	LastRttMillis                int64 // By the latest "DOWNSYNC_MSG_ACT_HB_ECHO"
+	InputDelaySwitchCount        int64
+}
+
+/*
+A headless client speaking the same "/tsrht" websocket protocol as "frontend/assets/scripts/WsSessionMgr.js" and "Map.js", except that it doesn't run any dynamics locally, i.e. it only keeps a render frame clock to upsync inputs in time.
+
+The typical lifecycle is
+- login by "LoginByIntAuthToken" or "LoginBySmsCaptcha",
+- "NewClient" then "Run", which returns nil once "DOWNSYNC_MSG_ACT_BATTLE_STOPPED" is received.
+*/
+type Client struct {
+	HttpBaseUrl    string
+	Login          *LoginResp
+	ExpectedRoomId int32 // Non-positive to pop any available room
+	SpectateRoomId int32 // Positive to watch the ongoing battle of this room as a spectator, then "ExpectedRoomId" and "Inputs" are ignored
+	Inputs         InputGenerator
+	Stats          Stats
+
+	// The following fields are only accessed by the goroutine running "Run".
+	Bci                           *BattleColliderInfo
+	JoinIndex                     int32
+	battleStarted                 bool
+	renderFrameId                 int32
+	lastAllConfirmedInputFrameId  int32
+	lastAllConfirmedRenderFrameId int32
+	lastUpsyncInputFrameId        int32
+	recentInputs                  map[int32]uint64 // inputFrameId -> encoded input of the bot itself, evicted once all-confirmed
+	echoedServerTimestamp         int64            // By the latest "DOWNSYNC_MSG_ACT_HB_ECHO", echoed back in the next "HeartbeatUpsync" for the backend to sample RTT
+	echoReceivedClientTimestamp   int64
+	conn                          *websocket.Conn
+}
+
+func NewClient(httpBaseUrl string, login *LoginResp, expectedRoomId int32, inputs InputGenerator) *Client {
+	return &Client{
+		HttpBaseUrl:                   httpBaseUrl,
+		Login:                         login,
+		ExpectedRoomId:                expectedRoomId,
+		Inputs:                        inputs,
+		Bci:                           nil,
+		JoinIndex:                     0,
+		battleStarted:                 false,
+		renderFrameId:                 0,
+		lastAllConfirmedInputFrameId:  -1,
+		lastAllConfirmedRenderFrameId: -1,
+		lastUpsyncInputFrameId:        -1,
+		recentInputs:                  make(map[int32]uint64),
+	}
+}
+
+func (pC *Client) WsUrl() string {
+	query := url.Values{"intAuthToken": {pC.Login.IntAuthToken}, "inputEncodingVersion": {fmt.Sprintf("%d", battle.INPUT_ENCODING_VERSION)}}
+	if 0 < pC.SpectateRoomId {
+		query.Set("spectateRoomId", fmt.Sprintf("%d", pC.SpectateRoomId))
+	} else if 0 < pC.ExpectedRoomId {
+		query.Set("expectedRoomId", fmt.Sprintf("%d", pC.ExpectedRoomId))
+	}
+	return strings.Replace(pC.HttpBaseUrl, "http", "ws", 1) + "/tsrht?" + query.Encode()
+}
+
+/*
+Blocks until the battle is stopped (returns nil), the connection is broken or "ctx" is done (returns the error).
+
+[WARNING] Only this goroutine writes to the connection, as gorilla/websocket supports at most one concurrent writer, see https://godoc.org/github.com/gorilla/websocket#hdr-Concurrency.
+*/
+func (pC *Client) Run(ctx context.Context) error {
+	conn, _, err := websocket.DefaultDialer.Dial(pC.WsUrl(), nil)
+	if nil != err {
+		return err
+	}
+	pC.conn = conn
+	defer conn.Close()
+
+	respCh := make(chan *WsResp, 64)
+	readErrCh := make(chan error, 1)
+	readerStopped := make(chan struct{})
+	defer close(readerStopped)
+	go func() {
+		for {
+			_, theBytes, err := conn.ReadMessage()
+			if nil != err {
+				readErrCh <- err
+				return
+			}
+			atomic.AddInt64(&pC.Stats.ReceivedMsgCount, 1)
+			atomic.AddInt64(&pC.Stats.ReceivedBytes, int64(len(theBytes)))
+			resp := &WsResp{}
+			if err := proto.Unmarshal(theBytes, resp); nil != err {
+				readErrCh <- err
+				return
+			}
+			select {
+			case respCh <- resp:
+			case <-readerStopped:
+				return
+			}
+		}
+	}()
+
+	// Both tickers are started upon receiving the corresponding downsync
+	var hbTickerCh, renderFrameTickerCh <-chan time.Time
+	defer func() {
+		// Stops the websocket session gracefully, errors are ignored because the connection is to be closed anyway
+		conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	}()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-readErrCh:
+			return err
+		case resp := <-respCh:
+			stopped, err := pC.onResp(resp)
+			if nil != err {
+				return err
+			}
+			if stopped {
+				return nil
+			}
+			if nil == hbTickerCh && nil != pC.Bci && 0 < pC.Bci.IntervalToPing {
+				hbTicker := time.NewTicker(time.Duration(pC.Bci.IntervalToPing) * time.Millisecond)
+				defer hbTicker.Stop()
+				hbTickerCh = hbTicker.C
+			}
+			if nil == renderFrameTickerCh && pC.battleStarted && 0 >= pC.SpectateRoomId {
+				renderFrameTicker := time.NewTicker(time.Duration(pC.Bci.RollbackEstimatedDtNanos))
+				defer renderFrameTicker.Stop()
+				renderFrameTickerCh = renderFrameTicker.C
+			}
+		case <-hbTickerCh:
+			if err := pC.sendHeartbeat(); nil != err {
+				return err
+			}
+		case <-renderFrameTickerCh:
+			if err := pC.onRenderFrameTick(); nil != err {
+				return err
+			}
+		}
+	}
+}
+
+func (pC *Client) onResp(resp *WsResp) (bool, error) {
+	switch resp.Act {
+	case models.DOWNSYNC_MSG_ACT_HB_REQ:
+		if int32(RET_CODE_OK) != resp.Ret || nil == resp.BciFrame {
+			return false, fmt.Errorf("unexpected HeartbeatRequirements, ret=%v", resp.Ret)
+		}
+		pC.Bci = resp.BciFrame
+		Logger.Info("Bot received BattleColliderInfo:", zap.Any("playerId", pC.Login.PlayerId), zap.Any("boundRoomId", pC.Bci.BoundRoomId), zap.Any("stageName", pC.Bci.StageName))
+		return false, pC.send(&WsReq{
+			MsgId:    pC.newMsgId(),
+			PlayerId: pC.Login.PlayerId,
+			Act:      models.UPSYNC_MSG_ACT_PLAYER_COLLIDER_ACK,
+		})
+	case models.DOWNSYNC_MSG_ACT_PLAYER_ADDED_AND_ACKED, models.DOWNSYNC_MSG_ACT_PLAYER_READDED_AND_ACKED, models.DOWNSYNC_MSG_ACT_BATTLE_READY_TO_START:
+		pC.updateJoinIndex(resp.Rdf)
+	case models.DOWNSYNC_MSG_ACT_PLAYER_READY_STATE_CHANGED:
+		if nil == resp.Rdf || 0 >= resp.Rdf.CountdownNanos {
+			// Not or no longer in a ready-check
+			return false, nil
+		}
+		if player, existent := resp.Rdf.Players[pC.Login.PlayerId]; existent && !player.Ready {
+			return false, pC.send(&WsReq{
+				MsgId:    pC.newMsgId(),
+				PlayerId: pC.Login.PlayerId,
+				Act:      models.UPSYNC_MSG_ACT_PLAYER_READY,
+			})
+		}
+	case models.DOWNSYNC_MSG_ACT_BATTLE_START:
+		if nil == pC.Bci {
+			return false, fmt.Errorf("battle started before receiving BattleColliderInfo")
+		}
+		if nil != resp.BciFrame {
+			// Only attached when the backend chooses "InputDelayFrames" at battle start
+			pC.Bci = resp.BciFrame
+		}
+		pC.updateJoinIndex(resp.Rdf)
+		pC.onRoomDownsyncFrame(resp.Rdf)
+		pC.battleStarted = true
+		Logger.Info("Bot battle started:", zap.Any("playerId", pC.Login.PlayerId), zap.Any("joinIndex", pC.JoinIndex))
+	case models.DOWNSYNC_MSG_ACT_INPUT_BATCH:
+		atomic.AddInt64(&pC.Stats.InputBatchCount, 1)
+		pC.onInputFrameDownsyncBatch(resp.InputFrameDownsyncBatch)
+	case models.DOWNSYNC_MSG_ACT_FORCED_RESYNC:
+		atomic.AddInt64(&pC.Stats.ForcedResyncCount, 1)
+		if nil == resp.Rdf || 0 >= len(resp.InputFrameDownsyncBatch) {
+			return false, fmt.Errorf("got forced resync without rdf or inputFrameDownsyncBatch @localRenderFrameId=%v", pC.renderFrameId)
+		}
+		Logger.Warn("Bot got forced resync:", zap.Any("playerId", pC.Login.PlayerId), zap.Any("localRenderFrameId", pC.renderFrameId), zap.Any("rdfId", resp.Rdf.Id), zap.Any("lastAllConfirmedInputFrameId", pC.lastAllConfirmedInputFrameId))
+		// The following order of execution is important, the same as in "WsSessionMgr.js"
+		pC.onRoomDownsyncFrame(resp.Rdf)
+		pC.onInputFrameDownsyncBatch(resp.InputFrameDownsyncBatch)
+	case models.DOWNSYNC_MSG_ACT_HB_ECHO:
+		if nil == resp.Hb {
+			return false, fmt.Errorf("got heartbeat echo without hb")
+		}
+		nowMillis := time.Now().UnixMilli()
+		pC.echoedServerTimestamp = resp.Hb.ServerTimestamp
+		pC.echoReceivedClientTimestamp = nowMillis
+		atomic.StoreInt64(&pC.Stats.LastRttMillis, nowMillis-resp.Hb.EchoedClientTimestamp)
+	case models.DOWNSYNC_MSG_ACT_INPUT_DELAY_SWITCHED:
+		if nil == resp.BciFrame {
+			return false, fmt.Errorf("got input delay switched without BattleColliderInfo")
+		}
+		atomic.AddInt64(&pC.Stats.InputDelaySwitchCount, 1)
+		pC.Bci = resp.BciFrame
+		Logger.Info("Bot got input delay switched:", zap.Any("playerId", pC.Login.PlayerId), zap.Any("localRenderFrameId", pC.renderFrameId), zap.Any("inputDelayFramesSwitches", pC.Bci.InputDelayFramesSwitches))
+	case models.DOWNSYNC_MSG_ACT_BATTLE_STOPPED:
+		Logger.Info("Bot battle stopped:", zap.Any("playerId", pC.Login.PlayerId), zap.Any("localRenderFrameId", pC.renderFrameId))
+		return true, nil
+	default:
+	}
+	return false, nil
+}
+
+func (pC *Client) updateJoinIndex(rdf *RoomDownsyncFrame) {
+	if nil == rdf {
+		return
+	}
+	if player, existent := rdf.Players[pC.Login.PlayerId]; existent {
+		pC.JoinIndex = player.JoinIndex
+	}
+}
+
+func (pC *Client) onRoomDownsyncFrame(rdf *RoomDownsyncFrame) {
+	if rdf.Id < pC.lastAllConfirmedRenderFrameId {
+		return
+	}
+	pC.lastAllConfirmedRenderFrameId = rdf.Id
+	if pC.renderFrameId < rdf.Id {
+		// A lagging clock is pulled forward, and the skipped inputFrames are never upsynced.
+		pC.renderFrameId = rdf.Id
+	}
+}
+
+func (pC *Client) onInputFrameDownsyncBatch(batch []*InputFrameDownsync) {
+	for _, inputFrameDownsync := range batch {
+		if inputFrameDownsync.InputFrameId <= pC.lastAllConfirmedInputFrameId {
+			continue
+		}
+		pC.lastAllConfirmedInputFrameId = inputFrameDownsync.InputFrameId
+	}
+	atomic.StoreInt64(&pC.Stats.LastAllConfirmedInputFrameId, int64(pC.lastAllConfirmedInputFrameId))
+	for inputFrameId := range pC.recentInputs {
+		if inputFrameId <= pC.lastAllConfirmedInputFrameId {
+			delete(pC.recentInputs, inputFrameId)
+		}
+	}
+}
+
+func (pC *Client) onRenderFrameTick() error {
+	defer func() {
+		pC.renderFrameId++
+	}()
+	if 0 != (pC.renderFrameId & ((1 << pC.Bci.InputScaleFrames) - 1)) {
+		return nil
+	}
+	noDelayInputFrameId := battle.ConvertToInputFrameId(pC.renderFrameId, 0, pC.Bci.InputScaleFrames)
+	prevSelfInput, prevExistent := pC.recentInputs[noDelayInputFrameId-1]
+	currSelfInput := pC.Inputs.Next(noDelayInputFrameId)
+	pC.recentInputs[noDelayInputFrameId] = currSelfInput
+
+	// The same criteria as "shouldSendInputFrameUpsyncBatch" in "Map.js"
+	shouldUpsyncForEarlyAllConfirmedOnBackend := (noDelayInputFrameId-pC.lastUpsyncInputFrameId >= pC.Bci.InputFrameUpsyncDelayTolerance)
+	if !shouldUpsyncForEarlyAllConfirmedOnBackend && prevExistent && prevSelfInput == currSelfInput {
+		return nil
+	}
+	return pC.sendInputFrameUpsyncBatch(noDelayInputFrameId)
+}
+
+func (pC *Client) sendInputFrameUpsyncBatch(latestLocalInputFrameId int32) error {
+	batchInputFrameIdSt := pC.lastUpsyncInputFrameId + 1
+	if batchInputFrameIdSt <= pC.lastAllConfirmedInputFrameId {
+		// No longer useful to the backend
+		batchInputFrameIdSt = pC.lastAllConfirmedInputFrameId + 1
+	}
+	inputFrameUpsyncBatch := make([]*InputFrameUpsync, 0, latestLocalInputFrameId-batchInputFrameIdSt+1)
+	for inputFrameId := batchInputFrameIdSt; inputFrameId <= latestLocalInputFrameId; inputFrameId++ {
+		encoded, existent := pC.recentInputs[inputFrameId]
+		if !existent {
+			// Skipped by a forced resync
+			continue
+		}
+		inputFrameUpsyncBatch = append(inputFrameUpsyncBatch, &InputFrameUpsync{
+			InputFrameId: inputFrameId,
+			Encoded:      encoded,
+		})
+	}
+	err := pC.send(&WsReq{
+		MsgId:                 pC.newMsgId(),
+		PlayerId:              pC.Login.PlayerId,
+		Act:                   models.UPSYNC_MSG_ACT_PLAYER_CMD,
+		JoinIndex:             pC.JoinIndex,
+		AckingFrameId:         pC.lastAllConfirmedRenderFrameId,
+		AckingInputFrameId:    pC.lastAllConfirmedInputFrameId,
+		InputFrameUpsyncBatch: inputFrameUpsyncBatch,
+	})
+	if nil != err {
+		return err
+	}
+	atomic.AddInt64(&pC.Stats.UpsyncedInputFrameCount, int64(len(inputFrameUpsyncBatch)))
+	pC.lastUpsyncInputFrameId = latestLocalInputFrameId
+	return nil
+}
+
+func (pC *Client) sendHeartbeat() error {
+	return pC.send(&WsReq{
+		MsgId:    pC.newMsgId(),
+		PlayerId: pC.Login.PlayerId,
+		Act:      models.UPSYNC_MSG_ACT_HB_PING,
+		Hb: &HeartbeatUpsync{
+			ClientTimestamp:             time.Now().UnixMilli(),
+			EchoedServerTimestamp:       pC.echoedServerTimestamp,
+			EchoReceivedClientTimestamp: pC.echoReceivedClientTimestamp,
+		},
+	})
+}
+
+func (pC *Client) send(req *WsReq) error {
+	theBytes, err := proto.Marshal(req)
+	if nil != err {
+		return err
+	}
+	if err := pC.conn.WriteMessage(websocket.BinaryMessage, theBytes); nil != err {
+		return err
+	}
+	atomic.AddInt64(&pC.Stats.SentMsgCount, 1)
+	atomic.AddInt64(&pC.Stats.SentBytes, int64(len(theBytes)))
+	return nil
+}
+
+func (pC *Client) newMsgId() int32 {
+	// The same as "Date.now()" used by the frontend, truncated to fit "int32"
+	return int32(time.Now().UnixMilli())
+}
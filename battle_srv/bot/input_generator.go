@@ -0,0 +1,120 @@
+package bot
+
+import (
+	"battle_srv/battle"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+/*
+Generates the encoded input of the bot itself for each "inputFrameId", see "battle.DecodeInput" for the encoding, e.g. "(guardLevel << 5) + (btnALevel << 4) + dirIndex".
+
+[WARNING] An "InputGenerator" is only called by the goroutine running "Client.Run", thus not necessarily concurrency-safe.
+*/
+type InputGenerator interface {
+	Next(inputFrameId int32) uint64
+}
+
+type ScriptedInputStep struct {
+	InputFrameId int32
+	Encoded      uint64
+}
+
+/*
+Holds each "Encoded" from its "InputFrameId" until the "InputFrameId" of the next step, e.g. steps "[{0, 3}, {30, 0}]" moves towards direction#3 for 30 inputFrames and then stays idle.
+
+When "Period" is positive, the script restarts from "Steps[0]" every "Period" inputFrames.
+*/
+type ScriptedInputGenerator struct {
+	Steps  []ScriptedInputStep
+	Period int32
+}
+
+func (g *ScriptedInputGenerator) Next(inputFrameId int32) uint64 {
+	if 0 < g.Period {
+		inputFrameId = inputFrameId % g.Period
+	}
+	encoded := uint64(0)
+	for _, step := range g.Steps {
+		if step.InputFrameId > inputFrameId {
+			break
+		}
+		encoded = step.Encoded
+	}
+	return encoded
+}
+
+/*
+Parses a script literal like "0:3,30:0,45:16", i.e. comma-separated "inputFrameId:encoded" pairs in ascending order of "inputFrameId".
+*/
+func ParseScriptedInputGenerator(script string, period int32) (*ScriptedInputGenerator, error) {
+	g := &ScriptedInputGenerator{
+		Steps:  make([]ScriptedInputStep, 0),
+		Period: period,
+	}
+	for _, pairStr := range strings.Split(script, ",") {
+		pairStr = strings.TrimSpace(pairStr)
+		if "" == pairStr {
+			continue
+		}
+		pair := strings.Split(pairStr, ":")
+		if 2 != len(pair) {
+			return nil, fmt.Errorf("invalid script step %q, expecting \"inputFrameId:encoded\"", pairStr)
+		}
+		inputFrameId, err := strconv.ParseInt(pair[0], 10, 32)
+		if nil != err {
+			return nil, err
+		}
+		encoded, err := strconv.ParseUint(pair[1], 10, 64)
+		if nil != err {
+			return nil, err
+		}
+		if 0 < len(g.Steps) && int32(inputFrameId) <= g.Steps[len(g.Steps)-1].InputFrameId {
+			return nil, fmt.Errorf("script step %q is not in ascending order of inputFrameId", pairStr)
+		}
+		g.Steps = append(g.Steps, ScriptedInputStep{int32(inputFrameId), encoded})
+	}
+	if 0 >= len(g.Steps) {
+		return nil, fmt.Errorf("empty script")
+	}
+	return g, nil
+}
+
+/*
+Picks a random direction with a random "btnALevel" and holds it for "HoldInputFrames", seeded such that a CI run is reproducible.
+*/
+type RandomInputGenerator struct {
+	HoldInputFrames int32
+	BtnAProbability float64
+	rdm             *rand.Rand
+	curr            uint64
+	currUntil       int32
+}
+
+func NewRandomInputGenerator(seed int64, holdInputFrames int32, btnAProbability float64) *RandomInputGenerator {
+	if 0 >= holdInputFrames {
+		holdInputFrames = 1
+	}
+	return &RandomInputGenerator{
+		HoldInputFrames: holdInputFrames,
+		BtnAProbability: btnAProbability,
+		rdm:             rand.New(rand.NewSource(seed)),
+		curr:            0,
+		currUntil:       -1,
+	}
+}
+
+func (g *RandomInputGenerator) Next(inputFrameId int32) uint64 {
+	if inputFrameId <= g.currUntil {
+		return g.curr
+	}
+	encoded := uint64(g.rdm.Intn(len(battle.DIRECTION_DECODER)))
+	if g.rdm.Float64() < g.BtnAProbability {
+		encoded += (1 << 4)
+	}
+	g.curr = encoded
+	g.currUntil = inputFrameId + g.HoldInputFrames - 1
+	return g.curr
+}
@@ -0,0 +1,33 @@
+package bot
+
+import (
+	"testing"
+)
+
+func TestScriptedInputGeneratorHoldsAndLoops(t *testing.T) {
+	g, err := ParseScriptedInputGenerator("0:3, 30:4,60:16", 90)
+	if nil != err {
+		t.Fatal(err)
+	}
+	for inputFrameId, expected := range map[int32]uint64{0: 3, 29: 3, 30: 4, 59: 4, 60: 16, 89: 16, 90: 3, 121: 4} {
+		if actual := g.Next(inputFrameId); expected != actual {
+			t.Fatalf("inputFrameId=%v, expected=%v, actual=%v", inputFrameId, expected, actual)
+		}
+	}
+	if _, err := ParseScriptedInputGenerator("30:4,0:3", 0); nil == err {
+		t.Fatal("expected error for descending inputFrameIds")
+	}
+}
+
+func TestRandomInputGeneratorIsReproducible(t *testing.T) {
+	g1, g2 := NewRandomInputGenerator(42, 3, 0.5), NewRandomInputGenerator(42, 3, 0.5)
+	for inputFrameId := int32(0); inputFrameId < 100; inputFrameId++ {
+		v1, v2 := g1.Next(inputFrameId), g2.Next(inputFrameId)
+		if v1 != v2 {
+			t.Fatalf("inputFrameId=%v, %v != %v", inputFrameId, v1, v2)
+		}
+		if 0 != inputFrameId%3 && v1 != g1.curr {
+			t.Fatalf("inputFrameId=%v is not held", inputFrameId)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package bot
+
+import (
+	"battle_srv/models"
+	"fmt"
+)
+
+type roomLoadStatsResp struct {
+	Ret   int                             `json:"ret"`
+	Rooms []*models.RoomLoadStatsSnapshot `json:"rooms"`
+}
+
+// Only available when the server runs with "ServerEnv=TEST".
+func FetchRoomLoadStats(httpBaseUrl string) ([]*models.RoomLoadStatsSnapshot, error) {
+	resp := &roomLoadStatsResp{}
+	if err := getJSON(httpBaseUrl+"/api/room/v1/loadStats", resp); nil != err {
+		return nil, err
+	}
+	if RET_CODE_OK != resp.Ret {
+		return nil, fmt.Errorf("fetching room load stats failed, ret=%v", resp.Ret)
+	}
+	return resp.Rooms, nil
+}
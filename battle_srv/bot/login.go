@@ -0,0 +1,94 @@
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+/*
+Mirrors "RET_CODE" in "common/constants.json", copied here such that a bot doesn't need the server-side configs to run.
+*/
+const (
+	RET_CODE_OK          = 9000
+	RET_CODE_IS_TEST_ACC = 9003
+	RET_CODE_IS_BOT_ACC  = 9017
+)
+
+type LoginResp struct {
+	Ret          int    `json:"ret"`
+	IntAuthToken string `json:"intAuthToken"`
+	ExpiresAt    int64  `json:"expiresAt"`
+	PlayerId     int32  `json:"playerId"`
+	DisplayName  string `json:"displayName"`
+	Name         string `json:"name"`
+}
+
+type smsCaptchaGetResp struct {
+	Ret             int    `json:"ret"`
+	SmsLoginCaptcha string `json:"smsLoginCaptcha"`
+}
+
+// Verifies an existing "intAuthToken" and fetches the bound player, "httpBaseUrl" is like "http://localhost:9992".
+func LoginByIntAuthToken(httpBaseUrl string, intAuthToken string) (*LoginResp, error) {
+	resp := &LoginResp{}
+	form := url.Values{"intAuthToken": {intAuthToken}}
+	if err := postForm(httpBaseUrl+"/api/player/v1/IntAuthToken/login", form, resp); nil != err {
+		return nil, err
+	}
+	if RET_CODE_OK != resp.Ret {
+		return nil, fmt.Errorf("IntAuthToken login failed, ret=%v", resp.Ret)
+	}
+	return resp, nil
+}
+
+/*
+Only test accounts (when the server runs with "ServerEnv=TEST") and bot accounts get "smsLoginCaptcha" echoed in the response of "SmsCaptcha/get", thus this login flow is only available for them.
+*/
+func LoginBySmsCaptcha(httpBaseUrl string, phoneNum string, phoneCountryCode string) (*LoginResp, error) {
+	captchaResp := &smsCaptchaGetResp{}
+	query := url.Values{"phoneNum": {phoneNum}, "phoneCountryCode": {phoneCountryCode}}
+	if err := getJSON(httpBaseUrl+"/api/player/v1/SmsCaptcha/get?"+query.Encode(), captchaResp); nil != err {
+		return nil, err
+	}
+	if RET_CODE_IS_TEST_ACC != captchaResp.Ret && RET_CODE_IS_BOT_ACC != captchaResp.Ret {
+		return nil, fmt.Errorf("SmsCaptcha get failed for phoneNum=%v, ret=%v, only test or bot accounts are supported", phoneNum, captchaResp.Ret)
+	}
+
+	resp := &LoginResp{}
+	form := url.Values{"phoneNum": {phoneNum}, "phoneCountryCode": {phoneCountryCode}, "smsLoginCaptcha": {captchaResp.SmsLoginCaptcha}}
+	if err := postForm(httpBaseUrl+"/api/player/v1/SmsCaptcha/login", form, resp); nil != err {
+		return nil, err
+	}
+	if RET_CODE_OK != resp.Ret {
+		return nil, fmt.Errorf("SmsCaptcha login failed for phoneNum=%v, ret=%v", phoneNum, resp.Ret)
+	}
+	return resp, nil
+}
+
+func getJSON(theUrl string, v interface{}) error {
+	httpResp, err := http.Get(theUrl)
+	if nil != err {
+		return err
+	}
+	defer httpResp.Body.Close()
+	return decodeJSONResp(httpResp, v)
+}
+
+func postForm(theUrl string, form url.Values, v interface{}) error {
+	httpResp, err := http.Post(theUrl, "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	if nil != err {
+		return err
+	}
+	defer httpResp.Body.Close()
+	return decodeJSONResp(httpResp, v)
+}
+
+func decodeJSONResp(httpResp *http.Response, v interface{}) error {
+	if http.StatusOK != httpResp.StatusCode {
+		return fmt.Errorf("unexpected http status %v from %v", httpResp.StatusCode, httpResp.Request.URL)
+	}
+	return json.NewDecoder(httpResp.Body).Decode(v)
+}
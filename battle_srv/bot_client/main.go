@@ -0,0 +1,73 @@
+package main
+
+import (
+	"battle_srv/bot"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+)
+
+/*
+Runs a single headless bot through one battle, e.g.
+
+```
+./bot_client -addr http://localhost:9992 -phoneNum add -script "0:3,30:4,60:16" -scriptPeriod 90
+```
+
+Exits with a non-zero code if the battle isn't stopped normally within "-timeout", thus usable in CI.
+*/
+func main() {
+	addr := flag.String("addr", "http://localhost:9992", "http base url of battle_srv")
+	intAuthToken := flag.String("intAuthToken", "", "login by an existing intAuthToken")
+	phoneNum := flag.String("phoneNum", "", "login by the SMS flow of a test or bot account, used when \"-intAuthToken\" is empty")
+	phoneCountryCode := flag.String("phoneCountryCode", "86", "phoneCountryCode for the SMS flow")
+	expectedRoomId := flag.Int("expectedRoomId", 0, "room to join, non-positive to pop any available room")
+	spectateRoomId := flag.Int("spectateRoomId", 0, "watch the ongoing battle of this room as a spectator if positive, no input is upsynced")
+	script := flag.String("script", "", "comma-separated \"inputFrameId:encoded\" pairs, random inputs are used when empty")
+	scriptPeriod := flag.Int("scriptPeriod", 0, "restarts the script every this many inputFrames if positive")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "seed for random inputs")
+	holdInputFrames := flag.Int("holdInputFrames", 8, "inputFrames to hold each random input")
+	btnAProbability := flag.Float64("btnAProbability", 0.1, "probability of pressing btnA for each random input")
+	timeout := flag.Duration("timeout", 5*time.Minute, "gives up if the battle isn't stopped in time")
+	flag.Parse()
+
+	if err := run(*addr, *intAuthToken, *phoneNum, *phoneCountryCode, int32(*expectedRoomId), int32(*spectateRoomId), *script, int32(*scriptPeriod), *seed, int32(*holdInputFrames), *btnAProbability, *timeout); nil != err {
+		fmt.Fprintf(os.Stderr, "bot_client failed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(addr string, intAuthToken string, phoneNum string, phoneCountryCode string, expectedRoomId int32, spectateRoomId int32, script string, scriptPeriod int32, seed int64, holdInputFrames int32, btnAProbability float64, timeout time.Duration) error {
+	var login *bot.LoginResp
+	var err error
+	if "" != intAuthToken {
+		login, err = bot.LoginByIntAuthToken(addr, intAuthToken)
+	} else if "" != phoneNum {
+		login, err = bot.LoginBySmsCaptcha(addr, phoneNum, phoneCountryCode)
+	} else {
+		err = fmt.Errorf("either \"-intAuthToken\" or \"-phoneNum\" is required")
+	}
+	if nil != err {
+		return err
+	}
+
+	var inputs bot.InputGenerator
+	if "" != script {
+		inputs, err = bot.ParseScriptedInputGenerator(script, scriptPeriod)
+		if nil != err {
+			return err
+		}
+	} else {
+		inputs = bot.NewRandomInputGenerator(seed, holdInputFrames, btnAProbability)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	client := bot.NewClient(addr, login, expectedRoomId, inputs)
+	client.SpectateRoomId = spectateRoomId
+	err = client.Run(ctx)
+	fmt.Printf("playerId=%v, joinIndex=%v, stats=%+v\n", login.PlayerId, client.JoinIndex, client.Stats)
+	return err
+}
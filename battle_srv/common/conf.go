@@ -47,6 +47,37 @@ type botServerConf struct {
 	SymmetricKey           string `json:"symmetricKey"`
 }
 
+type roomConf struct {
+	Capacity                   int    `json:"capacity"`
+	TeamCount                  int    `json:"teamCount"` // 0 for free-for-all, i.e. every player is a team of its own
+	FriendlyFireEnabled        bool   `json:"friendlyFireEnabled"`
+	StageName                  string `json:"stageName"` // Empty for a random one upon each dismissal
+	BattleDurationSeconds      int    `json:"battleDurationSeconds"`
+	ReadyCheckSeconds          int    `json:"readyCheckSeconds"`          // 0 to disable the ready-check
+	RematchWindowSeconds       int    `json:"rematchWindowSeconds"`       // 0 to disable the rematch vote after settlement
+	AdaptiveInputDelayEnabled  bool   `json:"adaptiveInputDelayEnabled"`  // Chooses the input delay by the measured round-trip time of players instead of a fixed one
+	BackendRollbackEnabled     bool   `json:"backendRollbackEnabled"`     // The backend then predicts non-all-confirmed inputFrames and rolls back upon a mismatched upsync, instead of waiting for all-confirmed ones
+	ResyncUponChecksumMismatch bool   `json:"resyncUponChecksumMismatch"` // Resyncs a player whose upsynced checksum mismatches that of the backend
+	FixedPointCollisionEnabled bool   `json:"fixedPointCollisionEnabled"` // Resolves collisions by integer arithmetics in virtual grid units instead of "resolv", opt-in until the frontend counterpart of "CalcPushbacksInVirtualGrid" is released
+	PlayerCollisionEnabled     bool   `json:"playerCollisionEnabled"`     // Players push each other back for melee brawling, otherwise pass through each other, opt-in until the frontend counterpart is released
+	KoRespawnFrames            int    `json:"koRespawnFrames"`            // A knocked-out player respawns at its starting position after this many render frames, 0 for elimination such that a battle ends as soon as only one side remains
+	ReplayRecordingEnabled     bool   `json:"replayRecordingEnabled"`     // Records the all-confirmed inputFrames of each battle into a replay file under "<AppRoot>/replays"
+	MaxReplayInputFrameCount   int    `json:"maxReplayInputFrameCount"`   // A replay exceeding this many inputFrames is dropped instead of being saved, 0 for no limit
+	MaxReplayFileCount         int    `json:"maxReplayFileCount"`         // The oldest replay files beyond this count are removed upon each save, 0 for no limit
+	InitialRoomCount           int    `json:"initialRoomCount"`
+	MaxRoomCount               int    `json:"maxRoomCount"`
+	MaxIdleRoomCount           int    `json:"maxIdleRoomCount"` // Dismissed rooms beyond this count are destroyed instead of being recycled
+}
+
+type matchmakingConf struct {
+	Enabled                       bool `json:"enabled"` // Otherwise a player joins the room popped from "RoomHeapManagerIns" by occupancy
+	TickMillis                    int  `json:"tickMillis"`
+	MaxWaitingSeconds             int  `json:"maxWaitingSeconds"`
+	InitialRatingWindow           int  `json:"initialRatingWindow"` // The max rating difference accepted by a player right after enqueued
+	RatingWindowWideningPerSecond int  `json:"ratingWindowWideningPerSecond"`
+	MaxRatingWindow               int  `json:"maxRatingWindow"`
+}
+
 type redisConf struct {
 	Dbname   int    `json:"dbname"`
 	Host     string `json:"host"`
@@ -55,20 +86,24 @@ type redisConf struct {
 }
 
 type config struct {
-	General   *generalConf
-	MySQL     *mysqlConf
-	Sio       *sioConf
-	Redis     *redisConf
-	BotServer *botServerConf
+	General     *generalConf
+	MySQL       *mysqlConf
+	Sio         *sioConf
+	Redis       *redisConf
+	BotServer   *botServerConf
+	Room        *roomConf
+	Matchmaking *matchmakingConf
 }
 
 func MustParseConfig() {
 	Conf = &config{
-		General:   new(generalConf),
-		MySQL:     new(mysqlConf),
-		Sio:       new(sioConf),
-		Redis:     new(redisConf),
-		BotServer: new(botServerConf),
+		General:     new(generalConf),
+		MySQL:       new(mysqlConf),
+		Sio:         new(sioConf),
+		Redis:       new(redisConf),
+		BotServer:   new(botServerConf),
+		Room:        new(roomConf),
+		Matchmaking: new(matchmakingConf),
 	}
 	execPath, err := os.Executable()
 	if nil != err {
@@ -118,6 +153,8 @@ func MustParseConfig() {
 	loadJSON("sio.json", Conf.Sio)
 	loadJSON("redis.json", Conf.Redis)
 	loadJSON("bot_server.json", Conf.BotServer)
+	loadJSON("room.json", Conf.Room)
+	loadJSON("matchmaking.json", Conf.Matchmaking)
 }
 
 func setMySQLDSNURL(c *mysqlConf) {
@@ -25,6 +25,9 @@ type constants struct {
 		IncorrectPhoneCountryCode                        int    `json:"INCORRECT_PHONE_COUNTRY_CODE"`
 		IncorrectPhoneNumber                             int    `json:"INCORRECT_PHONE_NUMBER"`
 		InsufficientMemToAllocateConnection              int    `json:"INSUFFICIENT_MEM_TO_ALLOCATE_CONNECTION"`
+		InputEncodingVersionMismatch                     int    `json:"INPUT_ENCODING_VERSION_MISMATCH"`
+		MatchmakingTimeout                               int    `json:"MATCHMAKING_TIMEOUT"`
+		ReadyCheckFailed                                 int    `json:"READY_CHECK_FAILED"`
 		InvalidEmailLiteral                              int    `json:"INVALID_EMAIL_LITERAL"`
 		InvalidRequestParam                              int    `json:"INVALID_REQUEST_PARAM"`
 		InvalidToken                                     int    `json:"INVALID_TOKEN"`
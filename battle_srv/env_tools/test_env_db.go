@@ -7,6 +7,7 @@ import (
 	. "battle_srv/protos"
 	"battle_srv/storage"
 	. "dnmshared"
+	"fmt"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/mattn/go-sqlite3"
@@ -24,6 +25,31 @@ func MergeTestPlayerAccounts() {
 	maybeCreateNewPlayer(db, "test_player")
 }
 
+const (
+	LOAD_TEST_PLAYER_COUNT       = 256
+	LOAD_TEST_PLAYER_NAME_PREFIX = "loadtest"
+)
+
+/*
+Creates accounts named "loadtest001", "loadtest002", ... for the load-testing bots (see "battle_srv/load_tester") to login by the SMS flow, because there're far from enough accounts in "test_player" to fill all rooms.
+*/
+func MergeLoadTestPlayerAccounts() {
+	Logger.Info(`Initializing LoadTestPlayerAccounts in runtime MySQLServer:`, zap.Int("count", LOAD_TEST_PLAYER_COUNT))
+	ls := make([]*dbTestPlayer, LOAD_TEST_PLAYER_COUNT)
+	for i := 0; i < LOAD_TEST_PLAYER_COUNT; i++ {
+		ls[i] = &dbTestPlayer{
+			Name:                  LoadTestPlayerName(i + 1),
+			MagicPhoneCountryCode: "086",
+			MagicPhoneNum:         fmt.Sprintf("1910000%04d", i+1),
+		}
+	}
+	mergeTestPlayers(ls, "load_test_player")
+}
+
+func LoadTestPlayerName(index int) string {
+	return fmt.Sprintf("%s%03d", LOAD_TEST_PLAYER_NAME_PREFIX, index)
+}
+
 type dbTestPlayer struct {
 	Name                  string `db:"name"`
 	MagicPhoneCountryCode string `db:"magic_phone_country_code"`
@@ -36,6 +62,10 @@ func maybeCreateNewPlayer(db *sqlx.DB, tableName string) {
 	if nil != err {
 		panic(err)
 	}
+	mergeTestPlayers(ls, tableName)
+}
+
+func mergeTestPlayers(ls []*dbTestPlayer, tableName string) {
 	names := make([]string, len(ls), len(ls))
 	for i, v := range ls {
 		names[i] = v.Name
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"battle_srv/bot"
+	"battle_srv/models"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+)
+
+/*
+Fills "-rooms" rooms with "-playersPerRoom" random-input bots each and reports both the server-side statistics of each room (by "/api/room/v1/loadStats", thus the server must run with "ServerEnv=TEST") and the client-side statistics of all bots, e.g.
+
+```
+./load_tester -addr http://localhost:9992 -rooms 32 -playersPerRoom 2
+```
+
+The bots login by the SMS flow as "loadtest001", "loadtest002", ..., which are created by "env_tools.MergeLoadTestPlayerAccounts" upon server startup. Rooms beyond the ones initialized by "models.InitRoomHeapManager" are requested by "expectedRoomId" as well, and the server falls back to popping any available room for them.
+*/
+func main() {
+	addr := flag.String("addr", "http://localhost:9992", "http base url of battle_srv")
+	roomCnt := flag.Int("rooms", 32, "count of rooms to fill")
+	firstRoomId := flag.Int("firstRoomId", 1, "the bots of the i-th room join by \"expectedRoomId=firstRoomId+i\"")
+	playersPerRoom := flag.Int("playersPerRoom", 2, "should be the capacity of each room")
+	namePrefix := flag.String("namePrefix", "loadtest", "bot account names are namePrefix%03d starting from 1")
+	phoneCountryCode := flag.String("phoneCountryCode", "86", "phoneCountryCode for the SMS flow")
+	seed := flag.Int64("seed", 1, "seed for random inputs, the i-th bot uses seed+i")
+	holdInputFrames := flag.Int("holdInputFrames", 8, "inputFrames to hold each random input")
+	btnAProbability := flag.Float64("btnAProbability", 0.1, "probability of pressing btnA for each random input")
+	reportInterval := flag.Duration("reportInterval", 10*time.Second, "interval of intermediate reports")
+	timeout := flag.Duration("timeout", 5*time.Minute, "gives up if the battles aren't stopped in time")
+	flag.Parse()
+
+	botCnt := (*roomCnt) * (*playersPerRoom)
+	clients := make([]*bot.Client, 0, botCnt)
+	roomIds := make(map[int32]bool)
+	for i := 0; i < botCnt; i++ {
+		name := fmt.Sprintf("%s%03d", *namePrefix, i+1)
+		login, err := bot.LoginBySmsCaptcha(*addr, name, *phoneCountryCode)
+		if nil != err {
+			fmt.Fprintf(os.Stderr, "login failed for %v: %v\n", name, err)
+			os.Exit(1)
+		}
+		expectedRoomId := int32(*firstRoomId + i/(*playersPerRoom))
+		roomIds[expectedRoomId] = true
+		inputs := bot.NewRandomInputGenerator(*seed+int64(i), int32(*holdInputFrames), *btnAProbability)
+		clients = append(clients, bot.NewClient(*addr, login, expectedRoomId, inputs))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	startedAt := time.Now()
+	failedCnt := int32(0)
+	var wg sync.WaitGroup
+	for _, client := range clients {
+		wg.Add(1)
+		go func(client *bot.Client) {
+			defer wg.Done()
+			if err := client.Run(ctx); nil != err {
+				atomic.AddInt32(&failedCnt, 1)
+				fmt.Fprintf(os.Stderr, "bot of playerId=%v failed: %v\n", client.Login.PlayerId, err)
+			}
+		}(client)
+	}
+	allDone := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(allDone)
+	}()
+
+	ticker := time.NewTicker(*reportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			report(*addr, roomIds, clients, time.Since(startedAt))
+		case <-allDone:
+			report(*addr, roomIds, clients, time.Since(startedAt))
+			if 0 < atomic.LoadInt32(&failedCnt) {
+				fmt.Fprintf(os.Stderr, "%v out of %v bots failed\n", failedCnt, len(clients))
+				os.Exit(1)
+			}
+			return
+		}
+	}
+}
+
+func report(addr string, roomIds map[int32]bool, clients []*bot.Client, elapsed time.Duration) {
+	fmt.Printf("==== %v elapsed ====\n", elapsed.Round(time.Second))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	rooms, err := bot.FetchRoomLoadStats(addr)
+	if nil != err {
+		fmt.Fprintf(os.Stderr, "fetching room load stats failed: %v\n", err)
+	} else {
+		total := &models.RoomLoadStatsSnapshot{}
+		fmt.Fprintln(w, "roomId\tstate\tplayers\trenderFrames\tavgFrame(ms)\tmaxFrame(ms)\tavgDynamics(ms)\tslowRatio\tdownsync(B/s)\tresyncs\tresyncs/s\tchecksumMismatches\t")
+		for _, room := range rooms {
+			if !roomIds[room.RoomId] && 0 >= room.RenderFrameCount {
+				continue
+			}
+			fmt.Fprintf(w, "%d\t%d\t%d\t%d\t%.3f\t%.3f\t%.3f\t%.4f\t%.0f\t%d\t%.3f\t%d\t\n", room.RoomId, room.State, room.EffectivePlayerCount, room.RenderFrameCount, nanosToMillis(room.AvgElapsedNanos), nanosToMillis(room.MaxElapsedNanos), nanosToMillis(room.AvgDynamicsNanos), room.SlowFrameRatio, room.DownsyncBytesPerSec, room.ForcedResyncCount, room.ForcedResyncPerSec, room.ChecksumMismatchCount)
+			total.RenderFrameCount += room.RenderFrameCount
+			total.SlowFrameCount += room.SlowFrameCount
+			total.DownsyncBytesPerSec += room.DownsyncBytesPerSec
+			total.ForcedResyncCount += room.ForcedResyncCount
+			total.ChecksumMismatchCount += room.ChecksumMismatchCount
+			if room.MaxElapsedNanos > total.MaxElapsedNanos {
+				total.MaxElapsedNanos = room.MaxElapsedNanos
+			}
+		}
+		if 0 < total.RenderFrameCount {
+			total.SlowFrameRatio = float64(total.SlowFrameCount) / float64(total.RenderFrameCount)
+		}
+		fmt.Fprintf(w, "total\t\t\t%d\t\t%.3f\t\t%.4f\t%.0f\t%d\t\t%d\t\n", total.RenderFrameCount, nanosToMillis(total.MaxElapsedNanos), total.SlowFrameRatio, total.DownsyncBytesPerSec, total.ForcedResyncCount, total.ChecksumMismatchCount)
+		w.Flush()
+	}
+
+	var receivedBytes, sentBytes, inputBatchCnt, forcedResyncCnt int64
+	for _, client := range clients {
+		receivedBytes += atomic.LoadInt64(&client.Stats.ReceivedBytes)
+		sentBytes += atomic.LoadInt64(&client.Stats.SentBytes)
+		inputBatchCnt += atomic.LoadInt64(&client.Stats.InputBatchCount)
+		forcedResyncCnt += atomic.LoadInt64(&client.Stats.ForcedResyncCount)
+	}
+	elapsedSeconds := elapsed.Seconds()
+	fmt.Printf("bots=%d, received=%.0f B/s, sent=%.0f B/s, inputBatches=%d, forcedResyncs=%d (%.3f/s)\n", len(clients), float64(receivedBytes)/elapsedSeconds, float64(sentBytes)/elapsedSeconds, inputBatchCnt, forcedResyncCnt, float64(forcedResyncCnt)/elapsedSeconds)
+}
+
+func nanosToMillis(nanos int64) float64 {
+	return float64(nanos) / 1e6
+}
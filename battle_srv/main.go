@@ -21,6 +21,8 @@ import (
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/robfig/cron"
 	"go.uber.org/zap"
 )
@@ -32,8 +34,14 @@ func main() {
 	env_tools.LoadPreConf()
 	if Conf.General.ServerEnv == SERVER_ENV_TEST {
 		env_tools.MergeTestPlayerAccounts()
+		env_tools.MergeLoadTestPlayerAccounts()
 	}
+	models.MustLoadSkillConfigs()
 	models.InitRoomHeapManager()
+	if Conf.Matchmaking.Enabled {
+		models.InitMatchmakingQueue()
+	}
+	prometheus.MustRegister(models.RoomsCollector{})
 	startScheduler()
 	router := gin.Default()
 	setRouter(router)
@@ -89,22 +97,30 @@ func setRouter(router *gin.Engine) {
 	router.StaticFS("/asset", http.Dir(filepath.Join(Conf.General.AppRoot, "asset")))
 	router.GET("/ping", f)
 	router.GET("/tsrht", ws.Serve)
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
 	apiRouter := router.Group("/api")
 	{
 		apiRouter.Use(api.HandleRet(), api.RequestLogger())
-		apiRouter.POST("/player/v1/IntAuthToken/login", v1.Player.IntAuthTokenLogin)
+		apiRouter.POST("/player/v1/IntAuthToken/login", api.CountLoginAttempts("intAuthToken"), v1.Player.IntAuthTokenLogin)
 		apiRouter.POST("/player/v1/IntAuthToken/logout", v1.Player.IntAuthTokenLogout)
 		apiRouter.GET("/player/v1/SmsCaptcha/get", v1.Player.SMSCaptchaGet)
-		apiRouter.POST("/player/v1/SmsCaptcha/login", v1.Player.SMSCaptchaLogin)
-		apiRouter.POST("/player/v1/wechat/login", v1.Player.WechatLogin)
+		apiRouter.POST("/player/v1/SmsCaptcha/login", api.CountLoginAttempts("smsCaptcha"), v1.Player.SMSCaptchaLogin)
+		apiRouter.POST("/player/v1/wechat/login", api.CountLoginAttempts("wechat"), v1.Player.WechatLogin)
 		apiRouter.POST("/player/v1/wechat/jsconfig", v1.Player.GetWechatShareConfig)
-		apiRouter.POST("/player/v1/wechatGame/login", v1.Player.WechatGameLogin)
+		apiRouter.POST("/player/v1/wechatGame/login", api.CountLoginAttempts("wechatGame"), v1.Player.WechatGameLogin)
 
 		authRouter := func(method string, url string, handler gin.HandlerFunc) {
 			apiRouter.Handle(method, url, v1.Player.TokenAuth, handler)
 		}
 		authRouter(http.MethodPost, "/player/v1/profile/fetch", v1.Player.FetchProfile)
+		authRouter(http.MethodPost, "/player/v1/battle/history", v1.Player.BattleHistory)
+		authRouter(http.MethodPost, "/room/v1/private/reserve", v1.Room.ReservePrivateRoom)
+		authRouter(http.MethodPost, "/room/v1/netStats", v1.Room.NetStats)
+
+		if Conf.General.ServerEnv == SERVER_ENV_TEST {
+			apiRouter.GET("/room/v1/loadStats", v1.Room.LoadStats)
+		}
 	}
 }
 
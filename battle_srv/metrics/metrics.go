@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+/*
+All collectors are registered to "prometheus.DefaultRegisterer" and exposed by "/metrics", see "setRouter" in "main.go".
+
+[WARNING] DON'T label any of the following by "playerId" or "roomId" unless the cardinality is bounded, a label value is never evicted once observed.
+*/
+
+const (
+	NAMESPACE = "battle_srv"
+
+	LOGIN_RESULT_OK     = "ok"
+	LOGIN_RESULT_FAILED = "failed"
+
+	STORAGE_BACKEND_MYSQL = "mysql"
+	STORAGE_BACKEND_REDIS = "redis"
+)
+
+var (
+	ActiveWsSessions = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: NAMESPACE,
+		Name:      "active_ws_sessions",
+		Help:      "Count of upgraded \"/tsrht\" websocket sessions not yet closed.",
+	})
+
+	RenderFrameDurationSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Name:      "render_frame_duration_seconds",
+		Help:      "Elapsed time of calculating each renderFrame in \"battleMainLoop\", excluding the sleep.",
+		Buckets:   []float64{0.0005, 0.001, 0.002, 0.004, 0.008, 0.012, 0.0166, 0.025, 0.05, 0.1},
+	})
+
+	DynamicsDurationSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Name:      "dynamics_duration_seconds",
+		Help:      "Elapsed time of \"applyInputFrameDownsyncDynamics\" in each renderFrame of \"battleMainLoop\", only observed when any dynamics is applied.",
+		Buckets:   []float64{0.0001, 0.0002, 0.0005, 0.001, 0.002, 0.004, 0.008, 0.0166, 0.05},
+	})
+
+	LoginAttemptsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "login_attempts_total",
+		Help:      "Count of login attempts by auth channel and result.",
+	}, []string{"channel", "result"})
+
+	StorageCallDurationSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Name:      "storage_call_duration_seconds",
+		Help:      "Latency of MySQL and Redis calls by backend and operation.",
+		Buckets:   prometheus.ExponentialBuckets(0.0002, 2, 14),
+	}, []string{"backend", "op"})
+)
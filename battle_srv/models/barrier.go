@@ -1,9 +0,0 @@
-package models
-
-import (
-	. "dnmshared/sharedprotos"
-)
-
-type Barrier struct {
-	Boundary *Polygon2D
-}
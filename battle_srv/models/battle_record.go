@@ -0,0 +1,140 @@
+package models
+
+import (
+	"battle_srv/common/utils"
+	"battle_srv/storage"
+	. "dnmshared"
+	"sort"
+
+	sq "github.com/Masterminds/squirrel"
+	"go.uber.org/zap"
+)
+
+const (
+	DEFAULT_BATTLE_HISTORY_PAGE_SIZE = 20
+	MAX_BATTLE_HISTORY_PAGE_SIZE     = 100
+)
+
+type BattleRecord struct {
+	ID             int64     `json:"id" db:"id"`
+	RoomId         int32     `json:"roomId" db:"room_id"`
+	StageName      string    `json:"stageName" db:"stage_name"`
+	DurationMillis int64     `json:"durationMillis" db:"duration_millis"`
+	StartedAt      int64     `json:"startedAt" db:"started_at"`
+	EndedAt        int64     `json:"endedAt" db:"ended_at"`
+	WinningTeamId  int32     `json:"winningTeamId" db:"winning_team_id"` // 0 for a draw or an unknown outcome, see "battle.WinningTeamIdOf"
+	CreatedAt      int64     `json:"-" db:"created_at"`
+	UpdatedAt      int64     `json:"-" db:"updated_at"`
+	DeletedAt      NullInt64 `json:"-" db:"deleted_at"`
+}
+
+type BattlePlayerRecord struct {
+	ID         int64     `json:"-" db:"id"`
+	BattleId   int64     `json:"battleId" db:"battle_id"`
+	PlayerId   int32     `json:"playerId" db:"player_id"`
+	JoinIndex  int32     `json:"joinIndex" db:"join_index"`
+	TeamId     int32     `json:"teamId" db:"team_id"`
+	FinalHp    int32     `json:"finalHp" db:"final_hp"`
+	Score      int32     `json:"score" db:"score"`
+	HitsLanded int32     `json:"hitsLanded" db:"hits_landed"`
+	CreatedAt  int64     `json:"-" db:"created_at"`
+	UpdatedAt  int64     `json:"-" db:"updated_at"`
+	DeletedAt  NullInt64 `json:"-" db:"deleted_at"`
+}
+
+// A settled battle in the history of a player, along with all of its participants.
+type BattleHistoryEntry struct {
+	Battle  *BattleRecord         `json:"battle"`
+	Players []*BattlePlayerRecord `json:"players"`
+}
+
+/*
+Inserts "pBattle" and its "players" in a single transaction, "BattleId" of each player is assigned by the inserted "pBattle.ID".
+*/
+func InsertBattleRecord(pBattle *BattleRecord, players []*BattlePlayerRecord) error {
+	now := utils.UnixtimeMilli()
+	tx, err := storage.MySQLManagerIns.Beginx()
+	if nil != err {
+		return err
+	}
+	defer tx.Rollback()
+	result, err := txInsert(tx, "battle", []string{"room_id", "stage_name", "duration_millis", "started_at", "ended_at", "winning_team_id", "created_at", "updated_at"},
+		[]interface{}{pBattle.RoomId, pBattle.StageName, pBattle.DurationMillis, pBattle.StartedAt, pBattle.EndedAt, pBattle.WinningTeamId, now, now})
+	if nil != err {
+		return err
+	}
+	pBattle.ID, err = result.LastInsertId()
+	if nil != err {
+		return err
+	}
+	for _, player := range players {
+		player.BattleId = pBattle.ID
+		_, err = txInsert(tx, "battle_player", []string{"battle_id", "player_id", "join_index", "team_id", "final_hp", "score", "hits_landed", "created_at", "updated_at"},
+			[]interface{}{player.BattleId, player.PlayerId, player.JoinIndex, player.TeamId, player.FinalHp, player.Score, player.HitsLanded, now, now})
+		if nil != err {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+/*
+Returns the battles participated by "playerId" from the latest, paginated by "page" starting from 1, along with the total count of such battles.
+*/
+func GetBattleHistoryByPlayerId(playerId int32, page int, pageSize int) ([]*BattleHistoryEntry, int, error) {
+	totalCount, err := getCount("battle_player", sq.Eq{"player_id": playerId, "deleted_at": nil})
+	if nil != err {
+		return nil, 0, err
+	}
+	ret := make([]*BattleHistoryEntry, 0, pageSize)
+	if 0 == totalCount {
+		return ret, 0, nil
+	}
+
+	query, args, err := sq.Select("battle_id").From("battle_player").
+		Where(sq.Eq{"player_id": playerId, "deleted_at": nil}).
+		OrderBy("battle_id DESC").
+		Limit(uint64(pageSize)).Offset(uint64((page - 1) * pageSize)).ToSql()
+	if nil != err {
+		return nil, 0, err
+	}
+	Logger.Debug("GetBattleHistoryByPlayerId", zap.String("sql", query), zap.Any("args", args))
+	battleIds := make([]int64, 0, pageSize)
+	if err := storage.MySQLManagerIns.Select(&battleIds, query, args...); nil != err {
+		return nil, 0, err
+	}
+	if 0 == len(battleIds) {
+		return ret, totalCount, nil
+	}
+
+	battles := make([]*BattleRecord, 0, len(battleIds))
+	if err := getList("battle", sq.Eq{"id": battleIds, "deleted_at": nil}, &battles); nil != err {
+		return nil, 0, err
+	}
+	players := make([]*BattlePlayerRecord, 0, len(battleIds))
+	if err := getList("battle_player", sq.Eq{"battle_id": battleIds, "deleted_at": nil}, &players); nil != err {
+		return nil, 0, err
+	}
+
+	entries := make(map[int64]*BattleHistoryEntry, len(battles))
+	for _, pBattle := range battles {
+		entries[pBattle.ID] = &BattleHistoryEntry{
+			Battle:  pBattle,
+			Players: make([]*BattlePlayerRecord, 0),
+		}
+	}
+	for _, player := range players {
+		if entry, existent := entries[player.BattleId]; existent {
+			entry.Players = append(entry.Players, player)
+		}
+	}
+	for _, battleId := range battleIds {
+		if entry, existent := entries[battleId]; existent {
+			sort.Slice(entry.Players, func(i, j int) bool {
+				return entry.Players[i].JoinIndex < entry.Players[j].JoinIndex
+			})
+			ret = append(ret, entry)
+		}
+	}
+	return ret, totalCount, nil
+}
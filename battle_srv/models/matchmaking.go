@@ -0,0 +1,253 @@
+package models
+
+import (
+	. "battle_srv/common"
+	"battle_srv/common/utils"
+	"container/heap"
+	. "dnmshared"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+/*
+A waiting player, "matchedRoomCh" receives exactly once, either the room reserved for its group or nil if replaced by a newer ticket of the same player.
+*/
+type matchmakingTicket struct {
+	playerId      int32
+	rating        int32
+	enqueuedAt    int64 // In milliseconds
+	matchedRoomCh chan *Room
+}
+
+// The max rating difference accepted by a ticket, widening linearly with its waiting time.
+type ratingWindowPolicy struct {
+	initial           int32
+	wideningPerSecond int32
+	max               int32
+}
+
+func (policy *ratingWindowPolicy) windowOf(waitedMillis int64) int32 {
+	ret := policy.initial + int32(int64(policy.wideningPerSecond)*waitedMillis/1000)
+	if ret > policy.max {
+		return policy.max
+	}
+	return ret
+}
+
+type MatchmakingQueue struct {
+	mux     sync.Mutex
+	tickets []*matchmakingTicket // Ordered by "enqueuedAt"
+	policy  ratingWindowPolicy
+}
+
+var MatchmakingQueueIns *MatchmakingQueue
+
+func InitMatchmakingQueue() {
+	MatchmakingQueueIns = &MatchmakingQueue{
+		tickets: make([]*matchmakingTicket, 0),
+		policy: ratingWindowPolicy{
+			initial:           int32(Conf.Matchmaking.InitialRatingWindow),
+			wideningPerSecond: int32(Conf.Matchmaking.RatingWindowWideningPerSecond),
+			max:               int32(Conf.Matchmaking.MaxRatingWindow),
+		},
+	}
+	go func() {
+		ticker := time.NewTicker(time.Duration(Conf.Matchmaking.TickMillis) * time.Millisecond)
+		defer ticker.Stop()
+		for range ticker.C {
+			MatchmakingQueueIns.tick()
+		}
+	}()
+	Logger.Info("The MatchmakingQueueIns has been initialized:", zap.Any("policy", MatchmakingQueueIns.policy))
+}
+
+/*
+Blocks until the player is matched into a room or "Conf.Matchmaking.MaxWaitingSeconds" elapses, returns nil for the latter.
+
+[WARNING] MUST NOT be called with "RoomHeapMux" locked, because the room is reserved by "tick" with "RoomHeapMux" locked.
+*/
+func (pQ *MatchmakingQueue) WaitForMatch(playerId int32) *Room {
+	rating, err := GetPlayerRatingById(playerId)
+	if nil != err {
+		Logger.Warn("WaitForMatch failed to get the player rating, using the default:", zap.Any("playerId", playerId), zap.Error(err))
+		rating = &PlayerRating{ID: playerId, Rating: DEFAULT_PLAYER_RATING}
+	}
+	ticket := pQ.enqueue(playerId, rating.Rating)
+	Logger.Info("Player enqueued for matchmaking:", zap.Any("playerId", playerId), zap.Any("rating", rating.Rating))
+
+	timer := time.NewTimer(time.Duration(Conf.Matchmaking.MaxWaitingSeconds) * time.Second)
+	defer timer.Stop()
+	select {
+	case pR := <-ticket.matchedRoomCh:
+		return pR
+	case <-timer.C:
+		if pQ.cancel(ticket) {
+			Logger.Info("Matchmaking timed out:", zap.Any("playerId", playerId), zap.Any("rating", rating.Rating))
+			return nil
+		}
+		// Matched right before cancelled, "matchedRoomCh" is buffered thus never blocks here
+		return <-ticket.matchedRoomCh
+	}
+}
+
+func (pQ *MatchmakingQueue) enqueue(playerId int32, rating int32) *matchmakingTicket {
+	pQ.mux.Lock()
+	defer pQ.mux.Unlock()
+	for i, existing := range pQ.tickets {
+		if existing.playerId == playerId {
+			// The same player reconnected, the stale waiting is ended
+			pQ.tickets = append(pQ.tickets[:i], pQ.tickets[i+1:]...)
+			existing.matchedRoomCh <- nil
+			break
+		}
+	}
+	ticket := &matchmakingTicket{
+		playerId:      playerId,
+		rating:        rating,
+		enqueuedAt:    utils.UnixtimeMilli(),
+		matchedRoomCh: make(chan *Room, 1),
+	}
+	pQ.tickets = append(pQ.tickets, ticket)
+	return ticket
+}
+
+// Returns false if "ticket" is no longer in the queue, i.e. it's already matched or replaced.
+func (pQ *MatchmakingQueue) cancel(ticket *matchmakingTicket) bool {
+	pQ.mux.Lock()
+	defer pQ.mux.Unlock()
+	for i, existing := range pQ.tickets {
+		if existing == ticket {
+			pQ.tickets = append(pQ.tickets[:i], pQ.tickets[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (pQ *MatchmakingQueue) tick() {
+	defer func() {
+		if r := recover(); r != nil {
+			Logger.Error("MatchmakingQueue tick, recovered from: ", zap.Any("panic", r))
+		}
+	}()
+	pQ.mux.Lock()
+	defer pQ.mux.Unlock()
+	groups := formMatchmakingGroups(pQ.tickets, Conf.Room.Capacity, utils.UnixtimeMilli(), &pQ.policy)
+	if 0 == len(groups) {
+		return
+	}
+
+	(*RoomHeapMux).Lock()
+	defer (*RoomHeapMux).Unlock()
+	matched := make(map[*matchmakingTicket]bool, len(pQ.tickets))
+	for _, group := range groups {
+		pR := takeIdleRoom()
+		if nil == pR {
+			Logger.Warn("MatchmakingQueue tick, no idle room for the formed groups:", zap.Any("unassignedGroupCount", len(groups)-len(matched)/Conf.Room.Capacity))
+			break
+		}
+		reserveMatchmadeRoom(pR, group)
+		playerIds := make([]int32, 0, len(group))
+		for _, ticket := range group {
+			playerIds = append(playerIds, ticket.playerId)
+		}
+		for _, ticket := range group {
+			matched[ticket] = true
+			ticket.matchedRoomCh <- pR
+		}
+		Logger.Info("A matchmaking group is formed:", zap.Any("roomId", pR.Id), zap.Any("playerIds", playerIds))
+	}
+
+	remaining := make([]*matchmakingTicket, 0, len(pQ.tickets)-len(matched))
+	for _, ticket := range pQ.tickets {
+		if !matched[ticket] {
+			remaining = append(remaining, ticket)
+		}
+	}
+	pQ.tickets = remaining
+}
+
+/*
+Takes "pR" out of "RoomHeapManagerIns" such that "PopAvailableRoom" never hands it to an unmatched player. The reservation is released upon dismissal, or upon expiry in "Conf.Matchmaking.MaxWaitingSeconds" if the room is not filled by then.
+
+[WARNING] The caller MUST have "RoomHeapMux" locked.
+*/
+func reserveMatchmadeRoom(pR *Room, group []*matchmakingTicket) {
+	RoomHeapManagerIns.remove(pR)
+	pR.matchedPlayerIds = make(map[int32]bool, len(group))
+	for _, ticket := range group {
+		pR.matchedPlayerIds[ticket.playerId] = true
+	}
+	matchedAtMillis := utils.UnixtimeMilli()
+	pR.matchedAtMillis = matchedAtMillis
+	time.AfterFunc(time.Duration(Conf.Matchmaking.MaxWaitingSeconds)*time.Second, func() {
+		checkMatchmakingReservation(pR, matchedAtMillis)
+	})
+}
+
+func checkMatchmakingReservation(pR *Room, matchedAtMillis int64) {
+	(*RoomHeapMux).Lock()
+	defer (*RoomHeapMux).Unlock()
+	if nil == pR.matchedPlayerIds || matchedAtMillis != pR.matchedAtMillis {
+		// Already released, or reserved again for another group
+		return
+	}
+	if RoomBattleStateIns.IDLE != pR.State && RoomBattleStateIns.WAITING != pR.State {
+		// Filled, thus kept until dismissal
+		return
+	}
+	if pR.Capacity == int(pR.EffectivePlayerCount) {
+		return
+	}
+	Logger.Info("A matchmaking reservation expired:", zap.Any("roomId", pR.Id), zap.Any("effectivePlayerCount", pR.EffectivePlayerCount))
+	releaseMatchmadeRoom(pR)
+}
+
+// Puts "pR" back into "RoomHeapManagerIns" as a public room. The caller MUST have "RoomHeapMux" locked.
+func releaseMatchmadeRoom(pR *Room) {
+	if nil == pR.matchedPlayerIds {
+		return
+	}
+	pR.matchedPlayerIds = nil
+	pR.matchedAtMillis = 0
+	if _, existent := (*RoomMapManagerIns)[pR.Id]; existent && 0 > pR.Index {
+		heap.Push(RoomHeapManagerIns, pR)
+	}
+}
+
+/*
+Groups "capacity" tickets of consecutive ratings together when the rating spread of the group is within the window of every member, scanning from the lowest rating.
+*/
+func formMatchmakingGroups(tickets []*matchmakingTicket, capacity int, nowMillis int64, policy *ratingWindowPolicy) [][]*matchmakingTicket {
+	if 0 >= capacity || len(tickets) < capacity {
+		return nil
+	}
+	sorted := make([]*matchmakingTicket, len(tickets))
+	copy(sorted, tickets)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].rating < sorted[j].rating
+	})
+
+	ret := make([][]*matchmakingTicket, 0)
+	for i := 0; i+capacity <= len(sorted); {
+		candidate := sorted[i : i+capacity]
+		spread := candidate[capacity-1].rating - candidate[0].rating
+		accepted := true
+		for _, ticket := range candidate {
+			if spread > policy.windowOf(nowMillis-ticket.enqueuedAt) {
+				accepted = false
+				break
+			}
+		}
+		if accepted {
+			ret = append(ret, candidate)
+			i += capacity
+		} else {
+			i++
+		}
+	}
+	return ret
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestMatchmakingGroupsAreFormedWithinWideningRatingWindows(t *testing.T) {
+	policy := &ratingWindowPolicy{initial: 100, wideningPerSecond: 50, max: 300}
+	tickets := []*matchmakingTicket{
+		&matchmakingTicket{playerId: 1, rating: 1500, enqueuedAt: 0},
+		&matchmakingTicket{playerId: 2, rating: 1900, enqueuedAt: 0},
+		&matchmakingTicket{playerId: 3, rating: 1560, enqueuedAt: 0},
+		&matchmakingTicket{playerId: 4, rating: 1700, enqueuedAt: 0},
+	}
+
+	groups := formMatchmakingGroups(tickets, 2, 0, policy)
+	if 1 != len(groups) || 1 != groups[0][0].playerId || 3 != groups[0][1].playerId {
+		t.Fatalf("Only players 1 and 3 should be matched right after enqueued, got %v groups", len(groups))
+	}
+
+	// The spread 200 between players 4 and 2 is accepted after waiting 2 seconds
+	groups = formMatchmakingGroups(tickets, 2, 2000, policy)
+	if 2 != len(groups) || 4 != groups[1][0].playerId || 2 != groups[1][1].playerId {
+		t.Fatalf("Players 4 and 2 should be matched after the windows are widened, got %v groups", len(groups))
+	}
+
+	// The window is capped
+	if window := policy.windowOf(60000); 300 != window {
+		t.Fatalf("The rating window should be capped at 300, got %v", window)
+	}
+}
+
+func TestEloDeltaFavorsTheUnderdog(t *testing.T) {
+	if delta := calcEloDelta(1500, 1500, ELO_PROVISIONAL_MATCH_COUNT, 1); 10 != delta {
+		t.Fatalf("Winning an even match should gain K/2=10, got %v", delta)
+	}
+	underdogWin := calcEloDelta(1300, 1700, ELO_PROVISIONAL_MATCH_COUNT, 1)
+	favoriteWin := calcEloDelta(1700, 1300, ELO_PROVISIONAL_MATCH_COUNT, 1)
+	if underdogWin <= favoriteWin {
+		t.Fatalf("An underdog should gain more than a favorite by winning, got %v <= %v", underdogWin, favoriteWin)
+	}
+	if delta := calcEloDelta(1500, 1500, 0, 0); -20 != delta {
+		t.Fatalf("Losing an even match as a newcomer should lose K/2=20, got %v", delta)
+	}
+}
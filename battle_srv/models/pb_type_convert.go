@@ -23,6 +23,13 @@ func toPbPlayers(modelInstances map[int32]*Player, withMetaInfo bool) map[int32]
 			Score:          last.Score,
 			Removed:        last.Removed,
 			JoinIndex:      last.JoinIndex,
+			SpeciesId:      last.SpeciesId,
+			TeamId:         last.TeamId,
+			Ready:          last.Ready,
+			RematchVoted:   last.RematchVoted,
+			Hp:             last.Hp,
+			MaxHp:          last.MaxHp,
+			CharacterState: last.CharacterState,
 		}
 		if withMetaInfo {
 			toRet[k].Name = last.Name
@@ -47,6 +47,16 @@ type Player struct {
 	LastSentInputFrameId int32
 	AckingFrameId        int32
 	AckingInputFrameId   int32
+
+	ChecksumMismatchCount             int32
+	ShouldResyncDueToChecksumMismatch bool
+
+	// Smoothed round-trip time by "HeartbeatUpsync" echoes, see "Room.OnHeartbeatReceived", all accessed atomically
+	RttMillis      int32
+	RttVarMillis   int32
+	RttSampleCount int32
+
+	NetStats PlayerNetStats
 }
 
 func ExistPlayerByName(name string) (bool, error) {
@@ -70,6 +70,15 @@ func getFields(t string, fields []string, cond sq.Eq, dest interface{}) error {
 	return err
 }
 
+// Locks the matched row until "tx" ends, or the gap where it would be inserted if not existent.
+func txGetObjForUpdate(tx *sqlx.Tx, t string, cond sq.Eq, dest interface{}) error {
+	query, args, err := sq.Select("*").From(t).Where(cond).Limit(1).Suffix("FOR UPDATE").ToSql()
+	if err != nil {
+		return err
+	}
+	return tx.Get(dest, query, args...)
+}
+
 func getObj(t string, cond sq.Eq, dest interface{}) error {
 	query, args, err := sq.Select("*").From(t).Where(cond).Limit(1).ToSql()
 	Logger.Debug("getObj", zap.String("sql", query), zap.Any("args", args))
@@ -0,0 +1,123 @@
+package models
+
+import (
+	"sort"
+	"sync/atomic"
+)
+
+/*
+Per-player network quality statistics of a battle, reset upon "AddPlayerIfPossible" but kept upon "ReAddPlayerIfPossible" (i.e. accumulated across reconnections within a same battle). All fields are updated atomically, because they're written by "battleMainLoop" as well as the receiving goroutine of the player, and read by the http handlers.
+*/
+type PlayerNetStats struct {
+	ForceConfirmedInputFrameCount int64 // The count of inputFrames whose input of this player was force-confirmed by "forceConfirmationIfApplicable", i.e. never upsynced in time
+	ForcedResyncCount             int64
+	ReconnectionCount             int64
+	DownsyncMsgCount              int64
+	DownsyncBytes                 int64
+}
+
+type PlayerNetStatsSnapshot struct {
+	PlayerId                      int32 `json:"playerId"`
+	JoinIndex                     int32 `json:"joinIndex"`
+	BattleState                   int32 `json:"battleState"`
+	RttMillis                     int32 `json:"rttMillis"` // Smoothed, 0 if "RttSampleCount" is 0, which is always the case unless "AdaptiveInputDelayEnabled"
+	RttVarMillis                  int32 `json:"rttVarMillis"`
+	RttSampleCount                int32 `json:"rttSampleCount"`
+	AckingInputFrameId            int32 `json:"ackingInputFrameId"`
+	AckGapInputFrames             int32 `json:"ackGapInputFrames"` // "room.LastAllConfirmedInputFrameId - AckingInputFrameId", a large value means that the player is lagging behind the room
+	ForceConfirmedInputFrameCount int64 `json:"forceConfirmedInputFrameCount"`
+	ForcedResyncCount             int64 `json:"forcedResyncCount"`
+	ReconnectionCount             int64 `json:"reconnectionCount"`
+	DownsyncMsgCount              int64 `json:"downsyncMsgCount"`
+	DownsyncBytes                 int64 `json:"downsyncBytes"`
+}
+
+type RoomNetStatsSnapshot struct {
+	RoomId                       int32                     `json:"roomId"`
+	State                        int32                     `json:"state"`
+	RenderFrameId                int32                     `json:"renderFrameId"`
+	LastAllConfirmedInputFrameId int32                     `json:"lastAllConfirmedInputFrameId"`
+	Players                      []*PlayerNetStatsSnapshot `json:"players"` // Ordered by "JoinIndex"
+}
+
+func (pS *PlayerNetStats) reset() {
+	atomic.StoreInt64(&pS.ForceConfirmedInputFrameCount, 0)
+	atomic.StoreInt64(&pS.ForcedResyncCount, 0)
+	atomic.StoreInt64(&pS.ReconnectionCount, 0)
+	atomic.StoreInt64(&pS.DownsyncMsgCount, 0)
+	atomic.StoreInt64(&pS.DownsyncBytes, 0)
+}
+
+func (pS *PlayerNetStats) onDownsyncSent(act int32, byteCnt int) {
+	atomic.AddInt64(&pS.DownsyncMsgCount, 1)
+	atomic.AddInt64(&pS.DownsyncBytes, int64(byteCnt))
+	if DOWNSYNC_MSG_ACT_FORCED_RESYNC == act {
+		atomic.AddInt64(&pS.ForcedResyncCount, 1)
+	}
+}
+
+// Called by "battleMainLoop" with the non-confirmed mask returned by "forceConfirmationIfApplicable".
+func (pR *Room) onInputFrameForceConfirmed(unconfirmedMask uint64) {
+	if 0 == unconfirmedMask {
+		return
+	}
+	for _, player := range pR.Players {
+		if 0 < (unconfirmedMask & uint64(1<<uint32(player.JoinIndex-1))) {
+			atomic.AddInt64(&player.NetStats.ForceConfirmedInputFrameCount, 1)
+		}
+	}
+}
+
+func (pR *Room) NetStatsSnapshot() *RoomNetStatsSnapshot {
+	ret := &RoomNetStatsSnapshot{
+		RoomId:                       pR.Id,
+		State:                        atomic.LoadInt32(&pR.State),
+		RenderFrameId:                atomic.LoadInt32(&pR.RenderFrameId),
+		LastAllConfirmedInputFrameId: atomic.LoadInt32(&pR.LastAllConfirmedInputFrameId),
+		Players:                      make([]*PlayerNetStatsSnapshot, 0, len(pR.PlayersArr)),
+	}
+	// [WARNING] Reading "PlayersArr" instead of the map "Players", because the latter might be written concurrently by "AddPlayerIfPossible". The trade-off is that "PlayersArr" is only populated upon "StartBattle", which is fine for in-battle statistics.
+	for _, player := range pR.PlayersArr {
+		if nil == player {
+			continue
+		}
+		pS := &(player.NetStats)
+		ackingInputFrameId := atomic.LoadInt32(&player.AckingInputFrameId)
+		ret.Players = append(ret.Players, &PlayerNetStatsSnapshot{
+			PlayerId:                      player.Id,
+			JoinIndex:                     player.JoinIndex,
+			BattleState:                   atomic.LoadInt32(&player.BattleState),
+			RttMillis:                     atomic.LoadInt32(&player.RttMillis),
+			RttVarMillis:                  atomic.LoadInt32(&player.RttVarMillis),
+			RttSampleCount:                atomic.LoadInt32(&player.RttSampleCount),
+			AckingInputFrameId:            ackingInputFrameId,
+			AckGapInputFrames:             ret.LastAllConfirmedInputFrameId - ackingInputFrameId,
+			ForceConfirmedInputFrameCount: atomic.LoadInt64(&pS.ForceConfirmedInputFrameCount),
+			ForcedResyncCount:             atomic.LoadInt64(&pS.ForcedResyncCount),
+			ReconnectionCount:             atomic.LoadInt64(&pS.ReconnectionCount),
+			DownsyncMsgCount:              atomic.LoadInt64(&pS.DownsyncMsgCount),
+			DownsyncBytes:                 atomic.LoadInt64(&pS.DownsyncBytes),
+		})
+	}
+	sort.Slice(ret.Players, func(i, j int) bool {
+		return ret.Players[i].JoinIndex < ret.Players[j].JoinIndex
+	})
+	return ret
+}
+
+// Ordered by "RoomId", or only the room of "roomId" if positive.
+func AllRoomNetStatsSnapshots(roomId int32) []*RoomNetStatsSnapshot {
+	(*RoomHeapMux).Lock()
+	defer (*RoomHeapMux).Unlock()
+	ret := make([]*RoomNetStatsSnapshot, 0, len(*RoomMapManagerIns))
+	for _, pR := range *RoomMapManagerIns {
+		if 0 < roomId && roomId != pR.Id {
+			continue
+		}
+		ret = append(ret, pR.NetStatsSnapshot())
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].RoomId < ret[j].RoomId
+	})
+	return ret
+}
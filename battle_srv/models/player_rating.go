@@ -0,0 +1,151 @@
+package models
+
+import (
+	"battle_srv/battle"
+	"battle_srv/common/utils"
+	. "battle_srv/protos"
+	"battle_srv/storage"
+	"database/sql"
+	. "dnmshared"
+	"math"
+	"sort"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+const (
+	DEFAULT_PLAYER_RATING       = int32(1500)
+	ELO_K_FACTOR_PROVISIONAL    = float64(40) // Converging faster for newcomers
+	ELO_K_FACTOR                = float64(20)
+	ELO_PROVISIONAL_MATCH_COUNT = int32(20)
+)
+
+// Keyed by the "player.id", a player without any row is rated "DEFAULT_PLAYER_RATING".
+type PlayerRating struct {
+	ID         int32     `db:"id"`
+	Rating     int32     `db:"rating"`
+	MatchCount int32     `db:"match_count"`
+	CreatedAt  int64     `db:"created_at"`
+	UpdatedAt  int64     `db:"updated_at"`
+	DeletedAt  NullInt64 `db:"deleted_at"`
+}
+
+func GetPlayerRatingById(id int32) (*PlayerRating, error) {
+	var p PlayerRating
+	err := getObj("player_rating", sq.Eq{"id": id, "deleted_at": nil}, &p)
+	if err == sql.ErrNoRows {
+		return &PlayerRating{ID: id, Rating: DEFAULT_PLAYER_RATING, MatchCount: 0}, nil
+	}
+	if nil != err {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func getPlayerRatingByIdForUpdate(tx *sqlx.Tx, id int32) (*PlayerRating, error) {
+	var p PlayerRating
+	err := txGetObjForUpdate(tx, "player_rating", sq.Eq{"id": id, "deleted_at": nil}, &p)
+	if err == sql.ErrNoRows {
+		return &PlayerRating{ID: id, Rating: DEFAULT_PLAYER_RATING, MatchCount: 0}, nil
+	}
+	if nil != err {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (p *PlayerRating) upsert(tx *sqlx.Tx) error {
+	now := utils.UnixtimeMilli()
+	query, args, err := sq.Insert("player_rating").
+		Columns("id", "rating", "match_count", "created_at", "updated_at").
+		Values(p.ID, p.Rating, p.MatchCount, now, now).
+		Suffix("ON DUPLICATE KEY UPDATE rating=VALUES(rating), match_count=VALUES(match_count), updated_at=VALUES(updated_at)").
+		ToSql()
+	if nil != err {
+		return err
+	}
+	_, err = tx.Exec(query, args...)
+	return err
+}
+
+/*
+The Elo delta of a player rated "rating" against opponents averaged at "opponentRating", where "actualScore" is 1 for a win, 0.5 for a draw and 0 for a loss.
+*/
+func calcEloDelta(rating, opponentRating int32, matchCount int32, actualScore float64) int32 {
+	kFactor := ELO_K_FACTOR
+	if ELO_PROVISIONAL_MATCH_COUNT > matchCount {
+		kFactor = ELO_K_FACTOR_PROVISIONAL
+	}
+	expectedScore := 1.0 / (1.0 + math.Pow(10, float64(opponentRating-rating)/400.0))
+	return int32(math.Round(kFactor * (actualScore - expectedScore)))
+}
+
+/*
+Updates the ratings of all participants of a settled battle by "battle.WinningTeamIdOf(rdf)", each against the average rating of players of the other teams.
+
+The ratings are read and written in a single transaction with the rows locked, such that concurrent settlements involving the same player never lose an update. Rows are locked by the ascending order of "playerId" to avoid deadlocks between such settlements.
+*/
+func UpdatePlayerRatingsBySettledFrame(rdf *RoomDownsyncFrame) error {
+	tx, err := storage.MySQLManagerIns.Beginx()
+	if nil != err {
+		return err
+	}
+	defer tx.Rollback()
+
+	playerIds := make([]int32, 0, len(rdf.Players))
+	for playerId, _ := range rdf.Players {
+		playerIds = append(playerIds, playerId)
+	}
+	sort.Slice(playerIds, func(i, j int) bool {
+		return playerIds[i] < playerIds[j]
+	})
+	ratings := make(map[int32]*PlayerRating, len(rdf.Players))
+	for _, playerId := range playerIds {
+		rating, err := getPlayerRatingByIdForUpdate(tx, playerId)
+		if nil != err {
+			return err
+		}
+		ratings[playerId] = rating
+	}
+
+	winningTeamId := battle.WinningTeamIdOf(rdf)
+	deltas := make(map[int32]int32, len(rdf.Players))
+	for playerId, player := range rdf.Players {
+		teamId := battle.EffectiveTeamIdOf(player)
+		opponentRatingSum, opponentCount := int32(0), int32(0)
+		for otherPlayerId, other := range rdf.Players {
+			if battle.EffectiveTeamIdOf(other) == teamId {
+				continue
+			}
+			opponentRatingSum += ratings[otherPlayerId].Rating
+			opponentCount++
+		}
+		if 0 == opponentCount {
+			continue
+		}
+		actualScore := float64(0.5)
+		if 0 != winningTeamId {
+			actualScore = 0
+			if winningTeamId == teamId {
+				actualScore = 1
+			}
+		}
+		deltas[playerId] = calcEloDelta(ratings[playerId].Rating, opponentRatingSum/opponentCount, ratings[playerId].MatchCount, actualScore)
+	}
+
+	for playerId, delta := range deltas {
+		rating := ratings[playerId]
+		rating.Rating += delta
+		rating.MatchCount++
+		if err := rating.upsert(tx); nil != err {
+			return err
+		}
+	}
+	if err := tx.Commit(); nil != err {
+		return err
+	}
+	Logger.Info("Player ratings updated:", zap.Any("winningTeamId", winningTeamId), zap.Any("deltas", deltas))
+	return nil
+}
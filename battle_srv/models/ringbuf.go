@@ -45,7 +45,8 @@ func (rb *RingBuffer) Pop() interface{} {
 }
 
 func (rb *RingBuffer) GetByOffset(offsetFromSt int32) interface{} {
-	if 0 == rb.Cnt {
+	if 0 > offsetFromSt || rb.Cnt <= offsetFromSt {
+		// Otherwise an evicted or a not yet put frameId might be mapped into "[St, Ed)" when the buffer is wrapped around
 		return nil
 	}
 	arrIdx := rb.St + offsetFromSt
@@ -71,3 +72,20 @@ func (rb *RingBuffer) GetByOffset(offsetFromSt int32) interface{} {
 func (rb *RingBuffer) GetByFrameId(frameId int32) interface{} {
 	return rb.GetByOffset(frameId - rb.StFrameId)
 }
+
+func (rb *RingBuffer) SetByFrameId(pItem interface{}, frameId int32) bool {
+	if frameId == rb.EdFrameId {
+		rb.Put(pItem)
+		return true
+	}
+	if nil == rb.GetByFrameId(frameId) {
+		// Only existing elements or the one right at "EdFrameId" can be set, i.e. never leaving a gap in the buffer
+		return false
+	}
+	arrIdx := rb.St + (frameId - rb.StFrameId)
+	if arrIdx >= rb.N {
+		arrIdx -= rb.N
+	}
+	rb.Eles[arrIdx] = pItem
+	return true
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestRingBufferSetByFrameIdAcrossWraparound(t *testing.T) {
+	rb := NewRingBuffer(4)
+	for frameId := int32(0); frameId < 4; frameId++ {
+		rb.Put(frameId)
+	}
+	rb.Pop()
+	rb.Pop()
+	rb.Put(int32(4))
+	rb.Put(int32(5)) // Now "0...ed...st...N-1" holding frameIds [2, 6)
+	if 2 != rb.StFrameId || 6 != rb.EdFrameId || rb.Ed > rb.St {
+		t.Fatalf("Expected a wrapped buffer holding [2, 6), got st=%v, ed=%v, stFrameId=%v, edFrameId=%v", rb.St, rb.Ed, rb.StFrameId, rb.EdFrameId)
+	}
+
+	// Replacing the existing ones on both sides of the wraparound
+	for frameId := int32(2); frameId < 6; frameId++ {
+		if !rb.SetByFrameId(100+frameId, frameId) {
+			t.Fatalf("Failed to replace frameId=%v", frameId)
+		}
+	}
+	for frameId := int32(2); frameId < 6; frameId++ {
+		if 100+frameId != rb.GetByFrameId(frameId).(int32) {
+			t.Fatalf("Unexpected element %v for frameId=%v", rb.GetByFrameId(frameId), frameId)
+		}
+	}
+
+	// Neither an evicted one nor a gap can be set
+	if rb.SetByFrameId(int32(101), 1) || rb.SetByFrameId(int32(107), 7) {
+		t.Fatalf("An evicted frameId or a gap shouldn't be set")
+	}
+
+	// Setting right at "EdFrameId" appends
+	rb.Pop()
+	if !rb.SetByFrameId(int32(6), 6) || 7 != rb.EdFrameId || 6 != rb.GetByFrameId(6).(int32) {
+		t.Fatalf("Failed to append frameId=6 by SetByFrameId")
+	}
+}
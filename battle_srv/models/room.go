@@ -1,22 +1,23 @@
 package models
 
 import (
+	"battle_srv/battle"
 	. "battle_srv/common"
 	"battle_srv/common/utils"
+	"battle_srv/metrics"
 	. "battle_srv/protos"
 	. "dnmshared"
-	. "dnmshared/sharedprotos"
 	"encoding/xml"
 	"fmt"
 	"github.com/golang/protobuf/proto"
 	"github.com/gorilla/websocket"
-	"github.com/solarlune/resolv"
 	"go.uber.org/zap"
 	"io/ioutil"
 	"math"
 	"math/rand"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -27,17 +28,23 @@ const (
 	UPSYNC_MSG_ACT_HB_PING             = int32(1)
 	UPSYNC_MSG_ACT_PLAYER_CMD          = int32(2)
 	UPSYNC_MSG_ACT_PLAYER_COLLIDER_ACK = int32(3)
+	UPSYNC_MSG_ACT_PLAYER_READY        = int32(4)
+	UPSYNC_MSG_ACT_REMATCH_VOTE        = int32(5)
 
-	DOWNSYNC_MSG_ACT_HB_REQ         = int32(1)
-	DOWNSYNC_MSG_ACT_INPUT_BATCH    = int32(2)
-	DOWNSYNC_MSG_ACT_BATTLE_STOPPED = int32(3)
-	DOWNSYNC_MSG_ACT_FORCED_RESYNC  = int32(4)
+	DOWNSYNC_MSG_ACT_HB_REQ               = int32(1)
+	DOWNSYNC_MSG_ACT_INPUT_BATCH          = int32(2)
+	DOWNSYNC_MSG_ACT_BATTLE_STOPPED       = int32(3)
+	DOWNSYNC_MSG_ACT_FORCED_RESYNC        = int32(4)
+	DOWNSYNC_MSG_ACT_HB_ECHO              = int32(5)
+	DOWNSYNC_MSG_ACT_INPUT_DELAY_SWITCHED = int32(6)
 
 	DOWNSYNC_MSG_ACT_BATTLE_READY_TO_START = int32(-1)
 	DOWNSYNC_MSG_ACT_BATTLE_START          = int32(0)
 
-	DOWNSYNC_MSG_ACT_PLAYER_ADDED_AND_ACKED   = int32(-98)
-	DOWNSYNC_MSG_ACT_PLAYER_READDED_AND_ACKED = int32(-97)
+	DOWNSYNC_MSG_ACT_PLAYER_ADDED_AND_ACKED     = int32(-98)
+	DOWNSYNC_MSG_ACT_PLAYER_READDED_AND_ACKED   = int32(-97)
+	DOWNSYNC_MSG_ACT_PLAYER_READY_STATE_CHANGED = int32(-96)
+	DOWNSYNC_MSG_ACT_REMATCH_VOTE_STATE_CHANGED = int32(-95)
 )
 
 const (
@@ -45,50 +52,22 @@ const (
 	MAGIC_JOIN_INDEX_INVALID = -1
 )
 
-const (
-	COLLISION_CATEGORY_CONTROLLED_PLAYER = (1 << 1)
-	COLLISION_CATEGORY_BARRIER           = (1 << 2)
-
-	COLLISION_MASK_FOR_CONTROLLED_PLAYER = (COLLISION_CATEGORY_BARRIER)
-	COLLISION_MASK_FOR_BARRIER           = (COLLISION_CATEGORY_CONTROLLED_PLAYER)
-
-	COLLISION_PLAYER_INDEX_PREFIX  = (1 << 17)
-	COLLISION_BARRIER_INDEX_PREFIX = (1 << 16)
-	COLLISION_BULLET_INDEX_PREFIX  = (1 << 15)
-)
-
 const (
 	MAGIC_LAST_SENT_INPUT_FRAME_ID_NORMAL_ADDED = -1
 	MAGIC_LAST_SENT_INPUT_FRAME_ID_READDED      = -2
 )
 
-const (
-	ATK_CHARACTER_STATE_IDLE1   = 0
-	ATK_CHARACTER_STATE_WALKING = 1
-	ATK_CHARACTER_STATE_ATK1    = 2
-	ATK_CHARACTER_STATE_ATKED1  = 3
-)
-
 const (
 	DEFAULT_PLAYER_RADIUS = float64(16)
-)
+	DEFAULT_PLAYER_MAX_HP = int32(100)
 
-// These directions are chosen such that when speed is changed to "(speedX+delta, speedY+delta)" for any of them, the direction is unchanged.
-var DIRECTION_DECODER = [][]int32{
-	{0, 0},
-	{0, +2},
-	{0, -2},
-	{+2, 0},
-	{-2, 0},
-	{+1, +1},
-	{-1, -1},
-	{+1, -1},
-	{-1, +1},
-}
+	MAX_ROOM_CAPACITY = 15 // Limited by "toDiscreteInputsBufferIndex"
+)
 
 type RoomBattleState struct {
 	IDLE                           int32
 	WAITING                        int32
+	READY_CHECK                    int32
 	PREPARE                        int32
 	IN_BATTLE                      int32
 	STOPPING_BATTLE_FOR_SETTLEMENT int32
@@ -106,6 +85,7 @@ func InitRoomBattleStateIns() {
 	RoomBattleStateIns = RoomBattleState{
 		IDLE:                           0,
 		WAITING:                        -1,
+		READY_CHECK:                    10000005,
 		PREPARE:                        10000000,
 		IN_BATTLE:                      10000001,
 		STOPPING_BATTLE_FOR_SETTLEMENT: 10000002,
@@ -122,14 +102,13 @@ func calRoomScore(inRoomPlayerCount int32, roomPlayerCnt int, currentRoomBattleS
 }
 
 type Room struct {
-	Id                    int32
-	Capacity              int
-	collisionSpaceOffsetX float64
-	collisionSpaceOffsetY float64
-	Players               map[int32]*Player
-	PlayersArr            []*Player // ordered by joinIndex
-	Space                 *resolv.Space
-	CollisionSysMap       map[int32]*resolv.Object
+	Id         int32
+	Capacity   int        // Always "Params.Capacity", immutable since creation
+	Params     RoomParams // Immutable since creation, applied upon each "OnDismissed"
+	Players    map[int32]*Player
+	PlayersArr []*Player         // ordered by joinIndex
+	Stage      *battle.Stage     // Immutable during a battle, re-assigned upon each "StartBattle"
+	simulator  *battle.Simulator // Only accessed by the goroutine of "battleMainLoop"
 	/**
 		 * The following `PlayerDownsyncSessionDict` is NOT individually put
 		 * under `type Player struct` for a reason.
@@ -149,15 +128,15 @@ type Room struct {
 	     * Moreover, during the invocation of `PlayerSignalToCloseDict`, the `Player` instance is supposed to be deallocated (though not synchronously).
 	*/
 	PlayerDownsyncSessionDict              map[int32]*websocket.Conn
+	PlayerDownsyncSessionMuxDict           map[int32]*sync.Mutex // gorilla/websocket supports at most one concurrent writer, while both "battleMainLoop" and the receiving goroutine of a player (for "DOWNSYNC_MSG_ACT_HB_ECHO") write to a same session
 	PlayerSignalToCloseDict                map[int32]SignalToCloseConnCbType
 	Score                                  float32
 	State                                  int32
 	Index                                  int
 	RenderFrameId                          int32
-	CurDynamicsRenderFrameId               int32 // [WARNING] The dynamics of backend is ALWAYS MOVING FORWARD BY ALL-CONFIRMED INPUTFRAMES (either by upsync or forced), i.e. no rollback
+	CurDynamicsRenderFrameId               int32 // [WARNING] Unless "BackendRollbackEnabled", the dynamics of backend is ALWAYS MOVING FORWARD BY ALL-CONFIRMED INPUTFRAMES (either by upsync or forced), i.e. no rollback
 	EffectivePlayerCount                   int32
 	DismissalWaitGroup                     sync.WaitGroup
-	Barriers                               map[int32]*Barrier
 	InputsBuffer                           *RingBuffer // Indices are STRICTLY consecutive
 	DiscreteInputsBuffer                   sync.Map    // Indices are NOT NECESSARILY consecutive
 	RenderFrameBuffer                      *RingBuffer
@@ -165,21 +144,44 @@ type Room struct {
 	LastAllConfirmedInputFrameIdWithChange int32
 	LastAllConfirmedInputList              []uint64
 	JoinIndexBooleanArr                    []bool
+	DiscreteChecksumsBuffer                sync.Map // Indices are NOT NECESSARILY consecutive, keyed the same way as "DiscreteInputsBuffer" but by renderFrameId
+	LastChecksumVerifiedRenderFrameId      int32
 
 	BackendDynamicsEnabled       bool
+	BackendRollbackEnabled       bool // Only takes effect when "BackendDynamicsEnabled", the backend then predicts non-all-confirmed inputFrames and rolls back upon a mismatched "InputFrameUpsync"
+	AdaptiveInputDelayEnabled    bool // Chooses "InputDelayFrames" by the measured round-trip time of players at battle start, and renegotiates it by "InputDelayFramesSwitches" during the battle
+	ResyncUponChecksumMismatch   bool // Only takes effect when "BackendDynamicsEnabled"
+	ReplayRecordingEnabled       bool
 	LastRenderFrameIdTriggeredAt int64
 	PlayerDefaultSpeed           int32
+	LoadStats                    RoomLoadStats
 
-	BulletBattleLocalIdCounter      int32
 	dilutedRollbackEstimatedDtNanos int64
-	BattleColliderInfo              // Compositing to send centralized magic numbers
+	pendingRollbackRenderFrameId    int32 // The earliest renderFrameId whose delayed inputFrame was mispredicted, "math.MaxInt32" if none
+	replayHeader                    *BattleReplayHeader
+	replayInputFrames               []*InputFrameDownsync // Indices are STRICTLY consecutive inputFrameIds starting from 0
+	maxReplayInputFrameCount        int32                 // 0 for no limit
+	spectators                      map[int32]*Spectator  // Keyed by playerId
+	spectatorsMux                   sync.Mutex            // Guards "spectators", because it's written by the ws goroutines and iterated by "battleMainLoop"
+	matchedPlayerIds                map[int32]bool        // Non-nil if reserved for a group formed by "MatchmakingQueueIns" and thus out of "RoomHeapManagerIns", only these players are addable then, only accessed with "RoomHeapMux" locked
+	matchedAtMillis                 int64
+	inviteCode                      string     // Non-empty if reserved by "ReservePrivateRoom", only accessed with "RoomHeapMux" locked
+	hostPlayerId                    int32      // Of the "ReservePrivateRoom" caller, only accessed with "RoomHeapMux" locked
+	readyCheckMux                   sync.Mutex // Guards the transitions from READY_CHECK, because they're triggered by the ws goroutines as well as a timer
+	readyCheckEpoch                 int32      // Incremented upon each transition into or from READY_CHECK, such that a stale timeout is ignored
+	readyCheckDeadlineNanos         int64
+	rematchMux                      sync.Mutex // Guards the transitions from the rematch window of IN_SETTLEMENT, because they're triggered by the ws goroutines as well as a timer
+	rematchEpoch                    int32      // Incremented upon each opening or closing of the rematch window, such that a stale timeout is ignored
+	rematchDeadlineNanos            int64      // Non-zero only while the rematch window is open
+	battleStartedAtMillis           int64      // Set upon "onBattleStarted", persisted by "saveBattleRecord"
+	BattleColliderInfo                         // Compositing to send centralized magic numbers
 }
 
 func (pR *Room) updateScore() {
 	pR.Score = calRoomScore(pR.EffectivePlayerCount, pR.Capacity, pR.State)
 }
 
-func (pR *Room) AddPlayerIfPossible(pPlayerFromDbInit *Player, session *websocket.Conn, signalToCloseConnOfThisPlayer SignalToCloseConnCbType) bool {
+func (pR *Room) AddPlayerIfPossible(pPlayerFromDbInit *Player, inviteCode string, session *websocket.Conn, signalToCloseConnOfThisPlayer SignalToCloseConnCbType) bool {
 	playerId := pPlayerFromDbInit.Id
 	// TODO: Any thread-safety concern for accessing "pR" here?
 	if RoomBattleStateIns.IDLE != pR.State && RoomBattleStateIns.WAITING != pR.State {
@@ -190,17 +192,35 @@ func (pR *Room) AddPlayerIfPossible(pPlayerFromDbInit *Player, session *websocke
 		Logger.Warn("AddPlayerIfPossible error, existing in the room.PlayersDict:", zap.Any("playerId", playerId), zap.Any("roomId", pR.Id), zap.Any("roomState", pR.State), zap.Any("roomEffectivePlayerCount", pR.EffectivePlayerCount))
 		return false
 	}
+	if nil != pR.matchedPlayerIds && !pR.matchedPlayerIds[playerId] {
+		Logger.Warn("AddPlayerIfPossible error, the room is reserved for a matchmaking group:", zap.Any("playerId", playerId), zap.Any("roomId", pR.Id), zap.Any("roomState", pR.State))
+		return false
+	}
+	if !pR.acceptsInviteCode(inviteCode) {
+		Logger.Warn("AddPlayerIfPossible error, the room is private and the invite code doesn't match:", zap.Any("playerId", playerId), zap.Any("roomId", pR.Id), zap.Any("roomState", pR.State))
+		return false
+	}
 
 	defer pR.onPlayerAdded(playerId)
 	pPlayerFromDbInit.AckingFrameId = -1
 	pPlayerFromDbInit.AckingInputFrameId = -1
 	pPlayerFromDbInit.LastSentInputFrameId = MAGIC_LAST_SENT_INPUT_FRAME_ID_NORMAL_ADDED
+	pPlayerFromDbInit.ChecksumMismatchCount = 0
+	pPlayerFromDbInit.ShouldResyncDueToChecksumMismatch = false
+	pPlayerFromDbInit.resetRtt()
+	pPlayerFromDbInit.NetStats.reset()
 	pPlayerFromDbInit.BattleState = PlayerBattleStateIns.ADDED_PENDING_BATTLE_COLLIDER_ACK
+	pPlayerFromDbInit.Ready = false
+	pPlayerFromDbInit.RematchVoted = false
 	pPlayerFromDbInit.Speed = pR.PlayerDefaultSpeed          // Hardcoded
 	pPlayerFromDbInit.ColliderRadius = DEFAULT_PLAYER_RADIUS // Hardcoded
+	pPlayerFromDbInit.MaxHp = DEFAULT_PLAYER_MAX_HP          // Hardcoded
+	pPlayerFromDbInit.Hp = pPlayerFromDbInit.MaxHp
+	pPlayerFromDbInit.CharacterState = battle.ATK_CHARACTER_STATE_IDLE1
 
 	pR.Players[playerId] = pPlayerFromDbInit
 	pR.PlayerDownsyncSessionDict[playerId] = session
+	pR.PlayerDownsyncSessionMuxDict[playerId] = new(sync.Mutex)
 	pR.PlayerSignalToCloseDict[playerId] = signalToCloseConnOfThisPlayer
 	return true
 }
@@ -226,52 +246,60 @@ func (pR *Room) ReAddPlayerIfPossible(pTmpPlayerInstance *Player, session *webso
 	pEffectiveInRoomPlayerInstance.AckingFrameId = -1
 	pEffectiveInRoomPlayerInstance.AckingInputFrameId = -1
 	pEffectiveInRoomPlayerInstance.LastSentInputFrameId = MAGIC_LAST_SENT_INPUT_FRAME_ID_READDED
+	pEffectiveInRoomPlayerInstance.ShouldResyncDueToChecksumMismatch = false // Will resync anyway
+	pEffectiveInRoomPlayerInstance.resetRtt()                                // A new connection
+	atomic.AddInt64(&pEffectiveInRoomPlayerInstance.NetStats.ReconnectionCount, 1)
 	pEffectiveInRoomPlayerInstance.BattleState = PlayerBattleStateIns.READDED_PENDING_BATTLE_COLLIDER_ACK
 	pEffectiveInRoomPlayerInstance.Speed = pR.PlayerDefaultSpeed          // Hardcoded
 	pEffectiveInRoomPlayerInstance.ColliderRadius = DEFAULT_PLAYER_RADIUS // Hardcoded
 
 	pR.PlayerDownsyncSessionDict[playerId] = session
+	pR.PlayerDownsyncSessionMuxDict[playerId] = new(sync.Mutex)
 	pR.PlayerSignalToCloseDict[playerId] = signalToCloseConnOfThisPlayer
 
 	Logger.Warn("ReAddPlayerIfPossible finished.", zap.Any("roomId", pR.Id), zap.Any("playerId", playerId), zap.Any("joinIndex", pEffectiveInRoomPlayerInstance.JoinIndex), zap.Any("playerBattleState", pEffectiveInRoomPlayerInstance.BattleState), zap.Any("roomState", pR.State), zap.Any("roomEffectivePlayerCount", pR.EffectivePlayerCount), zap.Any("AckingFrameId", pEffectiveInRoomPlayerInstance.AckingFrameId), zap.Any("AckingInputFrameId", pEffectiveInRoomPlayerInstance.AckingInputFrameId), zap.Any("LastSentInputFrameId", pEffectiveInRoomPlayerInstance.LastSentInputFrameId))
 	return true
 }
 
-func (pR *Room) ChooseStage() error {
-	/*
-	 * We use the verb "refresh" here to imply that upon invocation of this function, all colliders will be recovered if they were destroyed in the previous battle.
-	 *
-	 * -- YFLu, 2019-09-04
-	 */
+var randomStageNameList = []string{"dungeon" /*"dungeon", "simple", "richsoil" */} // Hardcoded temporarily. -- YFLu
+
+type parsedStage struct {
+	stageDiscreteW        int32
+	stageDiscreteH        int32
+	stageTileW            int32
+	stageTileH            int32
+	strToVec2DListMap     StrToVec2DListMap
+	strToPolygon2DListMap StrToPolygon2DListMap
+	battleMode            int32
+}
+
+/*
+Parses the tmx file of "stageName" along with its tsx files, returns an error unless the stage has a barrier, a known "battleMode" and a starting position for every joinIndex up to "capacity" grouped by "teamCount".
+*/
+func parseStage(stageName string, capacity int, teamCount int32) (*parsedStage, error) {
 	pwd, err := os.Getwd()
 	if nil != err {
-		panic(err)
+		return nil, err
 	}
 
-	rand.Seed(time.Now().Unix())
-	stageNameList := []string{"dungeon" /*"dungeon", "simple", "richsoil" */}
-	chosenStageIndex := rand.Int() % len(stageNameList) // Hardcoded temporarily. -- YFLu
-
-	pR.StageName = stageNameList[chosenStageIndex]
-
 	relativePathForAllStages := "../frontend/assets/resources/map"
-	relativePathForChosenStage := fmt.Sprintf("%s/%s", relativePathForAllStages, pR.StageName)
+	relativePathForChosenStage := fmt.Sprintf("%s/%s", relativePathForAllStages, stageName)
 
 	pTmxMapIns := &TmxMap{}
 
 	absDirPathContainingDirectlyTmxFile := filepath.Join(pwd, relativePathForChosenStage)
 	absTmxFilePath := fmt.Sprintf("%s/map.tmx", absDirPathContainingDirectlyTmxFile)
 	if !filepath.IsAbs(absTmxFilePath) {
-		panic("Tmx filepath must be absolute!")
+		return nil, fmt.Errorf("Tmx filepath must be absolute!")
 	}
 
 	byteArr, err := ioutil.ReadFile(absTmxFilePath)
 	if nil != err {
-		panic(err)
+		return nil, err
 	}
 	err = xml.Unmarshal(byteArr, pTmxMapIns)
 	if nil != err {
-		panic(err)
+		return nil, err
 	}
 
 	// Obtain the content of `gidBoundariesMap`.
@@ -280,15 +308,15 @@ func (pR *Room) ChooseStage() error {
 		relativeTsxFilePath := fmt.Sprintf("%s/%s", filepath.Join(pwd, relativePathForChosenStage), tileset.Source) // Note that "TmxTileset.Source" can be a string of "relative path".
 		absTsxFilePath, err := filepath.Abs(relativeTsxFilePath)
 		if nil != err {
-			panic(err)
+			return nil, err
 		}
 		if !filepath.IsAbs(absTsxFilePath) {
-			panic("Filepath must be absolute!")
+			return nil, fmt.Errorf("Filepath must be absolute!")
 		}
 
 		byteArrOfTsxFile, err := ioutil.ReadFile(absTsxFilePath)
 		if nil != err {
-			panic(err)
+			return nil, err
 		}
 
 		DeserializeTsxToColliderDict(pTmxMapIns, byteArrOfTsxFile, int(tileset.FirstGid), gidBoundariesMap)
@@ -296,50 +324,147 @@ func (pR *Room) ChooseStage() error {
 
 	stageDiscreteW, stageDiscreteH, stageTileW, stageTileH, strToVec2DListMap, strToPolygon2DListMap, err := ParseTmxLayersAndGroups(pTmxMapIns, gidBoundariesMap)
 	if nil != err {
-		panic(err)
+		return nil, err
 	}
 
-	pR.StageDiscreteW = stageDiscreteW
-	pR.StageDiscreteH = stageDiscreteH
-	pR.StageTileW = stageTileW
-	pR.StageTileH = stageTileH
-	pR.StrToVec2DListMap = strToVec2DListMap
-	pR.StrToPolygon2DListMap = strToPolygon2DListMap
+	if _, existent := strToPolygon2DListMap["Barrier"]; !existent {
+		return nil, fmt.Errorf("No barrier found for stage=%v", stageName)
+	}
 
-	barrierPolygon2DList := *(strToPolygon2DListMap["Barrier"])
+	for joinIndex := int32(1); joinIndex <= int32(capacity); joinIndex++ {
+		if nil == battle.PlayerStartingPosition(strToVec2DListMap, teamCount, joinIndex) {
+			return nil, fmt.Errorf("No starting position found for joinIndex=%v, teamCount=%v of stage=%v", joinIndex, teamCount, stageName)
+		}
+	}
 
-	var barrierLocalIdInBattle int32 = 0
-	for _, polygon2DUnaligned := range barrierPolygon2DList.Eles {
-		polygon2D := AlignPolygon2DToBoundingBox(polygon2DUnaligned)
-		/*
-		   // For debug-printing only.
-		   Logger.Info("ChooseStage printing polygon2D for barrierPolygon2DList", zap.Any("barrierLocalIdInBattle", barrierLocalIdInBattle), zap.Any("polygon2D.Anchor", polygon2D.Anchor), zap.Any("polygon2D.Points", polygon2D.Points))
-		*/
-		pR.Barriers[barrierLocalIdInBattle] = &Barrier{
-			Boundary: polygon2D,
+	// The battle mode is chosen by the map-level property "battleMode" of the tmx file, defaulted to top-down
+	battleMode := int32(battle.BATTLE_MODE_TOP_DOWN)
+	if battleModeName, existent := pTmxMapIns.PropertyValue("battleMode"); existent {
+		switch battleModeName {
+		case "topDown":
+		case "platformer":
+			battleMode = battle.BATTLE_MODE_PLATFORMER
+		default:
+			return nil, fmt.Errorf("Unknown battleMode=%v for stage=%v", battleModeName, stageName)
 		}
+	}
+
+	return &parsedStage{
+		stageDiscreteW:        stageDiscreteW,
+		stageDiscreteH:        stageDiscreteH,
+		stageTileW:            stageTileW,
+		stageTileH:            stageTileH,
+		strToVec2DListMap:     strToVec2DListMap,
+		strToPolygon2DListMap: strToPolygon2DListMap,
+		battleMode:            battleMode,
+	}, nil
+}
+
+func (pR *Room) ChooseStage() error {
+	/*
+	 * We use the verb "refresh" here to imply that upon invocation of this function, all colliders will be recovered if they were destroyed in the previous battle.
+	 *
+	 * -- YFLu, 2019-09-04
+	 */
+	if "" != pR.Params.StageName {
+		pR.StageName = pR.Params.StageName
+	} else {
+		rand.Seed(time.Now().Unix())
+		chosenStageIndex := rand.Int() % len(randomStageNameList)
+
+		pR.StageName = randomStageNameList[chosenStageIndex]
+	}
+
+	pStage, err := parseStage(pR.StageName, pR.Capacity, pR.TeamCount)
+	if nil != err {
+		// Not expected since every candidate stage has been parsed by "RoomParams.Validate" upon "CreateRoom"
+		panic(err)
+	}
 
-		barrierLocalIdInBattle++
+	pR.StageDiscreteW = pStage.stageDiscreteW
+	pR.StageDiscreteH = pStage.stageDiscreteH
+	pR.StageTileW = pStage.stageTileW
+	pR.StageTileH = pStage.stageTileH
+	pR.StrToVec2DListMap = pStage.strToVec2DListMap
+	pR.StrToPolygon2DListMap = pStage.strToPolygon2DListMap
+
+	pR.BattleMode = pStage.battleMode
+	pR.GravityY, pR.JumpingInitVelY, pR.MaxFallingVelY = 0, 0, 0
+	if battle.BATTLE_MODE_PLATFORMER == pR.BattleMode {
+		pR.GravityY = int32(float64(0.5) * pR.WorldToVirtualGridRatio) // in virtual grids per frame per frame
+		pR.JumpingInitVelY = int32(float64(8) * pR.WorldToVirtualGridRatio)
+		pR.MaxFallingVelY = int32(float64(8) * pR.WorldToVirtualGridRatio) // No more than "DEFAULT_PLAYER_RADIUS" to avoid tunneling through a thin platform
 	}
 
 	return nil
 }
 
+func (pR *Room) BuildBattleColliderInfo(playerBattleState int32) *BattleColliderInfo {
+	return &BattleColliderInfo{
+		BoundRoomId:           pR.Id,
+		StageName:             pR.StageName,
+		StrToVec2DListMap:     pR.StrToVec2DListMap,
+		StrToPolygon2DListMap: pR.StrToPolygon2DListMap,
+		StageDiscreteW:        pR.StageDiscreteW,
+		StageDiscreteH:        pR.StageDiscreteH,
+		StageTileW:            pR.StageTileW,
+		StageTileH:            pR.StageTileH,
+
+		IntervalToPing:                  int32(Constants.Ws.IntervalToPing),
+		WillKickIfInactiveFor:           int32(Constants.Ws.WillKickIfInactiveFor),
+		BattleDurationNanos:             pR.BattleDurationNanos,
+		ServerFps:                       pR.ServerFps,
+		InputDelayFrames:                pR.InputDelayFrames,
+		InputScaleFrames:                pR.InputScaleFrames,
+		NstDelayFrames:                  pR.NstDelayFrames,
+		InputFrameUpsyncDelayTolerance:  pR.InputFrameUpsyncDelayTolerance,
+		MaxChasingRenderFramesPerUpdate: pR.MaxChasingRenderFramesPerUpdate,
+		PlayerBattleState:               playerBattleState,
+		RollbackEstimatedDtMillis:       pR.RollbackEstimatedDtMillis,
+		RollbackEstimatedDtNanos:        pR.RollbackEstimatedDtNanos,
+
+		WorldToVirtualGridRatio: pR.WorldToVirtualGridRatio,
+		VirtualGridToWorldRatio: pR.VirtualGridToWorldRatio,
+
+		SpAtkLookupFrames: pR.SpAtkLookupFrames,
+		RenderCacheSize:   pR.RenderCacheSize,
+		MeleeSkillConfig:  pR.MeleeSkillConfig,
+
+		InputDelayFramesSwitches: pR.InputDelayFramesSwitches,
+		CharacterSkillBindings:   pR.CharacterSkillBindings,
+		KoRespawnFrames:          pR.KoRespawnFrames,
+		FireballSkillConfig:      pR.FireballSkillConfig,
+		InputEncodingVersion:     battle.INPUT_ENCODING_VERSION,
+		BattleMode:               pR.BattleMode,
+		GravityY:                 pR.GravityY,
+		JumpingInitVelY:          pR.JumpingInitVelY,
+		MaxFallingVelY:           pR.MaxFallingVelY,
+
+		FixedPointCollisionEnabled: pR.FixedPointCollisionEnabled,
+		PlayerCollisionEnabled:     pR.PlayerCollisionEnabled,
+		TeamCount:                  pR.TeamCount,
+		FriendlyFireEnabled:        pR.FriendlyFireEnabled,
+	}
+}
+
 func (pR *Room) ConvertToInputFrameId(renderFrameId int32, inputDelayFrames int32) int32 {
-	// Specifically when "renderFrameId < inputDelayFrames", the result is 0.
-	return ((renderFrameId - inputDelayFrames) >> pR.InputScaleFrames)
+	return battle.ConvertToInputFrameId(renderFrameId, inputDelayFrames, pR.InputScaleFrames)
 }
 
 func (pR *Room) ConvertToGeneratingRenderFrameId(inputFrameId int32) int32 {
-	return (inputFrameId << pR.InputScaleFrames)
+	return battle.ConvertToGeneratingRenderFrameId(inputFrameId, pR.InputScaleFrames)
+}
+
+func (pR *Room) ConvertToDelayedInputFrameId(renderFrameId int32) int32 {
+	return battle.ConvertToScheduledInputFrameId(renderFrameId, pR.InputDelayFrames, pR.InputDelayFramesSwitches, pR.InputScaleFrames)
 }
 
-func (pR *Room) ConvertToFirstUsedRenderFrameId(inputFrameId int32, inputDelayFrames int32) int32 {
-	return ((inputFrameId << pR.InputScaleFrames) + inputDelayFrames)
+func (pR *Room) ConvertToFirstUsedRenderFrameId(inputFrameId int32) int32 {
+	return battle.ConvertToScheduledFirstUsedRenderFrameId(inputFrameId, pR.InputDelayFrames, pR.InputDelayFramesSwitches, pR.InputScaleFrames)
 }
 
-func (pR *Room) ConvertToLastUsedRenderFrameId(inputFrameId int32, inputDelayFrames int32) int32 {
-	return ((inputFrameId << pR.InputScaleFrames) + inputDelayFrames + (1 << pR.InputScaleFrames) - 1)
+func (pR *Room) ConvertToLastUsedRenderFrameId(inputFrameId int32) int32 {
+	return battle.ConvertToScheduledLastUsedRenderFrameId(inputFrameId, pR.InputDelayFrames, pR.InputDelayFramesSwitches, pR.InputScaleFrames)
 }
 
 func (pR *Room) RenderFrameBufferString() string {
@@ -371,12 +496,13 @@ func (pR *Room) InputsBufferString(allDetails bool) string {
 }
 
 func (pR *Room) StartBattle() {
-	if RoomBattleStateIns.WAITING != pR.State {
-		Logger.Warn("[StartBattle] Battle not started due to not being WAITING!", zap.Any("roomId", pR.Id), zap.Any("roomState", pR.State))
+	if RoomBattleStateIns.WAITING != pR.State && RoomBattleStateIns.READY_CHECK != pR.State {
+		Logger.Warn("[StartBattle] Battle not started due to not being WAITING or READY_CHECK!", zap.Any("roomId", pR.Id), zap.Any("roomState", pR.State))
 		return
 	}
 
 	pR.RenderFrameId = 0
+	pR.LoadStats.reset()
 
 	// Initialize the "collisionSys" as well as "RenderFrameBuffer"
 	pR.CurDynamicsRenderFrameId = 0
@@ -388,11 +514,18 @@ func (pR *Room) StartBattle() {
 	pR.RenderFrameBuffer.Put(kickoffFrame)
 
 	// Refresh "Colliders"
-	spaceW := pR.StageDiscreteW * pR.StageTileW
-	spaceH := pR.StageDiscreteH * pR.StageTileH
+	pR.refreshColliders()
 
-	pR.collisionSpaceOffsetX, pR.collisionSpaceOffsetY = float64(spaceW)*0.5, float64(spaceH)*0.5
-	pR.refreshColliders(spaceW, spaceH)
+	pR.InputDelayFramesSwitches = nil
+	if pR.AdaptiveInputDelayEnabled {
+		pR.InputDelayFrames = pR.chooseInputDelayFrames(pR.InputDelayFrames)
+		pR.NstDelayFrames = (pR.InputDelayFrames >> 1)
+		Logger.Info("Input delay chosen at battle start:", zap.Any("roomId", pR.Id), zap.Any("inputDelayFrames", pR.InputDelayFrames))
+	}
+
+	if pR.ReplayRecordingEnabled {
+		pR.startReplayRecording(pR.BuildBattleColliderInfo(PlayerBattleStateIns.ACTIVE), kickoffFrame)
+	}
 
 	/**
 	 * Will be triggered from a goroutine which executes the critical `Room.AddPlayerIfPossible`, thus the `battleMainLoop` should be detached.
@@ -426,6 +559,15 @@ func (pR *Room) StartBattle() {
 				return
 			}
 
+			if pR.BackendDynamicsEnabled {
+				if decidedRdf := pR.battleDecidedByKo(); nil != decidedRdf {
+					Logger.Info(fmt.Sprintf("The `battleMainLoop` for roomId=%v is stopped@renderFrameId=%v, decided by knock-outs@renderFrameId=%v", pR.Id, pR.RenderFrameId, decidedRdf.Id))
+					pR.syncPlayersFromRenderFrame(decidedRdf)
+					pR.StopBattleForSettlement()
+					return
+				}
+			}
+
 			if swapped := atomic.CompareAndSwapInt32(&pR.State, RoomBattleStateIns.IN_BATTLE, RoomBattleStateIns.IN_BATTLE); !swapped {
 				return
 			}
@@ -436,11 +578,20 @@ func (pR *Room) StartBattle() {
 				pR.prefabInputFrameDownsync(noDelayInputFrameId)
 			}
 
+			if pR.AdaptiveInputDelayEnabled {
+				pR.renegotiateInputDelayIfApplicable()
+			}
+
 			pR.markConfirmationIfApplicable()
 			unconfirmedMask := uint64(0)
 			if pR.BackendDynamicsEnabled {
 				// Force setting all-confirmed of buffered inputFrames periodically
 				unconfirmedMask = pR.forceConfirmationIfApplicable()
+				pR.onInputFrameForceConfirmed(unconfirmedMask)
+			}
+
+			if pR.ReplayRecordingEnabled {
+				pR.recordAllConfirmedInputFrames()
 			}
 
 			upperToSendInputFrameId := atomic.LoadInt32(&(pR.LastAllConfirmedInputFrameId))
@@ -459,18 +610,29 @@ func (pR *Room) StartBattle() {
 
 			dynamicsDuration := int64(0)
 			if pR.BackendDynamicsEnabled {
-				if 0 <= pR.LastAllConfirmedInputFrameId {
+				if 0 <= pR.LastAllConfirmedInputFrameId || pR.BackendRollbackEnabled {
 					dynamicsStartedAt := utils.UnixtimeNano()
-					// Apply "all-confirmed inputFrames" to move forward "pR.CurDynamicsRenderFrameId"
-					nextDynamicsRenderFrameId := pR.ConvertToLastUsedRenderFrameId(pR.LastAllConfirmedInputFrameId, pR.InputDelayFrames)
+					nextDynamicsRenderFrameId := pR.CurDynamicsRenderFrameId
+					if 0 <= pR.LastAllConfirmedInputFrameId {
+						// Apply "all-confirmed inputFrames" to move forward "pR.CurDynamicsRenderFrameId"
+						nextDynamicsRenderFrameId = pR.ConvertToLastUsedRenderFrameId(pR.LastAllConfirmedInputFrameId)
+					}
+					if pR.BackendRollbackEnabled {
+						pR.rollbackIfApplicable()
+						// Also apply the predicted inputFrames to keep up with "pR.RenderFrameId", they'll be re-applied upon any mismatched "InputFrameUpsync"
+						if nextDynamicsRenderFrameId < pR.RenderFrameId {
+							nextDynamicsRenderFrameId = pR.RenderFrameId
+						}
+					}
 					Logger.Debug(fmt.Sprintf("roomId=%v, room.RenderFrameId=%v, LastAllConfirmedInputFrameId=%v, InputDelayFrames=%v, nextDynamicsRenderFrameId=%v", pR.Id, pR.RenderFrameId, pR.LastAllConfirmedInputFrameId, pR.InputDelayFrames, nextDynamicsRenderFrameId))
-					pR.applyInputFrameDownsyncDynamics(pR.CurDynamicsRenderFrameId, nextDynamicsRenderFrameId, pR.collisionSpaceOffsetX, pR.collisionSpaceOffsetY)
+					pR.applyInputFrameDownsyncDynamics(pR.CurDynamicsRenderFrameId, nextDynamicsRenderFrameId)
 					dynamicsDuration = utils.UnixtimeNano() - dynamicsStartedAt
+					pR.verifyChecksumsIfApplicable()
 				}
 
 				// [WARNING] The following inequality are seldom true, but just to avoid that in good network condition the frontend resyncs itself to a "too advanced frontend.renderFrameId", and then starts upsyncing "too advanced inputFrameId".
-				if refRenderFrameId > pR.CurDynamicsRenderFrameId {
-					refRenderFrameId = pR.CurDynamicsRenderFrameId
+				if lastSettledRenderFrameId := pR.lastSettledRenderFrameId(); refRenderFrameId > lastSettledRenderFrameId {
+					refRenderFrameId = lastSettledRenderFrameId
 				}
 			}
 
@@ -494,7 +656,7 @@ func (pR *Room) StartBattle() {
 
 					if MAGIC_LAST_SENT_INPUT_FRAME_ID_READDED == player.LastSentInputFrameId {
 						// A rejoined player, should guarantee that when it resyncs to "refRenderFrameId" a matching inputFrame to apply exists
-						candidateToSendInputFrameId = pR.ConvertToInputFrameId(refRenderFrameId, pR.InputDelayFrames)
+						candidateToSendInputFrameId = pR.ConvertToDelayedInputFrameId(refRenderFrameId)
 						Logger.Warn(fmt.Sprintf("Resetting refRenderFrame for rejoined player: roomId=%v, playerId=%v, refRenderFrameId=%v, candidateToSendInputFrameId=%v, upperToSendInputFrameId=%v, lastSentInputFrameId=%v, playerAckingInputFrameId=%v", pR.Id, playerId, refRenderFrameId, candidateToSendInputFrameId, upperToSendInputFrameId, player.LastSentInputFrameId, player.AckingInputFrameId))
 					}
 
@@ -528,13 +690,15 @@ func (pR *Room) StartBattle() {
 					shouldResync1 := (MAGIC_LAST_SENT_INPUT_FRAME_ID_READDED == player.LastSentInputFrameId)
 					shouldResync2 := (0 < (unconfirmedMask & uint64(1<<uint32(player.JoinIndex-1)))) // This condition is critical, if we don't send resync upon this condition, the "reconnected or slowly-clocking player" might never get its input synced
 					// shouldResync2 := (0 < unconfirmedMask) // An easier version of the above, might keep sending "refRenderFrame"s to still connected players when any player is disconnected
-					if pR.BackendDynamicsEnabled && (shouldResync1 || shouldResync2) {
+					shouldResync3 := player.ShouldResyncDueToChecksumMismatch
+					if pR.BackendDynamicsEnabled && (shouldResync1 || shouldResync2 || shouldResync3) {
 						tmp := pR.RenderFrameBuffer.GetByFrameId(refRenderFrameId)
 						if nil == tmp {
 							panic(fmt.Sprintf("Required refRenderFrameId=%v for roomId=%v, playerId=%v, candidateToSendInputFrameId=%v doesn't exist! InputsBuffer=%v, RenderFrameBuffer=%v", refRenderFrameId, pR.Id, playerId, candidateToSendInputFrameId, pR.InputsBufferString(false), pR.RenderFrameBufferString()))
 						}
 						refRenderFrame := tmp.(*RoomDownsyncFrame)
 						pR.sendSafely(refRenderFrame, toSendInputFrames, DOWNSYNC_MSG_ACT_FORCED_RESYNC, playerId)
+						player.ShouldResyncDueToChecksumMismatch = false
 					} else {
 						pR.sendSafely(nil, toSendInputFrames, DOWNSYNC_MSG_ACT_INPUT_BATCH, playerId)
 					}
@@ -542,6 +706,10 @@ func (pR *Room) StartBattle() {
 				}
 			}
 
+			if pR.BackendDynamicsEnabled {
+				pR.downsyncToSpectators(refRenderFrameId, upperToSendInputFrameId)
+			}
+
 			if pR.BackendDynamicsEnabled {
 				// Evict no longer required "RenderFrameBuffer"
 				for pR.RenderFrameBuffer.N < pR.RenderFrameBuffer.Cnt || (0 < pR.RenderFrameBuffer.Cnt && pR.RenderFrameBuffer.StFrameId < refRenderFrameId) {
@@ -549,7 +717,7 @@ func (pR *Room) StartBattle() {
 				}
 			}
 
-			minToKeepInputFrameId := pR.ConvertToInputFrameId(refRenderFrameId, pR.InputDelayFrames) - pR.SpAtkLookupFrames
+			minToKeepInputFrameId := pR.ConvertToDelayedInputFrameId(refRenderFrameId) - pR.SpAtkLookupFrames
 			/*
 			   [WARNING]
 			   The following updates to "minToKeepInputFrameId" is necessary because when "false == pR.BackendDynamicsEnabled", the variable "refRenderFrameId" is not well defined.
@@ -577,7 +745,13 @@ func (pR *Room) StartBattle() {
 
 			pR.RenderFrameId++
 			elapsedInCalculation := (utils.UnixtimeNano() - stCalculation)
-			if elapsedInCalculation > pR.dilutedRollbackEstimatedDtNanos {
+			isSlowFrame := (elapsedInCalculation > pR.dilutedRollbackEstimatedDtNanos)
+			pR.LoadStats.onRenderFrameCalculated(elapsedInCalculation, dynamicsDuration, isSlowFrame)
+			metrics.RenderFrameDurationSeconds.Observe(float64(elapsedInCalculation) / 1e9)
+			if 0 < dynamicsDuration {
+				metrics.DynamicsDurationSeconds.Observe(float64(dynamicsDuration) / 1e9)
+			}
+			if isSlowFrame {
 				Logger.Warn(fmt.Sprintf("SLOW FRAME! Elapsed time statistics: roomId=%v, room.RenderFrameId=%v, elapsedInCalculation=%v ns, dynamicsDuration=%v ns, dilutedRollbackEstimatedDtNanos=%v", pR.Id, pR.RenderFrameId, elapsedInCalculation, dynamicsDuration, pR.dilutedRollbackEstimatedDtNanos))
 			}
 			time.Sleep(time.Duration(pR.dilutedRollbackEstimatedDtNanos - elapsedInCalculation))
@@ -591,7 +765,7 @@ func (pR *Room) StartBattle() {
 }
 
 func (pR *Room) toDiscreteInputsBufferIndex(inputFrameId int32, joinIndex int32) int32 {
-	return (inputFrameId << 2) + joinIndex // allowing joinIndex upto 15
+	return (inputFrameId << 4) + joinIndex // allowing joinIndex upto 15
 }
 
 func (pR *Room) OnBattleCmdReceived(pReq *WsReq) {
@@ -617,6 +791,10 @@ func (pR *Room) OnBattleCmdReceived(pReq *WsReq) {
 		panic(fmt.Sprintf("Failed to update AckingInputFrameId to %v for roomId=%v, playerId=%v", ackingInputFrameId, pR.Id, playerId))
 	}
 
+	if nil != pReq.Checksum {
+		pR.onChecksumReceived(pReq.Checksum, playerId)
+	}
+
 	for _, inputFrameUpsync := range inputFrameUpsyncBatch {
 		clientInputFrameId := inputFrameUpsync.InputFrameId
 		if clientInputFrameId < pR.InputsBuffer.StFrameId {
@@ -624,6 +802,10 @@ func (pR *Room) OnBattleCmdReceived(pReq *WsReq) {
 			Logger.Debug(fmt.Sprintf("Omitting obsolete inputFrameUpsync: roomId=%v, playerId=%v, clientInputFrameId=%v, InputsBuffer=%v", pR.Id, playerId, clientInputFrameId, pR.InputsBufferString(false)))
 			continue
 		}
+		if !battle.IsValidInput(inputFrameUpsync.Encoded) {
+			Logger.Warn(fmt.Sprintf("Omitting invalid inputFrameUpsync: roomId=%v, playerId=%v, clientInputFrameId=%v, encoded=%v", pR.Id, playerId, clientInputFrameId, inputFrameUpsync.Encoded))
+			continue
+		}
 		bufIndex := pR.toDiscreteInputsBufferIndex(clientInputFrameId, pReq.JoinIndex)
 		pR.DiscreteInputsBuffer.Store(bufIndex, inputFrameUpsync)
 
@@ -631,6 +813,88 @@ func (pR *Room) OnBattleCmdReceived(pReq *WsReq) {
 	}
 }
 
+func (pR *Room) onChecksumReceived(checksum *RenderFrameChecksum, playerId int32) {
+	lastChecksumVerifiedRenderFrameId := atomic.LoadInt32(&(pR.LastChecksumVerifiedRenderFrameId))
+	if checksum.RenderFrameId <= lastChecksumVerifiedRenderFrameId {
+		// Otherwise it'd never be verified and thus never be removed from "pR.DiscreteChecksumsBuffer"
+		Logger.Debug(fmt.Sprintf("Omitting obsolete checksum: roomId=%v, playerId=%v, renderFrameId=%v, lastChecksumVerifiedRenderFrameId=%v", pR.Id, playerId, checksum.RenderFrameId, lastChecksumVerifiedRenderFrameId))
+		return
+	}
+	if checksum.RenderFrameId > lastChecksumVerifiedRenderFrameId+pR.RenderCacheSize {
+		// Such a renderFrame would've been evicted from "pR.RenderFrameBuffer" before verified, a frontend never settles that far ahead of the backend
+		Logger.Warn(fmt.Sprintf("Omitting too advanced checksum: roomId=%v, playerId=%v, renderFrameId=%v, lastChecksumVerifiedRenderFrameId=%v", pR.Id, playerId, checksum.RenderFrameId, lastChecksumVerifiedRenderFrameId))
+		return
+	}
+	player, existent := pR.Players[playerId]
+	if !existent {
+		return
+	}
+	bufIndex := pR.toDiscreteInputsBufferIndex(checksum.RenderFrameId, player.JoinIndex)
+	pR.DiscreteChecksumsBuffer.Store(bufIndex, checksum)
+}
+
+func (pR *Room) verifyChecksumsIfApplicable() {
+	if 0 > pR.LastAllConfirmedInputFrameId {
+		return
+	}
+	// Only renderFrames generated by all-confirmed inputFrames are verifiable, i.e. never a predicted one
+	upperRenderFrameId := pR.lastSettledRenderFrameId()
+	for renderFrameId := pR.LastChecksumVerifiedRenderFrameId + 1; renderFrameId <= upperRenderFrameId; renderFrameId++ {
+		var rdf *RoomDownsyncFrame = nil
+		if tmp := pR.RenderFrameBuffer.GetByFrameId(renderFrameId); nil != tmp {
+			rdf = tmp.(*RoomDownsyncFrame)
+		}
+		serverChecksum, serverChecksumCalculated := uint64(0), false
+		for playerId, player := range pR.Players {
+			if 0 >= player.JoinIndex {
+				continue
+			}
+			bufIndex := pR.toDiscreteInputsBufferIndex(renderFrameId, player.JoinIndex)
+			tmp, loaded := pR.DiscreteChecksumsBuffer.LoadAndDelete(bufIndex)
+			if !loaded || nil == rdf {
+				// Deleted anyway even if "rdf" is no longer available
+				continue
+			}
+			clientChecksum := tmp.(*RenderFrameChecksum)
+			if !serverChecksumCalculated {
+				serverChecksum, serverChecksumCalculated = battle.Checksum(rdf), true
+			}
+			if serverChecksum == clientChecksum.Checksum {
+				continue
+			}
+			mismatchCount := atomic.AddInt32(&(player.ChecksumMismatchCount), 1)
+			atomic.AddInt64(&(pR.LoadStats.ChecksumMismatchCount), 1)
+			if 0 == (mismatchCount & (mismatchCount - 1)) {
+				// Only the 1st, 2nd, 4th, 8th... mismatches of each player are logged with the full frames, such that a persistently diverging frontend doesn't flood the log
+				Logger.Warn(fmt.Sprintf("Checksum mismatched: roomId=%v, playerId=%v, renderFrameId=%v, serverChecksum=%v, clientChecksum=%v, mismatchCount=%v, serverRdf=%v, clientRdf=%v", pR.Id, playerId, renderFrameId, serverChecksum, clientChecksum.Checksum, mismatchCount, rdf, clientChecksum.Rdf))
+			} else {
+				Logger.Debug(fmt.Sprintf("Checksum mismatched: roomId=%v, playerId=%v, renderFrameId=%v, serverChecksum=%v, clientChecksum=%v, mismatchCount=%v", pR.Id, playerId, renderFrameId, serverChecksum, clientChecksum.Checksum, mismatchCount))
+			}
+			if pR.ResyncUponChecksumMismatch {
+				player.ShouldResyncDueToChecksumMismatch = true
+			}
+		}
+	}
+	if upperRenderFrameId > pR.LastChecksumVerifiedRenderFrameId {
+		if (upperRenderFrameId / pR.RenderCacheSize) > (pR.LastChecksumVerifiedRenderFrameId / pR.RenderCacheSize) {
+			pR.evictObsoleteChecksums(upperRenderFrameId)
+		}
+		atomic.StoreInt32(&(pR.LastChecksumVerifiedRenderFrameId), upperRenderFrameId)
+	}
+}
+
+/*
+Sweeps the checksums upto "upperRenderFrameId" which are left in "pR.DiscreteChecksumsBuffer", e.g. one stored by "onChecksumReceived" right after "verifyChecksumsIfApplicable" passed its renderFrameId, or one by a player who has since left.
+*/
+func (pR *Room) evictObsoleteChecksums(upperRenderFrameId int32) {
+	pR.DiscreteChecksumsBuffer.Range(func(key, _ interface{}) bool {
+		if (key.(int32) >> 4) <= upperRenderFrameId {
+			pR.DiscreteChecksumsBuffer.Delete(key)
+		}
+		return true
+	})
+}
+
 func (pR *Room) onInputFrameDownsyncAllConfirmed(inputFrameDownsync *InputFrameDownsync, playerId int32) {
 	inputFrameId := inputFrameDownsync.InputFrameId
 	if -1 == pR.LastAllConfirmedInputFrameIdWithChange || false == pR.equalInputLists(inputFrameDownsync.InputList, pR.LastAllConfirmedInputList) {
@@ -665,6 +929,50 @@ func (pR *Room) equalInputLists(lhs []uint64, rhs []uint64) bool {
 	return true
 }
 
+/*
+The latest renderFrameId calculated only from all-confirmed inputFrames, thus never to be rolled back, or 0 if none.
+
+When "BackendRollbackEnabled", such a renderFrame is mostly a predicted one calculated ahead of time and then kept or recalculated by "rollbackIfApplicable" once its inputFrames are all-confirmed, such that resync, checksum verification and knock-out decision read it without waiting for the dynamics to catch up.
+*/
+func (pR *Room) lastSettledRenderFrameId() int32 {
+	if 0 > pR.LastAllConfirmedInputFrameId {
+		return 0
+	}
+	ret := pR.ConvertToLastUsedRenderFrameId(pR.LastAllConfirmedInputFrameId) + 1
+	if ret > pR.CurDynamicsRenderFrameId {
+		ret = pR.CurDynamicsRenderFrameId
+	}
+	return ret
+}
+
+/*
+Returns the latest settled render frame if it's decided by knock-outs, otherwise nil. A knock-out in a later predicted render frame might be reverted, thus never used.
+*/
+func (pR *Room) battleDecidedByKo() *RoomDownsyncFrame {
+	if 0 > pR.LastAllConfirmedInputFrameId || nil == pR.Stage {
+		return nil
+	}
+	tmp := pR.RenderFrameBuffer.GetByFrameId(pR.lastSettledRenderFrameId())
+	if nil == tmp {
+		return nil
+	}
+	rdf := tmp.(*RoomDownsyncFrame)
+	if !battle.IsDecidedByKo(pR.Stage, rdf) {
+		return nil
+	}
+	return rdf
+}
+
+// Such that the "DOWNSYNC_MSG_ACT_BATTLE_STOPPED" frame assembled by "toPbPlayers" tells who's knocked out.
+func (pR *Room) syncPlayersFromRenderFrame(rdf *RoomDownsyncFrame) {
+	for playerId, player := range pR.Players {
+		if rdfPlayer, existent := rdf.Players[playerId]; existent {
+			player.Hp = rdfPlayer.Hp
+			player.CharacterState = rdfPlayer.CharacterState
+		}
+	}
+}
+
 func (pR *Room) StopBattleForSettlement() {
 	if RoomBattleStateIns.IN_BATTLE != pR.State {
 		return
@@ -680,6 +988,15 @@ func (pR *Room) StopBattleForSettlement() {
 		}
 		pR.sendSafely(&assembledFrame, nil, DOWNSYNC_MSG_ACT_BATTLE_STOPPED, playerId)
 	}
+	pR.broadcastToSpectators(&WsResp{
+		Ret: int32(Constants.RetCode.Ok),
+		Act: DOWNSYNC_MSG_ACT_BATTLE_STOPPED,
+		Rdf: &RoomDownsyncFrame{
+			Id:             pR.RenderFrameId,
+			Players:        toPbPlayers(pR.Players, false),
+			CountdownNanos: -1,
+		},
+	})
 	// Note that `pR.onBattleStoppedForSettlement` will be called by `battleMainLoop`.
 }
 
@@ -688,11 +1005,12 @@ func (pR *Room) onBattleStarted() {
 		return
 	}
 	pR.State = RoomBattleStateIns.IN_BATTLE
+	pR.battleStartedAtMillis = utils.UnixtimeMilli()
 	pR.updateScore()
 }
 
 func (pR *Room) onBattlePrepare(cb BattleStartCbType) {
-	if RoomBattleStateIns.WAITING != pR.State {
+	if RoomBattleStateIns.WAITING != pR.State && RoomBattleStateIns.READY_CHECK != pR.State {
 		Logger.Warn("[onBattlePrepare] Battle not started after all players' battle state checked!", zap.Any("roomId", pR.Id), zap.Any("roomState", pR.State))
 		return
 	}
@@ -738,12 +1056,197 @@ func (pR *Room) onBattleStoppedForSettlement() {
 		pR.onSettlementCompleted()
 	}()
 	pR.State = RoomBattleStateIns.IN_SETTLEMENT
+	atomic.StoreInt64(&(pR.LoadStats.BattleStoppedAtNanos), utils.UnixtimeNano())
 	Logger.Info("The room is in settlement:", zap.Any("roomId", pR.Id))
-	// TODO: Some settlement labor.
+	if pR.ReplayRecordingEnabled {
+		pR.saveReplayAsync()
+	}
+	pR.persistSettlementAsync(pR.settledRenderFrame())
+}
+
+/*
+Returns nil if the outcome is unknown, i.e. without backend dynamics.
+
+[WARNING] When "BackendRollbackEnabled", the render frames after "lastSettledRenderFrameId" are calculated by predicted inputFrames and might be reverted by a late upsync, thus never used to decide the outcome, the same as "battleDecidedByKo".
+*/
+func (pR *Room) settledRenderFrame() *RoomDownsyncFrame {
+	if !pR.BackendDynamicsEnabled {
+		return nil
+	}
+	lastSettledRenderFrameId := pR.lastSettledRenderFrameId()
+	tmp := pR.RenderFrameBuffer.GetByFrameId(lastSettledRenderFrameId)
+	if nil == tmp {
+		Logger.Warn("settledRenderFrame, the last settled renderFrame doesn't exist:", zap.Any("roomId", pR.Id), zap.Any("lastSettledRenderFrameId", lastSettledRenderFrameId), zap.Any("curDynamicsRenderFrameId", pR.CurDynamicsRenderFrameId))
+		return nil
+	}
+	return tmp.(*RoomDownsyncFrame)
+}
+
+/*
+Persists the battle record, and the player ratings unless "settledRdf" is nil, in a detached goroutine such that a slow database never stalls the settlement in the goroutine of "battleMainLoop".
+
+[WARNING] The battle record is built before detaching, because "pR.Players" might be refreshed by a rematch right after the settlement. Each "RoomDownsyncFrame" is never mutated once calculated, thus "settledRdf" is safe to be read by the detached goroutine.
+*/
+func (pR *Room) persistSettlementAsync(settledRdf *RoomDownsyncFrame) {
+	roomId := pR.Id
+	pBattle, players := pR.buildBattleRecord(settledRdf)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				Logger.Error("Room persistSettlementAsync, recovery spot#1, recovered from: ", zap.Any("roomId", roomId), zap.Any("panic", r))
+			}
+		}()
+		if err := InsertBattleRecord(pBattle, players); nil != err {
+			Logger.Error("InsertBattleRecord failed:", zap.Any("roomId", roomId), zap.Error(err))
+		} else {
+			Logger.Info("Battle record saved:", zap.Any("roomId", roomId), zap.Any("battleId", pBattle.ID), zap.Any("winningTeamId", pBattle.WinningTeamId))
+		}
+		if nil == settledRdf {
+			return
+		}
+		if err := UpdatePlayerRatingsBySettledFrame(settledRdf); nil != err {
+			Logger.Error("UpdatePlayerRatingsBySettledFrame failed:", zap.Any("roomId", roomId), zap.Error(err))
+		}
+	}()
+}
+
+/*
+Without "settledRdf" the winner is recorded as unknown, and the final states of participants are taken from "pR.Players" instead.
+*/
+func (pR *Room) buildBattleRecord(settledRdf *RoomDownsyncFrame) (*BattleRecord, []*BattlePlayerRecord) {
+	endedAt := utils.UnixtimeMilli()
+	pBattle := &BattleRecord{
+		RoomId:         pR.Id,
+		StageName:      pR.StageName,
+		DurationMillis: endedAt - pR.battleStartedAtMillis,
+		StartedAt:      pR.battleStartedAtMillis,
+		EndedAt:        endedAt,
+	}
+	finalPlayers := toPbPlayers(pR.Players, false)
+	if nil != settledRdf {
+		pBattle.WinningTeamId = battle.WinningTeamIdOf(settledRdf)
+		finalPlayers = settledRdf.Players
+	}
+	players := make([]*BattlePlayerRecord, 0, len(finalPlayers))
+	for playerId, player := range finalPlayers {
+		players = append(players, &BattlePlayerRecord{
+			PlayerId:   playerId,
+			JoinIndex:  player.JoinIndex,
+			TeamId:     battle.EffectiveTeamIdOf(player),
+			FinalHp:    player.Hp,
+			Score:      player.Score,
+			HitsLanded: player.HitsLanded,
+		})
+	}
+	return pBattle, players
+}
+
+func (pR *Room) startReplayRecording(bciFrame *BattleColliderInfo, kickoffFrame *RoomDownsyncFrame) {
+	pR.replayHeader = &BattleReplayHeader{
+		Version:               battle.REPLAY_VERSION,
+		RoomId:                pR.Id,
+		Capacity:              int32(pR.Capacity),
+		PlayerDefaultSpeed:    pR.PlayerDefaultSpeed,
+		BattleStartedAtMillis: utils.UnixtimeMilli(),
+		BciFrame:              bciFrame,
+		KickoffFrame:          kickoffFrame,
+	}
+	pR.replayInputFrames = make([]*InputFrameDownsync, 0, (pR.BattleDurationFrames>>pR.InputScaleFrames)+1)
+}
+
+func (pR *Room) recordAllConfirmedInputFrames() {
+	// [WARNING] Must be called before the eviction of "pR.InputsBuffer" in each iteration of "battleMainLoop", such that no all-confirmed inputFrame is evicted before being recorded.
+	if nil == pR.replayHeader {
+		// Dropped for exceeding "MaxReplayInputFrameCount"
+		return
+	}
+	if maxCount := pR.maxReplayInputFrameCount; 0 < maxCount && maxCount <= pR.LastAllConfirmedInputFrameId {
+		Logger.Warn("Replay dropped for exceeding maxReplayInputFrameCount:", zap.Any("roomId", pR.Id), zap.Any("maxReplayInputFrameCount", maxCount))
+		pR.replayHeader = nil
+		pR.replayInputFrames = nil
+		return
+	}
+	for inputFrameId := int32(len(pR.replayInputFrames)); inputFrameId <= pR.LastAllConfirmedInputFrameId; inputFrameId++ {
+		tmp := pR.InputsBuffer.GetByFrameId(inputFrameId)
+		if nil == tmp {
+			panic(fmt.Sprintf("inputFrameId=%v doesn't exist for roomId=%v, this is abnormal because it's to be recorded for replay! InputsBuffer=%v", inputFrameId, pR.Id, pR.InputsBufferString(false)))
+		}
+		pR.replayInputFrames = append(pR.replayInputFrames, tmp.(*InputFrameDownsync))
+	}
+}
+
+/*
+Writes the replay in a detached goroutine, the same as "persistSettlementAsync", such that neither the file writing nor "removeObsoleteReplays" stalls the settlement in the goroutine of "battleMainLoop".
+
+[WARNING] The recorded header and inputFrames are handed over before detaching, because they're reset by "refreshBattleStates" upon a rematch. A recorded inputFrame is all-confirmed and thus never mutated afterwards.
+*/
+func (pR *Room) saveReplayAsync() {
+	roomId := pR.Id
+	header, inputFrames := pR.replayHeader, pR.replayInputFrames
+	pR.replayHeader = nil
+	pR.replayInputFrames = nil
+	if nil == header {
+		return
+	}
+	// The switches renegotiated during the battle weren't known when "replayHeader" was built
+	header.BciFrame.InputDelayFramesSwitches = pR.InputDelayFramesSwitches
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				Logger.Error("Room saveReplayAsync, recovery spot#1, recovered from: ", zap.Any("roomId", roomId), zap.Any("panic", r))
+			}
+		}()
+		replayDir := filepath.Join(Conf.General.AppRoot, "replays")
+		if err := os.MkdirAll(replayDir, 0755); nil != err {
+			panic(err)
+		}
+		replayFilePath := filepath.Join(replayDir, fmt.Sprintf("room_%d_%d.replay", roomId, header.BattleStartedAtMillis))
+		file, err := os.Create(replayFilePath)
+		if nil != err {
+			panic(err)
+		}
+		defer file.Close()
+		if err := battle.WriteReplay(file, header, inputFrames); nil != err {
+			panic(err)
+		}
+		removeObsoleteReplays(replayDir)
+		Logger.Info("Replay saved:", zap.Any("roomId", roomId), zap.Any("replayFilePath", replayFilePath), zap.Any("inputFrameCount", len(inputFrames)))
+	}()
+}
+
+/*
+Removes the oldest replay files in "replayDir" beyond "MaxReplayFileCount", by the order of modification time.
+
+[WARNING] Rooms settled at the same time might run this concurrently, thus a file already removed by another room is not an error.
+*/
+func removeObsoleteReplays(replayDir string) {
+	maxCount := Conf.Room.MaxReplayFileCount
+	if 0 >= maxCount {
+		return
+	}
+	replayFilePaths, err := filepath.Glob(filepath.Join(replayDir, "room_*.replay"))
+	if nil != err || len(replayFilePaths) <= maxCount {
+		return
+	}
+	modTimes := make(map[string]int64, len(replayFilePaths))
+	for _, replayFilePath := range replayFilePaths {
+		if fileInfo, err := os.Stat(replayFilePath); nil == err {
+			modTimes[replayFilePath] = fileInfo.ModTime().UnixNano()
+		}
+	}
+	sort.Slice(replayFilePaths, func(i, j int) bool {
+		return modTimes[replayFilePaths[i]] < modTimes[replayFilePaths[j]]
+	})
+	for _, replayFilePath := range replayFilePaths[:len(replayFilePaths)-maxCount] {
+		if err := os.Remove(replayFilePath); nil != err && !os.IsNotExist(err) {
+			Logger.Warn("Failed to remove an obsolete replay:", zap.Any("replayFilePath", replayFilePath), zap.Error(err))
+		}
+	}
 }
 
 func (pR *Room) onSettlementCompleted() {
-	pR.Dismiss()
+	pR.openRematchWindowOrDismiss()
 }
 
 func (pR *Room) Dismiss() {
@@ -751,6 +1254,7 @@ func (pR *Room) Dismiss() {
 		return
 	}
 	pR.State = RoomBattleStateIns.IN_DISMISSAL
+	pR.expelAllSpectators()
 	if 0 < len(pR.Players) {
 		Logger.Info("The room is in dismissal:", zap.Any("roomId", pR.Id))
 		for playerId, _ := range pR.Players {
@@ -763,26 +1267,47 @@ func (pR *Room) Dismiss() {
 		pR.DismissalWaitGroup.Wait()
 	}
 	pR.OnDismissed()
+	recycleDismissedRoom(pR)
 }
 
 func (pR *Room) OnDismissed() {
 
 	// Always instantiates new HeapRAM blocks and let the old blocks die out due to not being retained by any root reference.
-	pR.BulletBattleLocalIdCounter = 0
-	pR.WorldToVirtualGridRatio = float64(1000)
-	pR.VirtualGridToWorldRatio = float64(1.0) / pR.WorldToVirtualGridRatio // this is a one-off computation, should avoid division in iterations
-	pR.SpAtkLookupFrames = 5
-	pR.PlayerDefaultSpeed = int32(float64(2) * pR.WorldToVirtualGridRatio) // in virtual grids per frame
 	pR.Players = make(map[int32]*Player)
 	pR.PlayersArr = make([]*Player, pR.Capacity)
-	pR.CollisionSysMap = make(map[int32]*resolv.Object)
 	pR.PlayerDownsyncSessionDict = make(map[int32]*websocket.Conn)
+	pR.PlayerDownsyncSessionMuxDict = make(map[int32]*sync.Mutex)
 	pR.PlayerSignalToCloseDict = make(map[int32]SignalToCloseConnCbType)
+	pR.spectatorsMux.Lock()
+	pR.spectators = make(map[int32]*Spectator)
+	pR.spectatorsMux.Unlock()
 	pR.JoinIndexBooleanArr = make([]bool, pR.Capacity)
-	pR.Barriers = make(map[int32]*Barrier)
+
+	pR.refreshBattleStates()
+	pR.EffectivePlayerCount = 0
+
+	// [WARNING] It's deliberately ordered such that "pR.State = RoomBattleStateIns.IDLE" is put AFTER all the refreshing operations above.
+	pR.State = RoomBattleStateIns.IDLE
+	pR.updateScore()
+
+	Logger.Info("The room is completely dismissed:", zap.Any("roomId", pR.Id))
+}
+
+/*
+Refreshes everything of the previous battle except for the participants and their network sessions, shared by "OnDismissed" and "rematch".
+*/
+func (pR *Room) refreshBattleStates() {
+	pR.WorldToVirtualGridRatio = float64(WORLD_TO_VIRTUAL_GRID_RATIO)
+	pR.VirtualGridToWorldRatio = float64(1.0) / pR.WorldToVirtualGridRatio // this is a one-off computation, should avoid division in iterations
+	pR.SpAtkLookupFrames = 5
+	pR.PlayerDefaultSpeed = int32(float64(2) * pR.WorldToVirtualGridRatio) // in virtual grids per frame
+	pR.Stage = nil
+	pR.simulator = nil
 	pR.RenderCacheSize = 1024
 	pR.RenderFrameBuffer = NewRingBuffer(pR.RenderCacheSize)
 	pR.DiscreteInputsBuffer = sync.Map{}
+	pR.DiscreteChecksumsBuffer = sync.Map{}
+	pR.LastChecksumVerifiedRenderFrameId = 0 // The kickoff frame is never upsynced
 	pR.InputsBuffer = NewRingBuffer((pR.RenderCacheSize >> 2) + 1)
 
 	pR.LastAllConfirmedInputFrameId = -1
@@ -799,47 +1324,30 @@ func (pR *Room) OnDismissed() {
 	pR.RollbackEstimatedDtNanos = 16666666 // A little smaller than the actual per frame time, just for preventing FAST FRAME
 	dilutionFactor := 12
 	pR.dilutedRollbackEstimatedDtNanos = int64(16666666 * (dilutionFactor) / (dilutionFactor - 1)) // [WARNING] Only used in controlling "battleMainLoop" to be keep a frame rate lower than that of the frontends, such that upon resync(i.e. BackendDynamicsEnabled=true), the frontends would have bigger chances to keep up with or even surpass the backend calculation
-	pR.BattleDurationFrames = 30 * pR.ServerFps
+	pR.BattleDurationFrames = pR.Params.BattleDurationSeconds * pR.ServerFps
 	pR.BattleDurationNanos = int64(pR.BattleDurationFrames) * (pR.RollbackEstimatedDtNanos + 1)
 	pR.InputFrameUpsyncDelayTolerance = 2
 	pR.MaxChasingRenderFramesPerUpdate = 5
 
 	pR.BackendDynamicsEnabled = true // [WARNING] When "false", recovery upon reconnection wouldn't work!
-	punchSkillId := int32(1)
-	pR.MeleeSkillConfig = make(map[int32]*MeleeBullet, 0)
-	pR.MeleeSkillConfig[punchSkillId] = &MeleeBullet{
-		// for offender
-		StartupFrames:         int32(23),
-		ActiveFrames:          int32(3),
-		RecoveryFrames:        int32(61), // I hereby set it to be 1 frame more than the actual animation to avoid critical transition, i.e. when the animation is 1 frame from ending but "rdfPlayer.framesToRecover" is already counted 0 and the player triggers an other same attack, making an effective bullet trigger but no animation is played due to same animName is still playing
-		RecoveryFramesOnBlock: int32(61),
-		RecoveryFramesOnHit:   int32(61),
-		Moveforward: &Vec2D{
-			X: 0,
-			Y: 0,
-		},
-		HitboxOffset: float64(24.0), // should be about the radius of the PlayerCollider
-		HitboxSize: &Vec2D{
-			X: float64(45.0),
-			Y: float64(32.0),
-		},
-
-		// for defender
-		HitStunFrames:      int32(18),
-		BlockStunFrames:    int32(9),
-		Pushback:           float64(11.0),
-		ReleaseTriggerType: int32(1), // 1: rising-edge, 2: falling-edge
-		Damage:             int32(5),
-	}
+	pR.BackendRollbackEnabled = pR.Params.BackendRollbackEnabled
+	pR.ResyncUponChecksumMismatch = pR.Params.ResyncUponChecksumMismatch
+	pR.FixedPointCollisionEnabled = pR.Params.FixedPointCollisionEnabled
+	pR.PlayerCollisionEnabled = pR.Params.PlayerCollisionEnabled
+	pR.ReplayRecordingEnabled = pR.Params.ReplayRecordingEnabled
+	pR.replayHeader = nil
+	pR.replayInputFrames = nil
+	pR.maxReplayInputFrameCount = int32(Conf.Room.MaxReplayInputFrameCount)
+	pR.pendingRollbackRenderFrameId = math.MaxInt32
+	pR.MeleeSkillConfig = SkillConfigsIns.Skills
+	pR.FireballSkillConfig = SkillConfigsIns.FireballSkills
+	pR.KoRespawnFrames = pR.Params.KoRespawnFrames
+	pR.CharacterSkillBindings = SkillConfigsIns.Characters
+	pR.TeamCount = pR.Params.TeamCount
+	pR.FriendlyFireEnabled = pR.Params.FriendlyFireEnabled
+	pR.AdaptiveInputDelayEnabled = pR.Params.AdaptiveInputDelayEnabled
 
 	pR.ChooseStage()
-	pR.EffectivePlayerCount = 0
-
-	// [WARNING] It's deliberately ordered such that "pR.State = RoomBattleStateIns.IDLE" is put AFTER all the refreshing operations above.
-	pR.State = RoomBattleStateIns.IDLE
-	pR.updateScore()
-
-	Logger.Info("The room is completely dismissed:", zap.Any("roomId", pR.Id))
 }
 
 func (pR *Room) expelPlayerDuringGame(playerId int32) {
@@ -882,6 +1390,7 @@ func (pR *Room) OnPlayerDisconnected(playerId int32) {
 		return
 	}
 
+	readyCheckAborted := pR.abortReadyCheck() // The room is no longer full
 	switch pR.State {
 	case RoomBattleStateIns.WAITING:
 		pR.onPlayerLost(playerId)
@@ -890,6 +1399,9 @@ func (pR *Room) OnPlayerDisconnected(playerId int32) {
 			pR.State = RoomBattleStateIns.IDLE
 		}
 		pR.updateScore()
+		if readyCheckAborted {
+			pR.broadcastReadyStates()
+		}
 		Logger.Info("Player disconnected while room is at RoomBattleStateIns.WAITING:", zap.Any("playerId", playerId), zap.Any("roomId", pR.Id), zap.Any("nowRoomBattleState", pR.State), zap.Any("nowRoomEffectivePlayerCount", pR.EffectivePlayerCount))
 	default:
 		pR.Players[playerId].BattleState = PlayerBattleStateIns.DISCONNECTED
@@ -923,6 +1435,7 @@ func (pR *Room) clearPlayerNetworkSession(playerId int32) {
 	if _, y := pR.PlayerDownsyncSessionDict[playerId]; y {
 		Logger.Info("sending termination symbol for:", zap.Any("playerId", playerId), zap.Any("roomId", pR.Id))
 		delete(pR.PlayerDownsyncSessionDict, playerId)
+		delete(pR.PlayerDownsyncSessionMuxDict, playerId)
 		delete(pR.PlayerSignalToCloseDict, playerId)
 	}
 }
@@ -937,27 +1450,11 @@ func (pR *Room) onPlayerAdded(playerId int32) {
 	for index, value := range pR.JoinIndexBooleanArr {
 		if false == value {
 			pR.Players[playerId].JoinIndex = int32(index) + 1
+			pR.Players[playerId].TeamId = battle.TeamIdOf(pR.Players[playerId].JoinIndex, pR.TeamCount)
 			pR.JoinIndexBooleanArr[index] = true
 
 			// Lazily assign the initial position of "Player" for "RoomDownsyncFrame".
-			playerPosList := *(pR.StrToVec2DListMap["PlayerStartingPos"])
-			if index > len(playerPosList.Eles) {
-				panic(fmt.Sprintf("onPlayerAdded error, index >= len(playerPosList), roomId=%v, playerId=%v, roomState=%v, roomEffectivePlayerCount=%v", pR.Id, playerId, pR.State, pR.EffectivePlayerCount))
-			}
-			playerPos := playerPosList.Eles[index]
-
-			if nil == playerPos {
-				panic(fmt.Sprintf("onPlayerAdded error, nil == playerPos, roomId=%v, playerId=%v, roomState=%v, roomEffectivePlayerCount=%v", pR.Id, playerId, pR.State, pR.EffectivePlayerCount))
-			}
-			pR.Players[playerId].VirtualGridX, pR.Players[playerId].VirtualGridY = WorldToVirtualGridPos(playerPos.X, playerPos.Y, pR.WorldToVirtualGridRatio)
-			// Hardcoded initial character orientation/facing
-			if 0 == (pR.Players[playerId].JoinIndex % 2) {
-				pR.Players[playerId].DirX = -2
-				pR.Players[playerId].DirY = 0
-			} else {
-				pR.Players[playerId].DirX = +2
-				pR.Players[playerId].DirY = 0
-			}
+			pR.placePlayerAtStartingPosition(pR.Players[playerId])
 
 			break
 		}
@@ -967,6 +1464,22 @@ func (pR *Room) onPlayerAdded(playerId int32) {
 	Logger.Info("onPlayerAdded:", zap.Any("playerId", playerId), zap.Any("roomId", pR.Id), zap.Any("joinIndex", pR.Players[playerId].JoinIndex), zap.Any("EffectivePlayerCount", pR.EffectivePlayerCount), zap.Any("resulted pR.JoinIndexBooleanArr", pR.JoinIndexBooleanArr), zap.Any("RoomBattleState", pR.State))
 }
 
+func (pR *Room) placePlayerAtStartingPosition(player *Player) {
+	playerPos := battle.PlayerStartingPosition(pR.StrToVec2DListMap, pR.TeamCount, player.JoinIndex)
+	if nil == playerPos {
+		panic(fmt.Sprintf("placePlayerAtStartingPosition error, nil == playerPos, roomId=%v, playerId=%v, roomState=%v, roomEffectivePlayerCount=%v", pR.Id, player.Id, pR.State, pR.EffectivePlayerCount))
+	}
+	player.VirtualGridX, player.VirtualGridY = WorldToVirtualGridPos(playerPos.X, playerPos.Y, pR.WorldToVirtualGridRatio)
+	// Hardcoded initial character orientation/facing
+	if 0 == (player.JoinIndex % 2) {
+		player.DirX = -2
+		player.DirY = 0
+	} else {
+		player.DirX = +2
+		player.DirY = 0
+	}
+}
+
 func (pR *Room) onPlayerReAdded(playerId int32) {
 	/*
 	 * [WARNING]
@@ -1025,7 +1538,7 @@ func (pR *Room) OnPlayerBattleColliderAcked(playerId int32) bool {
 			}
 		}
 		if true == allAcked {
-			pR.StartBattle() // WON'T run if the battle state is not in WAITING.
+			pR.startReadyCheckOrBattle() // WON'T run if the battle state is not in WAITING.
 		}
 	}
 
@@ -1046,15 +1559,31 @@ func (pR *Room) sendSafely(roomDownsyncFrame *RoomDownsyncFrame, toSendFrames []
 		Rdf:                     roomDownsyncFrame,
 		InputFrameDownsyncBatch: toSendFrames,
 	}
+	if DOWNSYNC_MSG_ACT_BATTLE_START == act && pR.AdaptiveInputDelayEnabled {
+		// The "InputDelayFrames" chosen at battle start differs from the one in "DOWNSYNC_MSG_ACT_HB_REQ"
+		pResp.BciFrame = pR.BuildBattleColliderInfo(PlayerBattleStateIns.ACTIVE)
+	}
+
+	pR.sendRespSafely(pResp, playerId)
+}
 
+// [WARNING] Panics upon any error, the caller is responsible for recovery.
+func (pR *Room) sendRespSafely(pResp *WsResp, playerId int32) {
 	theBytes, marshalErr := proto.Marshal(pResp)
 	if nil != marshalErr {
 		panic(fmt.Sprintf("Error marshaling downsync message: roomId=%v, playerId=%v, roomState=%v, roomEffectivePlayerCount=%v", pR.Id, playerId, pR.State, pR.EffectivePlayerCount))
 	}
 
+	mux := pR.PlayerDownsyncSessionMuxDict[playerId]
+	mux.Lock()
+	defer mux.Unlock()
 	if err := pR.PlayerDownsyncSessionDict[playerId].WriteMessage(websocket.BinaryMessage, theBytes); nil != err {
 		panic(fmt.Sprintf("Error sending downsync message: roomId=%v, playerId=%v, roomState=%v, roomEffectivePlayerCount=%v, err=%v", pR.Id, playerId, pR.State, pR.EffectivePlayerCount, err))
 	}
+	pR.LoadStats.onDownsyncSent(pResp.Act, len(theBytes))
+	if player, existent := pR.Players[playerId]; existent {
+		player.NetStats.onDownsyncSent(pResp.Act, len(theBytes))
+	}
 }
 
 func (pR *Room) shouldPrefabInputFrameDownsync(renderFrameId int32) bool {
@@ -1083,7 +1612,7 @@ func (pR *Room) prefabInputFrameDownsync(inputFrameId int32) *InputFrameDownsync
 		prevInputFrameDownsync := tmp.(*InputFrameDownsync)
 		currInputList := make([]uint64, pR.Capacity) // Would be a clone of the values
 		for i, _ := range currInputList {
-			currInputList[i] = (prevInputFrameDownsync.InputList[i] & uint64(15)) // Don't predict attack input!
+			currInputList[i] = (prevInputFrameDownsync.InputList[i] & battle.INPUT_PREDICTED_BITS_MASK)
 		}
 		currInputFrameDownsync = &InputFrameDownsync{
 			InputFrameId:  inputFrameId,
@@ -1100,6 +1629,7 @@ func (pR *Room) markConfirmationIfApplicable() {
 	inputFrameId1 := pR.LastAllConfirmedInputFrameId + 1
 	totPlayerCnt := uint32(pR.Capacity)
 	allConfirmedMask := uint64((1 << totPlayerCnt) - 1)
+	allConfirmedSoFar := true
 	for inputFrameId := inputFrameId1; inputFrameId < pR.InputsBuffer.EdFrameId; inputFrameId++ {
 		tmp := pR.InputsBuffer.GetByFrameId(inputFrameId)
 		if nil == tmp {
@@ -1114,18 +1644,61 @@ func (pR *Room) markConfirmationIfApplicable() {
 			}
 			inputFrameUpsync := tmp.(*InputFrameUpsync)
 			indiceInJoinIndexBooleanArr := uint32(player.JoinIndex - 1)
+			if pR.BackendRollbackEnabled && inputFrameDownsync.InputList[indiceInJoinIndexBooleanArr] != inputFrameUpsync.Encoded {
+				pR.onInputFrameDownsyncMispredicted(inputFrameId, player.JoinIndex, inputFrameUpsync.Encoded)
+			}
 			inputFrameDownsync.InputList[indiceInJoinIndexBooleanArr] = inputFrameUpsync.Encoded
 			inputFrameDownsync.ConfirmedList |= (1 << indiceInJoinIndexBooleanArr)
 		}
 
-		if allConfirmedMask == inputFrameDownsync.ConfirmedList {
+		if allConfirmedSoFar && allConfirmedMask == inputFrameDownsync.ConfirmedList {
 			pR.onInputFrameDownsyncAllConfirmed(inputFrameDownsync, -1)
+		} else if pR.BackendRollbackEnabled {
+			// Keep traversing to collect "InputFrameUpsync"s of later inputFrames as early as possible, they might already be used for prediction
+			allConfirmedSoFar = false
 		} else {
 			break
 		}
 	}
 }
 
+func (pR *Room) onInputFrameDownsyncMispredicted(inputFrameId int32, joinIndex int32, encoded uint64) {
+	firstUsedRenderFrameId := pR.ConvertToFirstUsedRenderFrameId(inputFrameId)
+	if firstUsedRenderFrameId < pR.CurDynamicsRenderFrameId && firstUsedRenderFrameId < pR.pendingRollbackRenderFrameId {
+		pR.pendingRollbackRenderFrameId = firstUsedRenderFrameId
+	}
+
+	// Later predicted inputs of the same player should follow the latest upsync as well, the same way as "prefabInputFrameDownsync"
+	indiceInJoinIndexBooleanArr := uint32(joinIndex - 1)
+	for laterInputFrameId := inputFrameId + 1; laterInputFrameId < pR.InputsBuffer.EdFrameId; laterInputFrameId++ {
+		tmp := pR.InputsBuffer.GetByFrameId(laterInputFrameId)
+		if nil == tmp {
+			break
+		}
+		laterInputFrameDownsync := tmp.(*InputFrameDownsync)
+		if 0 < (laterInputFrameDownsync.ConfirmedList & uint64(1<<indiceInJoinIndexBooleanArr)) {
+			continue
+		}
+		laterInputFrameDownsync.InputList[indiceInJoinIndexBooleanArr] = (encoded & battle.INPUT_PREDICTED_BITS_MASK)
+	}
+}
+
+func (pR *Room) rollbackIfApplicable() {
+	if math.MaxInt32 == pR.pendingRollbackRenderFrameId {
+		return
+	}
+	rollbackRenderFrameId := pR.pendingRollbackRenderFrameId
+	pR.pendingRollbackRenderFrameId = math.MaxInt32
+	if rollbackRenderFrameId >= pR.CurDynamicsRenderFrameId {
+		return
+	}
+	if nil == pR.RenderFrameBuffer.GetByFrameId(rollbackRenderFrameId) {
+		panic(fmt.Sprintf("rollbackRenderFrameId=%v doesn't exist for roomId=%v, this is abnormal because only non-all-confirmed inputFrames can be mispredicted! RenderFrameBuffer=%v", rollbackRenderFrameId, pR.Id, pR.RenderFrameBufferString()))
+	}
+	Logger.Debug(fmt.Sprintf("Rolling back: roomId=%v, room.RenderFrameId=%v, curDynamicsRenderFrameId=%v, rollbackRenderFrameId=%v", pR.Id, pR.RenderFrameId, pR.CurDynamicsRenderFrameId, rollbackRenderFrameId))
+	pR.CurDynamicsRenderFrameId = rollbackRenderFrameId
+}
+
 func (pR *Room) forceConfirmationIfApplicable() uint64 {
 	// Force confirmation of non-all-confirmed inputFrame EXACTLY ONE AT A TIME, returns the non-confirmed mask of players, e.g. in a 4-player-battle returning 1001 means that players with JoinIndex=1 and JoinIndex=4 are non-confirmed for inputFrameId2
 	renderFrameId1 := (pR.RenderFrameId - pR.NstDelayFrames) // the renderFrameId which should've been rendered on frontend
@@ -1162,7 +1735,7 @@ func (pR *Room) forceConfirmationIfApplicable() uint64 {
 	return unconfirmedMask
 }
 
-func (pR *Room) applyInputFrameDownsyncDynamics(fromRenderFrameId int32, toRenderFrameId int32, spaceOffsetX, spaceOffsetY float64) {
+func (pR *Room) applyInputFrameDownsyncDynamics(fromRenderFrameId int32, toRenderFrameId int32) {
 	if fromRenderFrameId >= toRenderFrameId {
 		return
 	}
@@ -1177,10 +1750,10 @@ func (pR *Room) applyInputFrameDownsyncDynamics(fromRenderFrameId int32, toRende
 			panic(fmt.Sprintf("collisionSysRenderFrameId=%v doesn't exist for roomId=%v, this is abnormal because it's to be used for applying dynamics to [fromRenderFrameId:%v, toRenderFrameId:%v)! RenderFrameBuffer=%v", collisionSysRenderFrameId, pR.Id, fromRenderFrameId, toRenderFrameId, pR.RenderFrameBufferString()))
 		}
 		currRenderFrame := currRenderFrameTmp.(*RoomDownsyncFrame)
-		delayedInputFrameId := pR.ConvertToInputFrameId(collisionSysRenderFrameId, pR.InputDelayFrames)
+		delayedInputFrameId := pR.ConvertToDelayedInputFrameId(collisionSysRenderFrameId)
 		var delayedInputFrame *InputFrameDownsync = nil
 		if 0 <= delayedInputFrameId {
-			if delayedInputFrameId > pR.LastAllConfirmedInputFrameId {
+			if !pR.BackendRollbackEnabled && delayedInputFrameId > pR.LastAllConfirmedInputFrameId {
 				panic(fmt.Sprintf("delayedInputFrameId=%v is not yet all-confirmed for roomId=%v, this is abnormal because it's to be used for applying dynamics to [fromRenderFrameId:%v, toRenderFrameId:%v) @ collisionSysRenderFrameId=%v! InputsBuffer=%v", delayedInputFrameId, pR.Id, fromRenderFrameId, toRenderFrameId, collisionSysRenderFrameId, pR.InputsBufferString(false)))
 			}
 			tmp := pR.InputsBuffer.GetByFrameId(delayedInputFrameId)
@@ -1188,252 +1761,25 @@ func (pR *Room) applyInputFrameDownsyncDynamics(fromRenderFrameId int32, toRende
 				panic(fmt.Sprintf("delayedInputFrameId=%v doesn't exist for roomId=%v, this is abnormal because it's to be used for applying dynamics to [fromRenderFrameId:%v, toRenderFrameId:%v) @ collisionSysRenderFrameId=%v! InputsBuffer=%v", delayedInputFrameId, pR.Id, fromRenderFrameId, toRenderFrameId, collisionSysRenderFrameId, pR.InputsBufferString(false)))
 			}
 			delayedInputFrame = tmp.(*InputFrameDownsync)
-			// [WARNING] It's possible that by now "allConfirmedMask != delayedInputFrame.ConfirmedList && delayedInputFrameId <= pR.LastAllConfirmedInputFrameId", we trust "pR.LastAllConfirmedInputFrameId" as the TOP AUTHORITY.
-			atomic.StoreUint64(&(delayedInputFrame.ConfirmedList), allConfirmedMask)
-		}
-
-		nextRenderFrame := pR.applyInputFrameDownsyncDynamicsOnSingleRenderFrame(delayedInputFrame, currRenderFrame, pR.CollisionSysMap)
-		pR.RenderFrameBuffer.Put(nextRenderFrame)
-		pR.CurDynamicsRenderFrameId++
-	}
-}
-
-// TODO: Write unit-test for this function to compare with its frontend counter part
-func (pR *Room) applyInputFrameDownsyncDynamicsOnSingleRenderFrame(delayedInputFrame *InputFrameDownsync, currRenderFrame *RoomDownsyncFrame, collisionSysMap map[int32]*resolv.Object) *RoomDownsyncFrame {
-	// TODO: Derive "nextRenderFramePlayers[*].CharacterState" as the frontend counter-part!
-	nextRenderFramePlayers := make(map[int32]*PlayerDownsync, pR.Capacity)
-	// Make a copy first
-	for playerId, currPlayerDownsync := range currRenderFrame.Players {
-		nextRenderFramePlayers[playerId] = &PlayerDownsync{
-			Id:              playerId,
-			VirtualGridX:    currPlayerDownsync.VirtualGridX,
-			VirtualGridY:    currPlayerDownsync.VirtualGridY,
-			DirX:            currPlayerDownsync.DirX,
-			DirY:            currPlayerDownsync.DirY,
-			CharacterState:  currPlayerDownsync.CharacterState,
-			Speed:           currPlayerDownsync.Speed,
-			BattleState:     currPlayerDownsync.BattleState,
-			Score:           currPlayerDownsync.Score,
-			Removed:         currPlayerDownsync.Removed,
-			JoinIndex:       currPlayerDownsync.JoinIndex,
-			FramesToRecover: currPlayerDownsync.FramesToRecover - 1,
-			Hp:              currPlayerDownsync.Hp,
-			MaxHp:           currPlayerDownsync.MaxHp,
-		}
-		if nextRenderFramePlayers[playerId].FramesToRecover < 0 {
-			nextRenderFramePlayers[playerId].FramesToRecover = 0
-		}
-	}
-
-	toRet := &RoomDownsyncFrame{
-		Id:             currRenderFrame.Id + 1,
-		Players:        nextRenderFramePlayers,
-		CountdownNanos: (pR.BattleDurationNanos - int64(currRenderFrame.Id)*pR.RollbackEstimatedDtNanos),
-		MeleeBullets:   make([]*MeleeBullet, 0), // Is there any better way to reduce malloc/free impact, e.g. smart prediction for fixed memory allocation?
-	}
-
-	bulletPushbacks := make([]Vec2D, pR.Capacity) // Guaranteed determinism regardless of traversal order
-	effPushbacks := make([]Vec2D, pR.Capacity)    // Guaranteed determinism regardless of traversal order
-
-	// Reset playerCollider position from the "virtual grid position"
-	for playerId, player := range pR.Players {
-		joinIndex := player.JoinIndex
-		bulletPushbacks[joinIndex-1].X, bulletPushbacks[joinIndex-1].Y = float64(0), float64(0)
-		effPushbacks[joinIndex-1].X, effPushbacks[joinIndex-1].Y = float64(0), float64(0)
-		currPlayerDownsync := currRenderFrame.Players[playerId]
-		newVx, newVy := currPlayerDownsync.VirtualGridX, currPlayerDownsync.VirtualGridY
-		collisionPlayerIndex := COLLISION_PLAYER_INDEX_PREFIX + joinIndex
-		playerCollider := collisionSysMap[collisionPlayerIndex]
-		playerCollider.X, playerCollider.Y = VirtualGridToPolygonColliderAnchorPos(newVx, newVy, player.ColliderRadius, player.ColliderRadius, pR.collisionSpaceOffsetX, pR.collisionSpaceOffsetY, pR.VirtualGridToWorldRatio)
-	}
-
-	// Check bullet-anything collisions first, because the pushbacks caused by bullets might later be reverted by player-barrier collision
-	bulletColliders := make(map[int32]*resolv.Object, 0) // Will all be removed at the end of `applyInputFrameDownsyncDynamicsOnSingleRenderFrame` due to the need for being rollback-compatible
-	removedBulletsAtCurrFrame := make(map[int32]int32, 0)
-	for _, meleeBullet := range currRenderFrame.MeleeBullets {
-		if (meleeBullet.OriginatedRenderFrameId+meleeBullet.StartupFrames <= currRenderFrame.Id) && (meleeBullet.OriginatedRenderFrameId+meleeBullet.StartupFrames+meleeBullet.ActiveFrames > currRenderFrame.Id) {
-			collisionBulletIndex := COLLISION_BULLET_INDEX_PREFIX + meleeBullet.BattleLocalId
-			collisionOffenderIndex := COLLISION_PLAYER_INDEX_PREFIX + meleeBullet.OffenderJoinIndex
-			offenderCollider := collisionSysMap[collisionOffenderIndex]
-			offender := currRenderFrame.Players[meleeBullet.OffenderPlayerId]
-
-			xfac := float64(1.0) // By now, straight Punch offset doesn't respect "y-axis"
-			if 0 > offender.DirX {
-				xfac = float64(-1.0)
-			}
-			offenderWx, offenderWy := VirtualGridToWorldPos(offender.VirtualGridX, offender.VirtualGridY, pR.VirtualGridToWorldRatio)
-			bulletWx, bulletWy := offenderWx+xfac*meleeBullet.HitboxOffset, offenderWy
-
-			newBulletCollider := GenerateRectCollider(bulletWx, bulletWy, meleeBullet.HitboxSize.X, meleeBullet.HitboxSize.Y, pR.collisionSpaceOffsetX, pR.collisionSpaceOffsetY, "MeleeBullet")
-			newBulletCollider.Data = meleeBullet
-			pR.Space.Add(newBulletCollider)
-			collisionSysMap[collisionBulletIndex] = newBulletCollider
-			bulletColliders[collisionBulletIndex] = newBulletCollider
-
-			Logger.Debug(fmt.Sprintf("roomId=%v, a meleeBullet is added to collisionSys at currRenderFrame.id=%v as start-up frames ended and active frame is not yet ended: %v, from offenderCollider=%v, xfac=%v", pR.Id, currRenderFrame.Id, ConvexPolygonStr(newBulletCollider.Shape.(*resolv.ConvexPolygon)), ConvexPolygonStr(offenderCollider.Shape.(*resolv.ConvexPolygon)), xfac))
-		}
-	}
-
-	for _, bulletCollider := range bulletColliders {
-		shouldRemove := false
-		meleeBullet := bulletCollider.Data.(*MeleeBullet)
-		collisionBulletIndex := COLLISION_BULLET_INDEX_PREFIX + meleeBullet.BattleLocalId
-		bulletShape := bulletCollider.Shape.(*resolv.ConvexPolygon)
-		if collision := bulletCollider.Check(0, 0); collision != nil {
-			offender := currRenderFrame.Players[meleeBullet.OffenderPlayerId]
-			for _, obj := range collision.Objects {
-				defenderShape := obj.Shape.(*resolv.ConvexPolygon)
-				switch t := obj.Data.(type) {
-				case *Player:
-					if meleeBullet.OffenderPlayerId != t.Id {
-						if overlapped, _, _, _ := CalcPushbacks(0, 0, bulletShape, defenderShape); overlapped {
-							xfac := float64(1.0) // By now, straight Punch offset doesn't respect "y-axis"
-							if 0 > offender.DirX {
-								xfac = float64(-1.0)
-							}
-							bulletPushbacks[t.JoinIndex-1].X += xfac * meleeBullet.Pushback
-							nextRenderFramePlayers[t.Id].CharacterState = ATK_CHARACTER_STATE_ATKED1
-							oldFramesToRecover := nextRenderFramePlayers[t.Id].FramesToRecover
-							if meleeBullet.HitStunFrames > oldFramesToRecover {
-								nextRenderFramePlayers[t.Id].FramesToRecover = meleeBullet.HitStunFrames
-							}
-							Logger.Debug(fmt.Sprintf("roomId=%v, a meleeBullet collides w/ player at currRenderFrame.id=%v: b=%v, p=%v", pR.Id, currRenderFrame.Id, ConvexPolygonStr(bulletShape), ConvexPolygonStr(defenderShape)))
-						}
-					}
-				default:
-					Logger.Debug(fmt.Sprintf("Bullet %v collided with non-player %v: roomId=%v, currRenderFrame.Id=%v, delayedInputFrame.Id=%v, objDataType=%t, objData=%v", ConvexPolygonStr(bulletShape), ConvexPolygonStr(defenderShape), pR.Id, currRenderFrame.Id, delayedInputFrame.InputFrameId, obj.Data, obj.Data))
-				}
+			if delayedInputFrameId <= pR.LastAllConfirmedInputFrameId {
+				// [WARNING] It's possible that by now "allConfirmedMask != delayedInputFrame.ConfirmedList && delayedInputFrameId <= pR.LastAllConfirmedInputFrameId", we trust "pR.LastAllConfirmedInputFrameId" as the TOP AUTHORITY.
+				atomic.StoreUint64(&(delayedInputFrame.ConfirmedList), allConfirmedMask)
 			}
-			shouldRemove = true
-		}
-		if shouldRemove {
-			removedBulletsAtCurrFrame[collisionBulletIndex] = 1
-		}
-	}
-
-	for _, meleeBullet := range currRenderFrame.MeleeBullets {
-		collisionBulletIndex := COLLISION_BULLET_INDEX_PREFIX + meleeBullet.BattleLocalId
-		if bulletCollider, existent := collisionSysMap[collisionBulletIndex]; existent {
-			bulletCollider.Space.Remove(bulletCollider)
-			delete(collisionSysMap, collisionBulletIndex)
 		}
-		if _, existent := removedBulletsAtCurrFrame[collisionBulletIndex]; existent {
-			continue
-		}
-		toRet.MeleeBullets = append(toRet.MeleeBullets, meleeBullet)
-	}
 
-	if nil != delayedInputFrame {
 		var delayedInputFrameForPrevRenderFrame *InputFrameDownsync = nil
-		tmp := pR.InputsBuffer.GetByFrameId(pR.ConvertToInputFrameId(currRenderFrame.Id-1, pR.InputDelayFrames))
-		if nil != tmp {
-			delayedInputFrameForPrevRenderFrame = tmp.(*InputFrameDownsync)
-		}
-		inputList := delayedInputFrame.InputList
-		// Process player inputs
-		for playerId, player := range pR.Players {
-			joinIndex := player.JoinIndex
-			collisionPlayerIndex := COLLISION_PLAYER_INDEX_PREFIX + joinIndex
-			playerCollider := collisionSysMap[collisionPlayerIndex]
-			thatPlayerInNextFrame := nextRenderFramePlayers[playerId]
-			if 0 < thatPlayerInNextFrame.FramesToRecover {
-				// No need to process inputs for this player, but there might be bullet pushbacks on this player
-				playerCollider.X += bulletPushbacks[joinIndex-1].X
-				playerCollider.Y += bulletPushbacks[joinIndex-1].Y
-				// Update in the collision system
-				playerCollider.Update()
-				if 0 != bulletPushbacks[joinIndex-1].X || 0 != bulletPushbacks[joinIndex-1].Y {
-					Logger.Info(fmt.Sprintf("roomId=%v, playerId=%v is pushed back by (%.2f, %.2f) by bullet impacts, now its framesToRecover is %d at currRenderFrame.id=%v", pR.Id, playerId, bulletPushbacks[joinIndex-1].X, bulletPushbacks[joinIndex-1].Y, thatPlayerInNextFrame.FramesToRecover, currRenderFrame.Id))
-				}
-				continue
-			}
-			currPlayerDownsync := currRenderFrame.Players[playerId]
-			decodedInput := pR.decodeInput(inputList[joinIndex-1])
-			prevBtnALevel := int32(0)
-			if nil != delayedInputFrameForPrevRenderFrame {
-				prevDecodedInput := pR.decodeInput(delayedInputFrameForPrevRenderFrame.InputList[joinIndex-1])
-				prevBtnALevel = prevDecodedInput.BtnALevel
-			}
-
-			if decodedInput.BtnALevel > prevBtnALevel {
-				punchSkillId := int32(1)
-				punchConfig := pR.MeleeSkillConfig[punchSkillId]
-				var newMeleeBullet MeleeBullet = *punchConfig
-				newMeleeBullet.BattleLocalId = pR.BulletBattleLocalIdCounter
-				pR.BulletBattleLocalIdCounter += 1
-				newMeleeBullet.OffenderJoinIndex = joinIndex
-				newMeleeBullet.OffenderPlayerId = playerId
-				newMeleeBullet.OriginatedRenderFrameId = currRenderFrame.Id
-				toRet.MeleeBullets = append(toRet.MeleeBullets, &newMeleeBullet)
-				thatPlayerInNextFrame.FramesToRecover = newMeleeBullet.RecoveryFrames
-				thatPlayerInNextFrame.CharacterState = ATK_CHARACTER_STATE_ATK1
-				Logger.Info(fmt.Sprintf("roomId=%v, playerId=%v triggered a rising-edge of btnA at currRenderFrame.id=%v, delayedInputFrame.id=%v", pR.Id, playerId, currRenderFrame.Id, delayedInputFrame.InputFrameId))
-
-			} else if decodedInput.BtnALevel < prevBtnALevel {
-				Logger.Debug(fmt.Sprintf("roomId=%v, playerId=%v triggered a falling-edge of btnA at currRenderFrame.id=%v, delayedInputFrame.id=%v", pR.Id, playerId, currRenderFrame.Id, delayedInputFrame.InputFrameId))
-			} else {
-				// No bullet trigger, process movement inputs
-				if 0 != decodedInput.Dx || 0 != decodedInput.Dy {
-					thatPlayerInNextFrame.DirX = decodedInput.Dx
-					thatPlayerInNextFrame.DirY = decodedInput.Dy
-					thatPlayerInNextFrame.CharacterState = ATK_CHARACTER_STATE_WALKING
-				} else {
-					thatPlayerInNextFrame.CharacterState = ATK_CHARACTER_STATE_IDLE1
-				}
-			}
-
-			movementX, movementY := VirtualGridToWorldPos(decodedInput.Dx+decodedInput.Dx*currPlayerDownsync.Speed, decodedInput.Dy+decodedInput.Dy*currPlayerDownsync.Speed, pR.VirtualGridToWorldRatio)
-			playerCollider.X += movementX
-			playerCollider.Y += movementY
-
-			// Update in the collision system
-			playerCollider.Update()
-		}
-
-		// handle pushbacks upon collision after all movements treated as simultaneous
-		for _, player := range pR.Players {
-			joinIndex := player.JoinIndex
-			collisionPlayerIndex := COLLISION_PLAYER_INDEX_PREFIX + joinIndex
-			playerCollider := collisionSysMap[collisionPlayerIndex]
-			if collision := playerCollider.Check(0, 0); collision != nil {
-				playerShape := playerCollider.Shape.(*resolv.ConvexPolygon)
-				for _, obj := range collision.Objects {
-					barrierShape := obj.Shape.(*resolv.ConvexPolygon)
-					if overlapped, pushbackX, pushbackY, overlapResult := CalcPushbacks(0, 0, playerShape, barrierShape); overlapped {
-						Logger.Debug(fmt.Sprintf("Overlapped: a=%v, b=%v, pushbackX=%v, pushbackY=%v", ConvexPolygonStr(playerShape), ConvexPolygonStr(barrierShape), pushbackX, pushbackY))
-						effPushbacks[joinIndex-1].X += pushbackX
-						effPushbacks[joinIndex-1].Y += pushbackY
-					} else {
-						Logger.Debug(fmt.Sprintf("Collided BUT not overlapped: a=%v, b=%v, overlapResult=%v", ConvexPolygonStr(playerShape), ConvexPolygonStr(barrierShape), overlapResult))
-					}
-				}
+		if nil != delayedInputFrame {
+			tmp := pR.InputsBuffer.GetByFrameId(pR.ConvertToDelayedInputFrameId(collisionSysRenderFrameId - 1))
+			if nil != tmp {
+				delayedInputFrameForPrevRenderFrame = tmp.(*InputFrameDownsync)
 			}
 		}
 
-		for playerId, player := range pR.Players {
-			joinIndex := player.JoinIndex
-			collisionPlayerIndex := COLLISION_PLAYER_INDEX_PREFIX + joinIndex
-			playerCollider := collisionSysMap[collisionPlayerIndex]
-
-			// Update "virtual grid position"
-			newVx, newVy := PolygonColliderAnchorToVirtualGridPos(playerCollider.X-effPushbacks[joinIndex-1].X, playerCollider.Y-effPushbacks[joinIndex-1].Y, player.ColliderRadius, player.ColliderRadius, pR.collisionSpaceOffsetX, pR.collisionSpaceOffsetY, pR.WorldToVirtualGridRatio)
-			thatPlayerInNextFrame := nextRenderFramePlayers[playerId]
-			thatPlayerInNextFrame.VirtualGridX, thatPlayerInNextFrame.VirtualGridY = newVx, newVy
+		nextRenderFrame := pR.simulator.Step(delayedInputFrame, delayedInputFrameForPrevRenderFrame, currRenderFrame)
+		if !pR.RenderFrameBuffer.SetByFrameId(nextRenderFrame, nextRenderFrame.Id) {
+			panic(fmt.Sprintf("Failed to set renderFrameId=%v for roomId=%v! RenderFrameBuffer=%v", nextRenderFrame.Id, pR.Id, pR.RenderFrameBufferString()))
 		}
-
-		Logger.Debug(fmt.Sprintf("After applyInputFrameDownsyncDynamicsOnSingleRenderFrame: currRenderFrame.Id=%v, inputList=%v, currRenderFrame.Players=%v, nextRenderFramePlayers=%v", currRenderFrame.Id, inputList, currRenderFrame.Players, nextRenderFramePlayers))
-	}
-
-	return toRet
-}
-
-func (pR *Room) decodeInput(encodedInput uint64) *InputFrameDecoded {
-	encodedDirection := (encodedInput & uint64(15))
-	btnALevel := int32((encodedInput >> 4) & 1)
-	return &InputFrameDecoded{
-		Dx:        DIRECTION_DECODER[encodedDirection][0],
-		Dy:        DIRECTION_DECODER[encodedDirection][1],
-		BtnALevel: btnALevel,
+		pR.CurDynamicsRenderFrameId++
 	}
 }
 
@@ -1441,30 +1787,12 @@ func (pR *Room) inputFrameIdDebuggable(inputFrameId int32) bool {
 	return 0 == (inputFrameId % 10)
 }
 
-func (pR *Room) refreshColliders(spaceW, spaceH int32) {
-	// Kindly note that by now, we've already got all the shapes in the tmx file into "pR.(Players | Barriers)" from "ParseTmxLayersAndGroups"
-
-	minStep := (int(float64(pR.PlayerDefaultSpeed)*pR.VirtualGridToWorldRatio) << 1) // the approx minimum distance a player can move per frame in world coordinate
-	pR.Space = resolv.NewSpace(int(spaceW), int(spaceH), minStep, minStep)           // allocate a new collision space everytime after a battle is settled
+func (pR *Room) refreshColliders() {
+	// Kindly note that by now, we've already got all the shapes in the tmx file into "pR.StrToPolygon2DListMap" from "ParseTmxLayersAndGroups"
 	for _, player := range pR.Players {
-		wx, wy := VirtualGridToWorldPos(player.VirtualGridX, player.VirtualGridY, pR.VirtualGridToWorldRatio)
-		playerCollider := GenerateRectCollider(wx, wy, player.ColliderRadius*2, player.ColliderRadius*2, pR.collisionSpaceOffsetX, pR.collisionSpaceOffsetY, "Player")
-		playerCollider.Data = player
-		pR.Space.Add(playerCollider)
-		// Keep track of the collider in "pR.CollisionSysMap"
-		joinIndex := player.JoinIndex
-		pR.PlayersArr[joinIndex-1] = player
-		collisionPlayerIndex := COLLISION_PLAYER_INDEX_PREFIX + joinIndex
-		pR.CollisionSysMap[collisionPlayerIndex] = playerCollider
+		pR.PlayersArr[player.JoinIndex-1] = player
 	}
 
-	for _, barrier := range pR.Barriers {
-		boundaryUnaligned := barrier.Boundary
-		barrierCollider := GenerateConvexPolygonCollider(boundaryUnaligned, pR.collisionSpaceOffsetX, pR.collisionSpaceOffsetY, "Barrier")
-		pR.Space.Add(barrierCollider)
-	}
-}
-
-func (pR *Room) printBarrier(barrierCollider *resolv.Object) {
-	Logger.Info(fmt.Sprintf("Barrier in roomId=%v: w=%v, h=%v, shape=%v", pR.Id, barrierCollider.W, barrierCollider.H, barrierCollider.Shape))
+	pR.Stage = battle.NewStage(&pR.BattleColliderInfo, pR.Capacity, pR.PlayerDefaultSpeed)
+	pR.simulator = battle.NewSimulator(pR.Stage) // allocate a new collision space everytime after a battle is settled
 }
@@ -1,10 +1,12 @@
 package models
 
 import (
+	. "battle_srv/common"
 	"container/heap"
 	. "dnmshared"
 	"fmt"
 	"go.uber.org/zap"
+	"regexp"
 	"sync"
 )
 
@@ -80,28 +82,231 @@ func PrintRoomMap() {
 	fmt.Printf("\n")
 }
 
+/*
+Per-room parameters, either "DefaultRoomParams" from "room.json" or customized upon "ReservePrivateRoom".
+*/
+type RoomParams struct {
+	StageName                  string // Empty for a random one upon each "OnDismissed"
+	Capacity                   int
+	TeamCount                  int32 // 0 for free-for-all, i.e. every player is a team of its own
+	FriendlyFireEnabled        bool
+	BattleDurationSeconds      int32
+	ReadyCheckSeconds          int32 // 0 to start the battle right after the room is full
+	RematchWindowSeconds       int32 // 0 to dismiss the room right after the settlement
+	AdaptiveInputDelayEnabled  bool
+	BackendRollbackEnabled     bool
+	ResyncUponChecksumMismatch bool
+	FixedPointCollisionEnabled bool
+	PlayerCollisionEnabled     bool
+	KoRespawnFrames            int32 // 0 for elimination
+	ReplayRecordingEnabled     bool  // Not overridable by "ReservePrivateRoom", because replays are saved on the disk of the server
+}
+
+var (
+	validStageNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_]*$`) // Used to compose the tmx file path, thus no path separator is allowed
+	lastRoomId          int32                                   // Only accessed with "RoomHeapMux" locked, never reused such that a stale "boundRoomId" won't rejoin a recreated room
+)
+
+func DefaultRoomParams() *RoomParams {
+	return &RoomParams{
+		StageName:                  Conf.Room.StageName,
+		Capacity:                   Conf.Room.Capacity,
+		TeamCount:                  int32(Conf.Room.TeamCount),
+		FriendlyFireEnabled:        Conf.Room.FriendlyFireEnabled,
+		BattleDurationSeconds:      int32(Conf.Room.BattleDurationSeconds),
+		ReadyCheckSeconds:          int32(Conf.Room.ReadyCheckSeconds),
+		RematchWindowSeconds:       int32(Conf.Room.RematchWindowSeconds),
+		AdaptiveInputDelayEnabled:  Conf.Room.AdaptiveInputDelayEnabled,
+		BackendRollbackEnabled:     Conf.Room.BackendRollbackEnabled,
+		ResyncUponChecksumMismatch: Conf.Room.ResyncUponChecksumMismatch,
+		FixedPointCollisionEnabled: Conf.Room.FixedPointCollisionEnabled,
+		PlayerCollisionEnabled:     Conf.Room.PlayerCollisionEnabled,
+		KoRespawnFrames:            int32(Conf.Room.KoRespawnFrames),
+		ReplayRecordingEnabled:     Conf.Room.ReplayRecordingEnabled,
+	}
+}
+
+func (pParams *RoomParams) Validate() error {
+	if 2 > pParams.Capacity || MAX_ROOM_CAPACITY < pParams.Capacity {
+		return fmt.Errorf("Room capacity=%v is out of [2, %v]", pParams.Capacity, MAX_ROOM_CAPACITY)
+	}
+	if 1 < pParams.TeamCount && (int(pParams.TeamCount) > pParams.Capacity || 0 != pParams.Capacity%int(pParams.TeamCount)) {
+		return fmt.Errorf("Room capacity=%v can't be divided evenly into teamCount=%v", pParams.Capacity, pParams.TeamCount)
+	}
+	if 0 >= pParams.BattleDurationSeconds {
+		return fmt.Errorf("Invalid battleDurationSeconds=%v", pParams.BattleDurationSeconds)
+	}
+	if 0 > pParams.ReadyCheckSeconds {
+		return fmt.Errorf("Invalid readyCheckSeconds=%v", pParams.ReadyCheckSeconds)
+	}
+	if 0 > pParams.RematchWindowSeconds {
+		return fmt.Errorf("Invalid rematchWindowSeconds=%v", pParams.RematchWindowSeconds)
+	}
+	if 0 > pParams.KoRespawnFrames {
+		return fmt.Errorf("Invalid koRespawnFrames=%v", pParams.KoRespawnFrames)
+	}
+	if !validStageNameRegex.MatchString(pParams.StageName) {
+		return fmt.Errorf("Invalid stageName=%v", pParams.StageName)
+	}
+	// Otherwise "ChooseStage" would panic upon "OnDismissed" with "RoomHeapMux" locked
+	candidateStageNames := randomStageNameList
+	if "" != pParams.StageName {
+		candidateStageNames = []string{pParams.StageName}
+	}
+	for _, stageName := range candidateStageNames {
+		if _, err := parseStage(stageName, pParams.Capacity, pParams.TeamCount); nil != err {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+Creates a room in IDLE state and pushes it into both "RoomHeapManagerIns" and "RoomMapManagerIns", returns an error if "Conf.Room.MaxRoomCount" is reached or "params" is invalid.
+
+[WARNING] The caller MUST have "RoomHeapMux" locked.
+*/
+func CreateRoom(params *RoomParams) (*Room, error) {
+	if err := params.Validate(); nil != err {
+		return nil, err
+	}
+	if Conf.Room.MaxRoomCount <= len(*RoomMapManagerIns) {
+		return nil, fmt.Errorf("Room count=%v has reached the limit", len(*RoomMapManagerIns))
+	}
+	lastRoomId++
+	pR := &Room{
+		Id:       lastRoomId,
+		Capacity: params.Capacity,
+		Params:   *params,
+	}
+	pR.OnDismissed()
+	heap.Push(RoomHeapManagerIns, pR)
+	(*RoomMapManagerIns)[pR.Id] = pR
+	Logger.Info("A room is created:", zap.Any("roomId", pR.Id), zap.Any("params", params), zap.Any("roomCount", len(*RoomMapManagerIns)))
+	return pR, nil
+}
+
+/*
+Pops the room of the highest score, or creates one by "DefaultRoomParams" if none is available. Returns nil only if "Conf.Room.MaxRoomCount" is reached.
+
+[WARNING] The caller MUST have "RoomHeapMux" locked, and push the popped room back afterwards.
+*/
+func PopAvailableRoom() *Room {
+	if pR := RoomHeapManagerIns.popIfAny(); nil != pR {
+		return pR
+	}
+	if _, err := CreateRoom(DefaultRoomParams()); nil != err {
+		Logger.Warn("PopAvailableRoom failed to create a room:", zap.Error(err))
+		return nil
+	}
+	return RoomHeapManagerIns.popIfAny()
+}
+
+/*
+Returns nil if empty or the room of the highest score is not available.
+
+[WARNING] "heap.Pop" MUST NOT be called on an empty heap, because it swaps the first element with the last one before "RoomHeap.Pop" could check the length, which panics by index out of range. The heap is emptied whenever all rooms are taken by "ReservePrivateRoom", "reserveMatchmadeRoom" or destroyed by "recycleDismissedRoom".
+*/
+func (pq *RoomHeap) popIfAny() *Room {
+	if 0 == pq.Len() {
+		return nil
+	}
+	if pR, ok := heap.Pop(pq).(*Room); ok {
+		return pR
+	}
+	return nil
+}
+
+/*
+Returns an empty IDLE room reserved neither for a matchmaking group nor by an invite code, creating one by "DefaultRoomParams" if none is found.
+
+[WARNING] The caller MUST have "RoomHeapMux" locked.
+*/
+func takeIdleRoom() *Room {
+	for _, pR := range *RoomMapManagerIns {
+		if RoomBattleStateIns.IDLE == pR.State && 0 == pR.EffectivePlayerCount && "" == pR.inviteCode && nil == pR.matchedPlayerIds {
+			return pR
+		}
+	}
+	pR, err := CreateRoom(DefaultRoomParams())
+	if nil != err {
+		Logger.Warn("takeIdleRoom failed to create a room:", zap.Error(err))
+		return nil
+	}
+	return pR
+}
+
+// Unlike "heap.Remove", this doesn't go through "RoomHeap.Pop" which refuses rooms with non-positive scores.
+func (pq *RoomHeap) remove(pItem *Room) {
+	i, n := pItem.Index, len(*pq)-1
+	if 0 > i || n < i || (*pq)[i] != pItem {
+		return
+	}
+	if i != n {
+		pq.Swap(i, n)
+	}
+	(*pq)[n] = nil
+	*pq = (*pq)[:n]
+	pItem.Index = -1
+	if i != n {
+		heap.Fix(pq, i)
+	}
+}
+
+/*
+Called right after "OnDismissed" by the goroutine of "battleMainLoop", releases the private or matchmaking reservation if any, then destroys "pR" if there're already more than "Conf.Room.MaxIdleRoomCount" idle rooms, such that a burst of concurrent battles doesn't keep its rooms forever.
+*/
+func recycleDismissedRoom(pR *Room) {
+	(*RoomHeapMux).Lock()
+	defer (*RoomHeapMux).Unlock()
+	releasePrivateRoom(pR)
+	releaseMatchmadeRoom(pR)
+	if _, existent := (*RoomMapManagerIns)[pR.Id]; !existent {
+		// A customized private room is already destroyed upon release
+		return
+	}
+	if RoomBattleStateIns.IDLE != pR.State {
+		// Already taken by a newly joined player
+		return
+	}
+	idleRoomCount := 0
+	for _, pOther := range *RoomMapManagerIns {
+		if RoomBattleStateIns.IDLE == pOther.State {
+			idleRoomCount++
+		}
+	}
+	if idleRoomCount <= Conf.Room.MaxIdleRoomCount {
+		return
+	}
+	RoomHeapManagerIns.remove(pR)
+	delete(*RoomMapManagerIns, pR.Id)
+	Logger.Info("A redundant room is destroyed:", zap.Any("roomId", pR.Id), zap.Any("idleRoomCount", idleRoomCount), zap.Any("roomCount", len(*RoomMapManagerIns)))
+}
+
 func InitRoomHeapManager() {
 	RoomHeapMux = new(sync.Mutex)
 	// Init "pseudo class constants".
 	InitRoomBattleStateIns()
 	InitPlayerBattleStateIns()
-	initialCountOfRooms := 32
-	pq := make(RoomHeap, initialCountOfRooms)
-	roomMap := make(RoomMap, initialCountOfRooms)
-
-	for i := 0; i < initialCountOfRooms; i++ {
-		roomCapacity := 2
-		pq[i] = &Room{
-			Id:       int32(i + 1),
-			Capacity: roomCapacity,
-			Index:    i,
-		}
-		roomMap[pq[i].Id] = pq[i]
-		pq[i].OnDismissed()
+	defaultParams := DefaultRoomParams()
+	if err := defaultParams.Validate(); nil != err {
+		panic(err)
+	}
+	if Conf.Room.InitialRoomCount > Conf.Room.MaxRoomCount {
+		panic(fmt.Sprintf("initialRoomCount=%v exceeds maxRoomCount=%v", Conf.Room.InitialRoomCount, Conf.Room.MaxRoomCount))
 	}
-	heap.Init(&pq)
+	pq := make(RoomHeap, 0, Conf.Room.MaxRoomCount)
+	roomMap := make(RoomMap, Conf.Room.MaxRoomCount)
 	RoomHeapManagerIns = &pq
 	RoomMapManagerIns = &roomMap
+
+	(*RoomHeapMux).Lock()
+	defer (*RoomHeapMux).Unlock()
+	for i := 0; i < Conf.Room.InitialRoomCount; i++ {
+		if _, err := CreateRoom(defaultParams); nil != err {
+			panic(err)
+		}
+	}
 	Logger.Info("The RoomHeapManagerIns has been initialized:", zap.Any("addr", fmt.Sprintf("%p", RoomHeapManagerIns)), zap.Any("size", len(*RoomHeapManagerIns)))
 	Logger.Info("The RoomMapManagerIns has been initialized:", zap.Any("size", len(*RoomMapManagerIns)))
 }
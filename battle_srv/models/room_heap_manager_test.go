@@ -0,0 +1,23 @@
+package models
+
+import (
+	"container/heap"
+	"testing"
+)
+
+func TestDrainedRoomHeapPopsNothing(t *testing.T) {
+	pq := make(RoomHeap, 0)
+	for roomId := int32(1); roomId <= 2; roomId++ {
+		heap.Push(&pq, &Room{Id: roomId, Score: float32(roomId)})
+	}
+	for _, expectedRoomId := range []int32{2, 1} {
+		if pR := pq.popIfAny(); nil == pR || expectedRoomId != pR.Id {
+			t.Fatalf("Expected roomId=%v to be popped, got %v", expectedRoomId, pR)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if pR := pq.popIfAny(); nil != pR {
+			t.Fatalf("Expected nothing popped from a drained heap, got roomId=%v", pR.Id)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package models
+
+import (
+	. "battle_srv/common"
+	"battle_srv/common/utils"
+	. "battle_srv/protos"
+	. "dnmshared"
+	"fmt"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+)
+
+const (
+	MIN_INPUT_DELAY_FRAMES = int32(4)
+	MAX_INPUT_DELAY_FRAMES = int32(24)
+
+	INPUT_DELAY_RENEGOTIATION_PERIOD_RENDER_FRAMES = int32(300)
+)
+
+func (p *Player) resetRtt() {
+	atomic.StoreInt32(&p.RttMillis, 0)
+	atomic.StoreInt32(&p.RttVarMillis, 0)
+	atomic.StoreInt32(&p.RttSampleCount, 0)
+}
+
+/*
+Smooths the RTT samples the same way as TCP does, i.e. by RFC6298.
+
+[WARNING] Only called by the receiving goroutine of the player, thus no need for a CAS loop.
+*/
+func (p *Player) onRttSampled(rttMillis int32) {
+	if 0 == atomic.LoadInt32(&p.RttSampleCount) {
+		atomic.StoreInt32(&p.RttMillis, rttMillis)
+		atomic.StoreInt32(&p.RttVarMillis, rttMillis>>1)
+	} else {
+		srtt, rttVar := atomic.LoadInt32(&p.RttMillis), atomic.LoadInt32(&p.RttVarMillis)
+		diff := srtt - rttMillis
+		if 0 > diff {
+			diff = -diff
+		}
+		atomic.StoreInt32(&p.RttVarMillis, (3*rttVar+diff)>>2)
+		atomic.StoreInt32(&p.RttMillis, (7*srtt+rttMillis)>>3)
+	}
+	atomic.AddInt32(&p.RttSampleCount, 1)
+}
+
+/*
+Called by the receiving goroutine of the player for each "UPSYNC_MSG_ACT_HB_PING". The RTT is sampled by the echoed fields, i.e. "(now - echoedServerTimestamp) - (clientTimestamp - echoReceivedClientTimestamp)" which excludes the time the client held the echo, then the heartbeat is echoed back.
+
+Heartbeats are dropped unless "AdaptiveInputDelayEnabled", the only consumer of the samples.
+*/
+func (pR *Room) OnHeartbeatReceived(playerId int32, hb *HeartbeatUpsync) {
+	defer func() {
+		if r := recover(); r != nil {
+			Logger.Warn("OnHeartbeatReceived, recovered from: ", zap.Any("roomId", pR.Id), zap.Any("playerId", playerId), zap.Any("panic", r))
+		}
+	}()
+	if nil == hb || !pR.AdaptiveInputDelayEnabled {
+		return
+	}
+	player, existent := pR.Players[playerId]
+	if !existent {
+		return
+	}
+
+	nowMillis := utils.UnixtimeMilli()
+	if 0 < hb.EchoedServerTimestamp && 0 < hb.EchoReceivedClientTimestamp {
+		rttMillis := (nowMillis - hb.EchoedServerTimestamp) - (hb.ClientTimestamp - hb.EchoReceivedClientTimestamp)
+		if 0 <= rttMillis {
+			player.onRttSampled(int32(rttMillis))
+		}
+	}
+
+	if _, existent := pR.PlayerDownsyncSessionDict[playerId]; !existent {
+		return
+	}
+	pR.sendRespSafely(&WsResp{
+		Ret: int32(Constants.RetCode.Ok),
+		Act: DOWNSYNC_MSG_ACT_HB_ECHO,
+		Hb: &HeartbeatDownsync{
+			ServerTimestamp:       nowMillis,
+			EchoedClientTimestamp: hb.ClientTimestamp,
+		},
+	}, playerId)
+}
+
+/*
+The delay covering the worst "SRTT + 2*RTTVAR" among players, rounded up to a multiple of "1 << InputScaleFrames" and clamped by "[MIN_INPUT_DELAY_FRAMES, MAX_INPUT_DELAY_FRAMES]". Returns "fallback" if no player has been sampled yet.
+*/
+func (pR *Room) chooseInputDelayFrames(fallback int32) int32 {
+	worstMillis := int32(-1)
+	for _, player := range pR.Players {
+		if swapped := atomic.CompareAndSwapInt32(&player.BattleState, PlayerBattleStateIns.ACTIVE, PlayerBattleStateIns.ACTIVE); !swapped {
+			continue
+		}
+		if 0 == atomic.LoadInt32(&player.RttSampleCount) {
+			continue
+		}
+		estimated := atomic.LoadInt32(&player.RttMillis) + 2*atomic.LoadInt32(&player.RttVarMillis)
+		if estimated > worstMillis {
+			worstMillis = estimated
+		}
+	}
+	if 0 > worstMillis {
+		return fallback
+	}
+	inputDelayFrames := int32((int64(worstMillis)*1000000 + pR.RollbackEstimatedDtNanos - 1) / pR.RollbackEstimatedDtNanos)
+	alignment := int32(1 << pR.InputScaleFrames)
+	inputDelayFrames = ((inputDelayFrames + alignment - 1) / alignment) * alignment
+	if MIN_INPUT_DELAY_FRAMES > inputDelayFrames {
+		inputDelayFrames = MIN_INPUT_DELAY_FRAMES
+	}
+	if MAX_INPUT_DELAY_FRAMES < inputDelayFrames {
+		inputDelayFrames = MAX_INPUT_DELAY_FRAMES
+	}
+	return inputDelayFrames
+}
+
+func (pR *Room) currentInputDelayFrames() int32 {
+	if 0 < len(pR.InputDelayFramesSwitches) {
+		return pR.InputDelayFramesSwitches[len(pR.InputDelayFramesSwitches)-1].InputDelayFrames
+	}
+	return pR.InputDelayFrames
+}
+
+/*
+Called by "battleMainLoop" for each renderFrame. A switch is scheduled at a renderFrameId well ahead of "pR.RenderFrameId", such that the frontends receive it before using any inputFrame under the new delay. The delay is increased as soon as needed, but only decreased by more than one inputFrame to avoid oscillation.
+*/
+func (pR *Room) renegotiateInputDelayIfApplicable() {
+	if 0 == pR.RenderFrameId || 0 != (pR.RenderFrameId%INPUT_DELAY_RENEGOTIATION_PERIOD_RENDER_FRAMES) {
+		return
+	}
+	if 0 < len(pR.InputDelayFramesSwitches) && pR.InputDelayFramesSwitches[len(pR.InputDelayFramesSwitches)-1].FromRenderFrameId > pR.RenderFrameId {
+		// The previous switch is not yet effective
+		return
+	}
+	current := pR.currentInputDelayFrames()
+	desired := pR.chooseInputDelayFrames(current)
+	if desired <= current && desired >= current-(1<<pR.InputScaleFrames) {
+		return
+	}
+	alignment := int32(1 << pR.InputScaleFrames)
+	fromRenderFrameId := pR.RenderFrameId + 2*MAX_INPUT_DELAY_FRAMES
+	fromRenderFrameId = ((fromRenderFrameId + alignment - 1) / alignment) * alignment
+	pR.InputDelayFramesSwitches = append(pR.InputDelayFramesSwitches, &InputDelayFramesSwitch{
+		FromRenderFrameId: fromRenderFrameId,
+		InputDelayFrames:  desired,
+	})
+	pR.NstDelayFrames = (desired >> 1)
+	Logger.Info(fmt.Sprintf("Input delay renegotiated: roomId=%v, renderFrameId=%v, fromRenderFrameId=%v, inputDelayFrames=%v->%v", pR.Id, pR.RenderFrameId, fromRenderFrameId, current, desired))
+
+	bciFrame := pR.BuildBattleColliderInfo(PlayerBattleStateIns.ACTIVE)
+	for playerId, player := range pR.Players {
+		if swapped := atomic.CompareAndSwapInt32(&player.BattleState, PlayerBattleStateIns.ACTIVE, PlayerBattleStateIns.ACTIVE); !swapped {
+			continue
+		}
+		pR.sendInputDelaySwitchedSafely(bciFrame, playerId)
+	}
+}
+
+func (pR *Room) sendInputDelaySwitchedSafely(bciFrame *BattleColliderInfo, playerId int32) {
+	defer func() {
+		if r := recover(); r != nil {
+			pR.PlayerSignalToCloseDict[playerId](Constants.RetCode.UnknownError, fmt.Sprintf("%v", r))
+		}
+	}()
+	pR.sendRespSafely(&WsResp{
+		Ret:      int32(Constants.RetCode.Ok),
+		Act:      DOWNSYNC_MSG_ACT_INPUT_DELAY_SWITCHED,
+		BciFrame: bciFrame,
+	}, playerId)
+}
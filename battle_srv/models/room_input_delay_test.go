@@ -0,0 +1,54 @@
+package models
+
+import (
+	"battle_srv/common/utils"
+	. "battle_srv/protos"
+	"testing"
+)
+
+func TestInputDelayFramesAreChosenBySampledRtt(t *testing.T) {
+	InitPlayerBattleStateIns()
+	pR := &Room{
+		Players: make(map[int32]*Player),
+	}
+	pR.InputScaleFrames = 2
+	pR.RollbackEstimatedDtNanos = 16666666
+	for playerId := int32(1); playerId <= 3; playerId++ {
+		pR.Players[playerId] = &Player{}
+		pR.Players[playerId].BattleState = PlayerBattleStateIns.ACTIVE
+	}
+	pR.Players[3].BattleState = PlayerBattleStateIns.DISCONNECTED
+
+	pingWithRtt := func(playerId int32, rttMillis int64) {
+		now := utils.UnixtimeMilli()
+		pR.OnHeartbeatReceived(playerId, &HeartbeatUpsync{
+			ClientTimestamp:             now,
+			EchoedServerTimestamp:       now - rttMillis,
+			EchoReceivedClientTimestamp: now,
+		})
+	}
+
+	pingWithRtt(1, 150)
+	if 0 != pR.Players[1].RttSampleCount {
+		t.Fatalf("Heartbeats should be dropped unless AdaptiveInputDelayEnabled")
+	}
+
+	pR.AdaptiveInputDelayEnabled = true
+	if chosen := pR.chooseInputDelayFrames(8); 8 != chosen {
+		t.Fatalf("The fallback should be chosen before any sample, got %v", chosen)
+	}
+	for i := 0; i < 20; i++ {
+		pingWithRtt(1, 150)
+		pingWithRtt(2, 50)
+		pingWithRtt(3, 1000) // Not ACTIVE thus ignored
+	}
+	// The worst smoothed RTT ~150ms takes 9+ frames of 16.67ms, rounded up to a multiple of 4
+	if chosen := pR.chooseInputDelayFrames(8); 12 != chosen {
+		t.Fatalf("Expected 12 input delay frames by the worst RTT, got %v", chosen)
+	}
+
+	pR.Players[3].BattleState = PlayerBattleStateIns.ACTIVE
+	if chosen := pR.chooseInputDelayFrames(8); MAX_INPUT_DELAY_FRAMES != chosen {
+		t.Fatalf("Expected the input delay clamped to %v, got %v", MAX_INPUT_DELAY_FRAMES, chosen)
+	}
+}
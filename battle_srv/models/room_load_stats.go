@@ -0,0 +1,128 @@
+package models
+
+import (
+	"battle_srv/common/utils"
+	"sort"
+	"sync/atomic"
+)
+
+/*
+Per-battle statistics of a room for load testing, reset upon each "StartBattle". All fields are updated atomically, because they're written by "battleMainLoop" and read by the http handlers.
+*/
+type RoomLoadStats struct {
+	BattleStartedAtNanos  int64
+	BattleStoppedAtNanos  int64 // 0 if still in battle
+	RenderFrameCount      int64
+	SlowFrameCount        int64 // The count of "SLOW FRAME!"s, i.e. "elapsedInCalculation > dilutedRollbackEstimatedDtNanos" in "battleMainLoop"
+	TotalElapsedNanos     int64
+	MaxElapsedNanos       int64
+	TotalDynamicsNanos    int64
+	DownsyncMsgCount      int64
+	DownsyncBytes         int64
+	ForcedResyncCount     int64
+	ChecksumMismatchCount int64
+}
+
+type RoomLoadStatsSnapshot struct {
+	RoomId                int32   `json:"roomId"`
+	State                 int32   `json:"state"`
+	EffectivePlayerCount  int32   `json:"effectivePlayerCount"`
+	RenderFrameId         int32   `json:"renderFrameId"`
+	BattleElapsedNanos    int64   `json:"battleElapsedNanos"`
+	RenderFrameCount      int64   `json:"renderFrameCount"`
+	SlowFrameCount        int64   `json:"slowFrameCount"`
+	SlowFrameRatio        float64 `json:"slowFrameRatio"`
+	AvgElapsedNanos       int64   `json:"avgElapsedNanos"`
+	MaxElapsedNanos       int64   `json:"maxElapsedNanos"`
+	AvgDynamicsNanos      int64   `json:"avgDynamicsNanos"`
+	DownsyncMsgCount      int64   `json:"downsyncMsgCount"`
+	DownsyncBytes         int64   `json:"downsyncBytes"`
+	DownsyncBytesPerSec   float64 `json:"downsyncBytesPerSec"`
+	ForcedResyncCount     int64   `json:"forcedResyncCount"`
+	ForcedResyncPerSec    float64 `json:"forcedResyncPerSec"`
+	ChecksumMismatchCount int64   `json:"checksumMismatchCount"`
+}
+
+func (pS *RoomLoadStats) reset() {
+	atomic.StoreInt64(&pS.BattleStartedAtNanos, utils.UnixtimeNano())
+	atomic.StoreInt64(&pS.BattleStoppedAtNanos, 0)
+	atomic.StoreInt64(&pS.RenderFrameCount, 0)
+	atomic.StoreInt64(&pS.SlowFrameCount, 0)
+	atomic.StoreInt64(&pS.TotalElapsedNanos, 0)
+	atomic.StoreInt64(&pS.MaxElapsedNanos, 0)
+	atomic.StoreInt64(&pS.TotalDynamicsNanos, 0)
+	atomic.StoreInt64(&pS.DownsyncMsgCount, 0)
+	atomic.StoreInt64(&pS.DownsyncBytes, 0)
+	atomic.StoreInt64(&pS.ForcedResyncCount, 0)
+	atomic.StoreInt64(&pS.ChecksumMismatchCount, 0)
+}
+
+func (pS *RoomLoadStats) onRenderFrameCalculated(elapsedInCalculation int64, dynamicsDuration int64, isSlow bool) {
+	atomic.AddInt64(&pS.RenderFrameCount, 1)
+	atomic.AddInt64(&pS.TotalElapsedNanos, elapsedInCalculation)
+	atomic.AddInt64(&pS.TotalDynamicsNanos, dynamicsDuration)
+	if isSlow {
+		atomic.AddInt64(&pS.SlowFrameCount, 1)
+	}
+	// Only "battleMainLoop" writes "MaxElapsedNanos", thus no need for a CAS loop
+	if elapsedInCalculation > atomic.LoadInt64(&pS.MaxElapsedNanos) {
+		atomic.StoreInt64(&pS.MaxElapsedNanos, elapsedInCalculation)
+	}
+}
+
+func (pS *RoomLoadStats) onDownsyncSent(act int32, byteCnt int) {
+	atomic.AddInt64(&pS.DownsyncMsgCount, 1)
+	atomic.AddInt64(&pS.DownsyncBytes, int64(byteCnt))
+	if DOWNSYNC_MSG_ACT_FORCED_RESYNC == act {
+		atomic.AddInt64(&pS.ForcedResyncCount, 1)
+	}
+}
+
+func (pR *Room) LoadStatsSnapshot() *RoomLoadStatsSnapshot {
+	pS := &(pR.LoadStats)
+	ret := &RoomLoadStatsSnapshot{
+		RoomId:                pR.Id,
+		State:                 atomic.LoadInt32(&pR.State),
+		EffectivePlayerCount:  atomic.LoadInt32(&pR.EffectivePlayerCount),
+		RenderFrameId:         atomic.LoadInt32(&pR.RenderFrameId),
+		RenderFrameCount:      atomic.LoadInt64(&pS.RenderFrameCount),
+		SlowFrameCount:        atomic.LoadInt64(&pS.SlowFrameCount),
+		MaxElapsedNanos:       atomic.LoadInt64(&pS.MaxElapsedNanos),
+		DownsyncMsgCount:      atomic.LoadInt64(&pS.DownsyncMsgCount),
+		DownsyncBytes:         atomic.LoadInt64(&pS.DownsyncBytes),
+		ForcedResyncCount:     atomic.LoadInt64(&pS.ForcedResyncCount),
+		ChecksumMismatchCount: atomic.LoadInt64(&pS.ChecksumMismatchCount),
+	}
+	battleStartedAtNanos, battleStoppedAtNanos := atomic.LoadInt64(&pS.BattleStartedAtNanos), atomic.LoadInt64(&pS.BattleStoppedAtNanos)
+	if 0 < battleStartedAtNanos {
+		if 0 >= battleStoppedAtNanos {
+			battleStoppedAtNanos = utils.UnixtimeNano()
+		}
+		ret.BattleElapsedNanos = battleStoppedAtNanos - battleStartedAtNanos
+	}
+	if 0 < ret.RenderFrameCount {
+		ret.SlowFrameRatio = float64(ret.SlowFrameCount) / float64(ret.RenderFrameCount)
+		ret.AvgElapsedNanos = atomic.LoadInt64(&pS.TotalElapsedNanos) / ret.RenderFrameCount
+		ret.AvgDynamicsNanos = atomic.LoadInt64(&pS.TotalDynamicsNanos) / ret.RenderFrameCount
+	}
+	if 0 < ret.BattleElapsedNanos {
+		elapsedSeconds := float64(ret.BattleElapsedNanos) / 1e9
+		ret.DownsyncBytesPerSec = float64(ret.DownsyncBytes) / elapsedSeconds
+		ret.ForcedResyncPerSec = float64(ret.ForcedResyncCount) / elapsedSeconds
+	}
+	return ret
+}
+
+// Ordered by "RoomId".
+func AllRoomLoadStatsSnapshots() []*RoomLoadStatsSnapshot {
+	(*RoomHeapMux).Lock()
+	defer (*RoomHeapMux).Unlock()
+	ret := make([]*RoomLoadStatsSnapshot, 0, len(*RoomMapManagerIns))
+	for _, pR := range *RoomMapManagerIns {
+		ret = append(ret, pR.LoadStatsSnapshot())
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].RoomId < ret[j].RoomId
+	})
+	return ret
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"battle_srv/metrics"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+/*
+Collects the gauges of all rooms upon each scrape instead of updating them at each state transition, because "Room.State" is CAS-ed at too many places. Being a "prometheus.Collector", the series of a room no longer existing are never exposed again.
+*/
+type RoomsCollector struct{}
+
+var (
+	roomsByStateDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.NAMESPACE, "", "rooms"),
+		"Count of rooms by \"RoomBattleStateIns\" state.",
+		[]string{"state"}, nil,
+	)
+	inputsBufferOccupancyDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.NAMESPACE, "", "inputs_buffer_occupancy"),
+		"Ratio of \"InputsBuffer.Cnt\" to \"InputsBuffer.N\" of a room.",
+		[]string{"roomId"}, nil,
+	)
+	renderFrameBufferOccupancyDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.NAMESPACE, "", "render_frame_buffer_occupancy"),
+		"Ratio of \"RenderFrameBuffer.Cnt\" to \"RenderFrameBuffer.N\" of a room.",
+		[]string{"roomId"}, nil,
+	)
+)
+
+func (c RoomsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- roomsByStateDesc
+	ch <- inputsBufferOccupancyDesc
+	ch <- renderFrameBufferOccupancyDesc
+}
+
+func (c RoomsCollector) Collect(ch chan<- prometheus.Metric) {
+	stateNames := map[int32]string{
+		RoomBattleStateIns.IDLE:                           "IDLE",
+		RoomBattleStateIns.WAITING:                        "WAITING",
+		RoomBattleStateIns.PREPARE:                        "PREPARE",
+		RoomBattleStateIns.IN_BATTLE:                      "IN_BATTLE",
+		RoomBattleStateIns.STOPPING_BATTLE_FOR_SETTLEMENT: "STOPPING_BATTLE_FOR_SETTLEMENT",
+		RoomBattleStateIns.IN_SETTLEMENT:                  "IN_SETTLEMENT",
+		RoomBattleStateIns.IN_DISMISSAL:                   "IN_DISMISSAL",
+	}
+	countsByState := make(map[string]int, len(stateNames))
+	for _, stateName := range stateNames {
+		countsByState[stateName] = 0
+	}
+
+	(*RoomHeapMux).Lock()
+	defer (*RoomHeapMux).Unlock()
+	for _, pR := range *RoomMapManagerIns {
+		if stateName, existent := stateNames[atomic.LoadInt32(&pR.State)]; existent {
+			countsByState[stateName]++
+		}
+		roomIdStr := fmt.Sprintf("%d", pR.Id)
+		if inputsBuffer := pR.InputsBuffer; nil != inputsBuffer && 0 < inputsBuffer.N {
+			ch <- prometheus.MustNewConstMetric(inputsBufferOccupancyDesc, prometheus.GaugeValue, float64(atomic.LoadInt32(&inputsBuffer.Cnt))/float64(inputsBuffer.N), roomIdStr)
+		}
+		if renderFrameBuffer := pR.RenderFrameBuffer; nil != renderFrameBuffer && 0 < renderFrameBuffer.N {
+			ch <- prometheus.MustNewConstMetric(renderFrameBufferOccupancyDesc, prometheus.GaugeValue, float64(atomic.LoadInt32(&renderFrameBuffer.Cnt))/float64(renderFrameBuffer.N), roomIdStr)
+		}
+	}
+	for stateName, cnt := range countsByState {
+		ch <- prometheus.MustNewConstMetric(roomsByStateDesc, prometheus.GaugeValue, float64(cnt), stateName)
+	}
+}
@@ -0,0 +1,150 @@
+package models
+
+import (
+	. "battle_srv/common"
+	"container/heap"
+	"crypto/rand"
+	. "dnmshared"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	PRIVATE_ROOM_INVITE_CODE_LEN          = 6
+	PRIVATE_ROOM_INVITE_CODE_ALPHABET     = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // Without the easily confused "I", "O", "0" and "1"
+	PRIVATE_ROOM_RESERVATION_CHECK_PERIOD = 5 * time.Minute
+	PRIVATE_ROOM_MAX_SHARE_PERCENT        = 50 // Of "Conf.Room.MaxRoomCount", such that reservations never lock public play out
+)
+
+var (
+	privateRoomsByInviteCode   = make(map[string]*Room) // Only accessed with "RoomHeapMux" locked
+	privateRoomsByHostPlayerId = make(map[int32]*Room)  // Only accessed with "RoomHeapMux" locked
+)
+
+/*
+Reserves an empty IDLE room for "hostPlayerId" and returns it along with a newly generated invite code. A reserved room is taken out of "RoomHeapManagerIns" such that "RoomHeap.Pop" never hands it out, and only players carrying its invite code are addable.
+
+A room is created by "params" unless it's nil or the same as "DefaultRoomParams", such a customized room is destroyed instead of being put back into "RoomHeapManagerIns" upon release.
+
+The reservation is released upon dismissal, or at a periodic check if the room is empty by then. Each host holds at most one reservation at a time, and no more than "PRIVATE_ROOM_MAX_SHARE_PERCENT" of "Conf.Room.MaxRoomCount" rooms are reserved in total.
+*/
+func ReservePrivateRoom(hostPlayerId int32, params *RoomParams) (*Room, string, error) {
+	(*RoomHeapMux).Lock()
+	defer (*RoomHeapMux).Unlock()
+	if err := checkPrivateRoomReservable(hostPlayerId, Conf.Room.MaxRoomCount); nil != err {
+		return nil, "", err
+	}
+	var pR *Room = nil
+	if nil == params || *DefaultRoomParams() == *params {
+		pR = takeIdleRoom()
+		if nil == pR {
+			return nil, "", fmt.Errorf("No idle room to reserve")
+		}
+	} else {
+		var err error
+		pR, err = CreateRoom(params)
+		if nil != err {
+			return nil, "", err
+		}
+	}
+	inviteCode := ""
+	for "" == inviteCode {
+		candidate, err := newInviteCode()
+		if nil != err {
+			return nil, "", err
+		}
+		if _, existent := privateRoomsByInviteCode[candidate]; !existent {
+			inviteCode = candidate
+		}
+	}
+	RoomHeapManagerIns.remove(pR)
+	pR.inviteCode = inviteCode
+	pR.hostPlayerId = hostPlayerId
+	privateRoomsByInviteCode[inviteCode] = pR
+	privateRoomsByHostPlayerId[hostPlayerId] = pR
+	time.AfterFunc(PRIVATE_ROOM_RESERVATION_CHECK_PERIOD, func() {
+		checkPrivateRoomReservation(pR, inviteCode)
+	})
+	Logger.Info("A private room is reserved:", zap.Any("roomId", pR.Id), zap.Any("hostPlayerId", hostPlayerId), zap.Any("inviteCode", inviteCode))
+	return pR, inviteCode, nil
+}
+
+// The caller MUST have "RoomHeapMux" locked.
+func checkPrivateRoomReservable(hostPlayerId int32, maxRoomCount int) error {
+	if pR, existent := privateRoomsByHostPlayerId[hostPlayerId]; existent {
+		return fmt.Errorf("Player %v is already hosting the private roomId=%v", hostPlayerId, pR.Id)
+	}
+	if maxPrivateRoomCount := maxRoomCount * PRIVATE_ROOM_MAX_SHARE_PERCENT / 100; maxPrivateRoomCount <= len(privateRoomsByInviteCode) {
+		return fmt.Errorf("Private room count=%v has reached the limit", len(privateRoomsByInviteCode))
+	}
+	return nil
+}
+
+// Case-insensitive, returns nil if no room is reserved by "inviteCode". The caller MUST have "RoomHeapMux" locked.
+func GetPrivateRoomByInviteCode(inviteCode string) *Room {
+	if pR, existent := privateRoomsByInviteCode[strings.ToUpper(inviteCode)]; existent {
+		return pR
+	}
+	return nil
+}
+
+// Whether a player carrying "inviteCode" is addable, i.e. "pR" is public or reserved by the same code.
+func (pR *Room) acceptsInviteCode(inviteCode string) bool {
+	return "" == pR.inviteCode || pR.inviteCode == strings.ToUpper(inviteCode)
+}
+
+func checkPrivateRoomReservation(pR *Room, inviteCode string) {
+	(*RoomHeapMux).Lock()
+	defer (*RoomHeapMux).Unlock()
+	if inviteCode != pR.inviteCode {
+		// Already released
+		return
+	}
+	if RoomBattleStateIns.IDLE == pR.State && 0 == pR.EffectivePlayerCount {
+		releasePrivateRoom(pR)
+		return
+	}
+	time.AfterFunc(PRIVATE_ROOM_RESERVATION_CHECK_PERIOD, func() {
+		checkPrivateRoomReservation(pR, inviteCode)
+	})
+}
+
+// Puts "pR" back into "RoomHeapManagerIns" as a public room. The caller MUST have "RoomHeapMux" locked.
+func releasePrivateRoom(pR *Room) {
+	if "" == pR.inviteCode {
+		return
+	}
+	Logger.Info("A private room is released:", zap.Any("roomId", pR.Id), zap.Any("inviteCode", pR.inviteCode))
+	delete(privateRoomsByInviteCode, pR.inviteCode)
+	if hosted, existent := privateRoomsByHostPlayerId[pR.hostPlayerId]; existent && hosted == pR {
+		delete(privateRoomsByHostPlayerId, pR.hostPlayerId)
+	}
+	pR.inviteCode = ""
+	pR.hostPlayerId = 0
+	if _, existent := (*RoomMapManagerIns)[pR.Id]; !existent {
+		return
+	}
+	if *DefaultRoomParams() != pR.Params {
+		delete(*RoomMapManagerIns, pR.Id)
+		Logger.Info("A customized room is destroyed:", zap.Any("roomId", pR.Id), zap.Any("roomCount", len(*RoomMapManagerIns)))
+		return
+	}
+	heap.Push(RoomHeapManagerIns, pR)
+}
+
+func newInviteCode() (string, error) {
+	var sb strings.Builder
+	alphabetSize := big.NewInt(int64(len(PRIVATE_ROOM_INVITE_CODE_ALPHABET)))
+	for i := 0; i < PRIVATE_ROOM_INVITE_CODE_LEN; i++ {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if nil != err {
+			return "", err
+		}
+		sb.WriteByte(PRIVATE_ROOM_INVITE_CODE_ALPHABET[n.Int64()])
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPrivateRoomOnlyAcceptsItsInviteCode(t *testing.T) {
+	inviteCode, err := newInviteCode()
+	if nil != err {
+		t.Fatal(err)
+	}
+	if PRIVATE_ROOM_INVITE_CODE_LEN != len(inviteCode) || strings.ContainsAny(inviteCode, "IO01") {
+		t.Fatalf("Invalid invite code %v", inviteCode)
+	}
+
+	pR := &Room{}
+	if !pR.acceptsInviteCode("") || !pR.acceptsInviteCode(inviteCode) {
+		t.Fatalf("A public room should accept any player")
+	}
+	pR.inviteCode = inviteCode
+	if pR.acceptsInviteCode("") || pR.acceptsInviteCode("ZZZZZZ"+inviteCode) {
+		t.Fatalf("A private room shouldn't accept a player without its invite code")
+	}
+	if !pR.acceptsInviteCode(strings.ToLower(inviteCode)) {
+		t.Fatalf("A private room should accept its invite code case-insensitively")
+	}
+}
+
+func TestPrivateRoomReservationsAreLimited(t *testing.T) {
+	roomMap := make(RoomMap)
+	RoomMapManagerIns = &roomMap
+	defer func() {
+		RoomMapManagerIns = nil
+		privateRoomsByInviteCode = make(map[string]*Room)
+		privateRoomsByHostPlayerId = make(map[int32]*Room)
+	}()
+	pR := &Room{Id: 1, inviteCode: "ABCDEF", hostPlayerId: 10}
+	privateRoomsByInviteCode[pR.inviteCode] = pR
+	privateRoomsByHostPlayerId[pR.hostPlayerId] = pR
+
+	if err := checkPrivateRoomReservable(10, 8); nil == err {
+		t.Fatalf("A host shouldn't reserve a second private room")
+	}
+	if err := checkPrivateRoomReservable(20, 8); nil != err {
+		t.Fatalf("Another host should reserve a private room, got %v", err)
+	}
+	if err := checkPrivateRoomReservable(20, 2); nil == err {
+		t.Fatalf("Private rooms shouldn't exceed %v%% of maxRoomCount", PRIVATE_ROOM_MAX_SHARE_PERCENT)
+	}
+
+	releasePrivateRoom(pR)
+	if err := checkPrivateRoomReservable(10, 8); nil != err {
+		t.Fatalf("The host should reserve again after its private room is released, got %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package models
+
+import (
+	. "battle_srv/common"
+	"battle_srv/common/utils"
+	. "battle_srv/protos"
+	. "dnmshared"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+/*
+Called once all players of a full room have acked the "BattleColliderInfo". Unless "Params.ReadyCheckSeconds" is 0, the room enters READY_CHECK where every player upsyncs "UPSYNC_MSG_ACT_PLAYER_READY", and the battle starts only when all are ready.
+
+Each ready-state change, including the start and the failure of a ready-check, is downsynced as "DOWNSYNC_MSG_ACT_PLAYER_READY_STATE_CHANGED" whose "CountdownNanos" is the remaining time of the ready-check.
+*/
+func (pR *Room) startReadyCheckOrBattle() {
+	if 0 >= pR.Params.ReadyCheckSeconds {
+		pR.StartBattle()
+		return
+	}
+	pR.readyCheckMux.Lock()
+	if RoomBattleStateIns.WAITING != pR.State {
+		pR.readyCheckMux.Unlock()
+		return
+	}
+	pR.State = RoomBattleStateIns.READY_CHECK
+	pR.readyCheckEpoch++
+	epoch := pR.readyCheckEpoch
+	readyCheckNanos := int64(pR.Params.ReadyCheckSeconds) * int64(time.Second)
+	pR.readyCheckDeadlineNanos = utils.UnixtimeNano() + readyCheckNanos
+	for _, player := range pR.Players {
+		player.Ready = false
+	}
+	pR.readyCheckMux.Unlock()
+
+	Logger.Info("Ready-check started for:", zap.Any("roomId", pR.Id), zap.Any("readyCheckSeconds", pR.Params.ReadyCheckSeconds))
+	pR.broadcastReadyStates()
+	time.AfterFunc(time.Duration(readyCheckNanos), func() {
+		pR.onReadyCheckTimeout(epoch)
+	})
+}
+
+func (pR *Room) OnPlayerReady(playerId int32) {
+	pR.readyCheckMux.Lock()
+	if RoomBattleStateIns.READY_CHECK != pR.State {
+		pR.readyCheckMux.Unlock()
+		return
+	}
+	player, existent := pR.Players[playerId]
+	if !existent || player.Ready {
+		pR.readyCheckMux.Unlock()
+		return
+	}
+	player.Ready = true
+	allReady := true
+	for _, p := range pR.Players {
+		if !p.Ready {
+			allReady = false
+			break
+		}
+	}
+	if allReady {
+		// Invalidates the pending "onReadyCheckTimeout"
+		pR.readyCheckEpoch++
+	}
+	pR.readyCheckMux.Unlock()
+
+	pR.broadcastReadyStates()
+	if allReady {
+		Logger.Info("All players are ready for:", zap.Any("roomId", pR.Id))
+		pR.StartBattle()
+	}
+}
+
+/*
+The room goes back to WAITING and unready players are expelled by "Constants.RetCode.ReadyCheckFailed", upon which the frontend is expected to requeue without "boundRoomId". A room reserved for a matchmaking group expels all players instead, because nobody else could fill it.
+
+[WARNING] Running on the timer goroutine while each expelled player is removed from "pR.Players" by "OnPlayerDisconnected" at WAITING. Therefore both the players to expel and the ready states to broadcast are taken from a snapshot with "RoomHeapMux" locked, i.e. the guard of "AddPlayerIfPossible" on the ws path, and the broadcast goes out before any expelling.
+*/
+func (pR *Room) onReadyCheckTimeout(epoch int32) {
+	pR.readyCheckMux.Lock()
+	if RoomBattleStateIns.READY_CHECK != pR.State || epoch != pR.readyCheckEpoch {
+		pR.readyCheckMux.Unlock()
+		return
+	}
+	pR.State = RoomBattleStateIns.WAITING
+	pR.readyCheckEpoch++
+	readyPlayerIds := make(map[int32]bool, len(pR.Players))
+	for playerId, player := range pR.Players {
+		if player.Ready {
+			readyPlayerIds[playerId] = true
+		}
+		player.Ready = false
+	}
+	pR.readyCheckMux.Unlock()
+
+	(*RoomHeapMux).Lock()
+	matchmade := (nil != pR.matchedPlayerIds)
+	releaseMatchmadeRoom(pR)
+	snapshot := toPbPlayers(pR.Players, true)
+	toExpelIds := make([]int32, 0, len(snapshot))
+	toExpel := make([]SignalToCloseConnCbType, 0, len(snapshot))
+	for playerId, _ := range snapshot {
+		if readyPlayerIds[playerId] && !matchmade {
+			continue
+		}
+		if signalToClose, existent := pR.PlayerSignalToCloseDict[playerId]; existent && nil != signalToClose {
+			toExpelIds = append(toExpelIds, playerId)
+			toExpel = append(toExpel, signalToClose)
+		}
+	}
+	(*RoomHeapMux).Unlock()
+
+	Logger.Info("Ready-check failed for:", zap.Any("roomId", pR.Id), zap.Any("readyPlayerIds", readyPlayerIds), zap.Any("matchmade", matchmade), zap.Any("toExpelIds", toExpelIds))
+	pR.broadcastReadyStatesSnapshot(snapshot)
+	for _, signalToClose := range toExpel {
+		// Triggers "OnPlayerDisconnected" at WAITING, thus removing the player from the room
+		signalToClose(Constants.RetCode.ReadyCheckFailed, fmt.Sprintf("Not ready in %v seconds for roomId == %v", pR.Params.ReadyCheckSeconds, pR.Id))
+	}
+}
+
+// Returns true if "pR" was in READY_CHECK, which is then aborted back to WAITING, e.g. when a player disconnects.
+func (pR *Room) abortReadyCheck() bool {
+	pR.readyCheckMux.Lock()
+	defer pR.readyCheckMux.Unlock()
+	if RoomBattleStateIns.READY_CHECK != pR.State {
+		return false
+	}
+	pR.State = RoomBattleStateIns.WAITING
+	pR.readyCheckEpoch++
+	for _, player := range pR.Players {
+		player.Ready = false
+	}
+	return true
+}
+
+func (pR *Room) broadcastReadyStates() {
+	pR.broadcastReadyStatesSnapshot(toPbPlayers(pR.Players, true))
+}
+
+// Sends the ready states in "players", a snapshot taken by the caller, to each of them.
+func (pR *Room) broadcastReadyStatesSnapshot(players map[int32]*PlayerDownsync) {
+	countdownNanos := int64(0)
+	if RoomBattleStateIns.READY_CHECK == pR.State {
+		countdownNanos = pR.readyCheckDeadlineNanos - utils.UnixtimeNano()
+	}
+	readyStatesFrame := &RoomDownsyncFrame{
+		Id:             pR.RenderFrameId,
+		Players:        players,
+		CountdownNanos: countdownNanos,
+	}
+	for playerId, _ := range players {
+		pR.sendSafely(readyStatesFrame, nil, DOWNSYNC_MSG_ACT_PLAYER_READY_STATE_CHANGED, playerId)
+	}
+}
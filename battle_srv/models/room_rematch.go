@@ -0,0 +1,166 @@
+package models
+
+import (
+	"battle_srv/battle"
+	. "battle_srv/common"
+	"battle_srv/common/utils"
+	. "battle_srv/protos"
+	. "dnmshared"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+/*
+Called upon "onSettlementCompleted". Unless "Params.RematchWindowSeconds" is 0, the room stays in IN_SETTLEMENT with a rematch window where every player may upsync "UPSYNC_MSG_ACT_REMATCH_VOTE". A unanimous vote brings the room back to WAITING with the same participants without dropping any websocket session, otherwise the room is dismissed when the window elapses.
+
+Each vote-state change, including the opening and the closing of the window, is downsynced as "DOWNSYNC_MSG_ACT_REMATCH_VOTE_STATE_CHANGED" whose "Players" carry the results of the settled battle, and whose "CountdownNanos" is the remaining time of the window, 0 once closed.
+*/
+func (pR *Room) openRematchWindowOrDismiss() {
+	if 0 >= pR.Params.RematchWindowSeconds || 0 == len(pR.PlayerDownsyncSessionDict) {
+		pR.Dismiss()
+		return
+	}
+	pR.rematchMux.Lock()
+	if RoomBattleStateIns.IN_SETTLEMENT != pR.State {
+		pR.rematchMux.Unlock()
+		return
+	}
+	pR.rematchEpoch++
+	epoch := pR.rematchEpoch
+	rematchWindowNanos := int64(pR.Params.RematchWindowSeconds) * int64(time.Second)
+	pR.rematchDeadlineNanos = utils.UnixtimeNano() + rematchWindowNanos
+	for _, player := range pR.Players {
+		player.RematchVoted = false
+	}
+	pR.rematchMux.Unlock()
+
+	Logger.Info("Rematch window opened for:", zap.Any("roomId", pR.Id), zap.Any("rematchWindowSeconds", pR.Params.RematchWindowSeconds))
+	pR.expelAllSpectators()
+	pR.broadcastRematchVoteStates()
+	time.AfterFunc(time.Duration(rematchWindowNanos), func() {
+		pR.onRematchWindowTimeout(epoch)
+	})
+}
+
+func (pR *Room) OnPlayerRematchVote(playerId int32) {
+	pR.rematchMux.Lock()
+	if RoomBattleStateIns.IN_SETTLEMENT != pR.State || 0 == pR.rematchDeadlineNanos {
+		pR.rematchMux.Unlock()
+		return
+	}
+	player, existent := pR.Players[playerId]
+	if !existent || PlayerBattleStateIns.ACTIVE != player.BattleState || player.RematchVoted {
+		pR.rematchMux.Unlock()
+		return
+	}
+	player.RematchVoted = true
+	allVoted := true
+	for _, p := range pR.Players {
+		// A voter who has since disconnected can't be brought back by the rematch, thus no longer counted as voted
+		if PlayerBattleStateIns.ACTIVE != p.BattleState || !p.RematchVoted {
+			allVoted = false
+			break
+		}
+	}
+	if allVoted {
+		// Invalidates the pending "onRematchWindowTimeout"
+		pR.rematchEpoch++
+		pR.rematchDeadlineNanos = 0
+	}
+	pR.rematchMux.Unlock()
+
+	pR.broadcastRematchVoteStates()
+	if allVoted {
+		pR.rematch()
+	}
+}
+
+func (pR *Room) onRematchWindowTimeout(epoch int32) {
+	pR.rematchMux.Lock()
+	if RoomBattleStateIns.IN_SETTLEMENT != pR.State || epoch != pR.rematchEpoch {
+		pR.rematchMux.Unlock()
+		return
+	}
+	pR.rematchEpoch++
+	pR.rematchDeadlineNanos = 0
+	pR.rematchMux.Unlock()
+
+	Logger.Info("Rematch window closed without a unanimous vote for:", zap.Any("roomId", pR.Id))
+	pR.broadcastRematchVoteStates()
+	pR.Dismiss()
+}
+
+/*
+Refreshes the frames and colliders like "OnDismissed" but keeps the participants, each of whom is then sent a new "BattleColliderInfo" by "DOWNSYNC_MSG_ACT_HB_REQ" exactly as upon being added, such that the battle starts by the same acking flow.
+
+The room is dismissed instead if any participant disconnected right after the unanimous vote, because a player is never re-added to a room in WAITING.
+*/
+func (pR *Room) rematch() {
+	for playerId, _ := range pR.Players {
+		if _, existent := pR.PlayerDownsyncSessionDict[playerId]; !existent {
+			Logger.Info("Rematch aborted due to a disconnected player for:", zap.Any("roomId", pR.Id), zap.Any("playerId", playerId))
+			pR.Dismiss()
+			return
+		}
+	}
+	Logger.Info("Rematch agreed for:", zap.Any("roomId", pR.Id), zap.Any("playerCount", len(pR.Players)))
+	pR.refreshBattleStates()
+	for _, player := range pR.Players {
+		player.AckingFrameId = -1
+		player.AckingInputFrameId = -1
+		player.LastSentInputFrameId = MAGIC_LAST_SENT_INPUT_FRAME_ID_NORMAL_ADDED
+		player.ChecksumMismatchCount = 0
+		player.ShouldResyncDueToChecksumMismatch = false
+		player.BattleState = PlayerBattleStateIns.ADDED_PENDING_BATTLE_COLLIDER_ACK
+		player.Ready = false
+		player.RematchVoted = false
+		player.Speed = pR.PlayerDefaultSpeed
+		player.Hp = player.MaxHp
+		player.CharacterState = battle.ATK_CHARACTER_STATE_IDLE1
+		pR.placePlayerAtStartingPosition(player)
+	}
+
+	// [WARNING] It's deliberately ordered such that "pR.State = RoomBattleStateIns.WAITING" is put AFTER all the refreshing operations above.
+	pR.State = RoomBattleStateIns.WAITING
+	pR.updateScore()
+
+	bciFrame := pR.BuildBattleColliderInfo(PlayerBattleStateIns.ADDED_PENDING_BATTLE_COLLIDER_ACK)
+	for playerId, _ := range pR.Players {
+		pR.sendBattleColliderInfoSafely(bciFrame, playerId)
+	}
+}
+
+func (pR *Room) sendBattleColliderInfoSafely(bciFrame *BattleColliderInfo, playerId int32) {
+	defer func() {
+		if r := recover(); r != nil {
+			if signalToClose, existent := pR.PlayerSignalToCloseDict[playerId]; existent && nil != signalToClose {
+				signalToClose(Constants.RetCode.UnknownError, fmt.Sprintf("%v", r))
+			}
+		}
+	}()
+	pR.sendRespSafely(&WsResp{
+		Ret:      int32(Constants.RetCode.Ok),
+		Act:      DOWNSYNC_MSG_ACT_HB_REQ,
+		BciFrame: bciFrame,
+	}, playerId)
+}
+
+func (pR *Room) broadcastRematchVoteStates() {
+	countdownNanos := int64(0)
+	if deadline := pR.rematchDeadlineNanos; 0 != deadline {
+		countdownNanos = deadline - utils.UnixtimeNano()
+	}
+	for playerId, _ := range pR.Players {
+		if _, existent := pR.PlayerDownsyncSessionDict[playerId]; !existent {
+			continue
+		}
+		voteStatesFrame := &RoomDownsyncFrame{
+			Id:             pR.RenderFrameId,
+			Players:        toPbPlayers(pR.Players, true),
+			CountdownNanos: countdownNanos,
+		}
+		pR.sendSafely(voteStatesFrame, nil, DOWNSYNC_MSG_ACT_REMATCH_VOTE_STATE_CHANGED, playerId)
+	}
+}
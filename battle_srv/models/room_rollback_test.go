@@ -0,0 +1,276 @@
+package models
+
+import (
+	"battle_srv/battle"
+	. "battle_srv/protos"
+	"bytes"
+	. "dnmshared/sharedprotos"
+	"github.com/golang/protobuf/proto"
+	"math"
+	"sync"
+	"testing"
+)
+
+func newTestRollbackRoom() *Room {
+	pR := &Room{
+		Capacity:   2,
+		Players:    make(map[int32]*Player),
+		PlayersArr: make([]*Player, 2),
+	}
+	for joinIndex := int32(1); joinIndex <= 2; joinIndex++ {
+		player := &Player{}
+		player.Id, player.JoinIndex = 10*joinIndex, joinIndex
+		pR.Players[player.Id] = player
+	}
+	pR.StageDiscreteW, pR.StageDiscreteH, pR.StageTileW, pR.StageTileH = 64, 64, 16, 16
+	pR.StrToPolygon2DListMap = map[string]*Polygon2DList{
+		"Barrier": &Polygon2DList{Eles: []*Polygon2D{
+			&Polygon2D{
+				Anchor: &Vec2D{X: 100, Y: -50},
+				Points: []*Vec2D{&Vec2D{X: 0, Y: 0}, &Vec2D{X: 32, Y: 0}, &Vec2D{X: 32, Y: 100}, &Vec2D{X: 0, Y: 100}},
+			},
+		}},
+	}
+	pR.InputDelayFrames = 4
+	pR.InputScaleFrames = 2
+	pR.RollbackEstimatedDtNanos = 16666666
+	pR.WorldToVirtualGridRatio = 1000
+	pR.VirtualGridToWorldRatio = 0.001
+	pR.PlayerDefaultSpeed = 2000
+	pR.BackendRollbackEnabled = true
+	pR.RenderFrameBuffer = NewRingBuffer(64)
+	pR.InputsBuffer = NewRingBuffer(32)
+	pR.DiscreteInputsBuffer = sync.Map{}
+	pR.LastAllConfirmedInputFrameId = -1
+	pR.LastAllConfirmedInputFrameIdWithChange = -1
+	pR.LastAllConfirmedInputList = make([]uint64, pR.Capacity)
+	pR.pendingRollbackRenderFrameId = math.MaxInt32
+	pR.refreshColliders()
+
+	pR.RenderFrameBuffer.Put(&RoomDownsyncFrame{
+		Id: 0,
+		Players: map[int32]*PlayerDownsync{
+			10: &PlayerDownsync{Id: 10, JoinIndex: 1, VirtualGridX: 0, VirtualGridY: 0, DirX: 2, Speed: 2000, ColliderRadius: 16, Hp: 100, MaxHp: 100},
+			20: &PlayerDownsync{Id: 20, JoinIndex: 2, VirtualGridX: 40000, VirtualGridY: 0, DirX: -2, Speed: 2000, ColliderRadius: 16, Hp: 100, MaxHp: 100},
+		},
+	})
+	for inputFrameId := int32(0); inputFrameId < 8; inputFrameId++ {
+		pR.prefabInputFrameDownsync(inputFrameId)
+	}
+	return pR
+}
+
+func (pR *Room) upsyncForTest(inputFrameId int32, joinIndex int32, encoded uint64) {
+	pR.DiscreteInputsBuffer.Store(pR.toDiscreteInputsBufferIndex(inputFrameId, joinIndex), &InputFrameUpsync{
+		InputFrameId: inputFrameId,
+		Encoded:      encoded,
+	})
+}
+
+func TestMispredictedRenderFramesAreReplacedUponRollback(t *testing.T) {
+	const renderFrameCount = int32(24)
+	const mispredictedInputFrameId = int32(2)
+	const encoded = uint64(3)
+
+	// All inputs are predicted as idle
+	pR := newTestRollbackRoom()
+	pR.applyInputFrameDownsyncDynamics(0, renderFrameCount)
+	if renderFrameCount != pR.CurDynamicsRenderFrameId {
+		t.Fatalf("Predicted renderFrames should be calculated up to %v, got %v", renderFrameCount, pR.CurDynamicsRenderFrameId)
+	}
+	predicted := make([]*RoomDownsyncFrame, renderFrameCount+1)
+	for renderFrameId := int32(0); renderFrameId <= renderFrameCount; renderFrameId++ {
+		predicted[renderFrameId] = pR.RenderFrameBuffer.GetByFrameId(renderFrameId).(*RoomDownsyncFrame)
+	}
+
+	// The upsync tells that player#1 actually moved since "mispredictedInputFrameId"
+	pR.upsyncForTest(mispredictedInputFrameId, 1, encoded)
+	pR.markConfirmationIfApplicable()
+	rollbackRenderFrameId := pR.ConvertToFirstUsedRenderFrameId(mispredictedInputFrameId)
+	if rollbackRenderFrameId != pR.pendingRollbackRenderFrameId {
+		t.Fatalf("Expected a pending rollback to renderFrameId=%v, got %v", rollbackRenderFrameId, pR.pendingRollbackRenderFrameId)
+	}
+	pR.rollbackIfApplicable()
+	pR.applyInputFrameDownsyncDynamics(pR.CurDynamicsRenderFrameId, renderFrameCount)
+
+	// The same inputs known beforehand
+	pExpected := newTestRollbackRoom()
+	for inputFrameId := mispredictedInputFrameId; inputFrameId < pExpected.InputsBuffer.EdFrameId; inputFrameId++ {
+		pExpected.InputsBuffer.GetByFrameId(inputFrameId).(*InputFrameDownsync).InputList[0] = encoded
+	}
+	pExpected.applyInputFrameDownsyncDynamics(0, renderFrameCount)
+
+	for renderFrameId := int32(0); renderFrameId <= renderFrameCount; renderFrameId++ {
+		actual := pR.RenderFrameBuffer.GetByFrameId(renderFrameId).(*RoomDownsyncFrame)
+		if renderFrameId <= rollbackRenderFrameId && actual != predicted[renderFrameId] {
+			t.Fatalf("renderFrameId=%v before the mispredicted input shouldn't be recalculated", renderFrameId)
+		}
+		if renderFrameId > rollbackRenderFrameId && proto.Equal(actual, predicted[renderFrameId]) {
+			t.Fatalf("renderFrameId=%v should be replaced after rollback", renderFrameId)
+		}
+		expected := pExpected.RenderFrameBuffer.GetByFrameId(renderFrameId).(*RoomDownsyncFrame)
+		if !proto.Equal(expected, actual) {
+			t.Fatalf("renderFrameId=%v differs after rollback:\nexpected=%v\nactual=%v", renderFrameId, expected, actual)
+		}
+	}
+	if moved := pR.RenderFrameBuffer.GetByFrameId(renderFrameCount).(*RoomDownsyncFrame).Players[10]; battle.ATK_CHARACTER_STATE_WALKING != moved.CharacterState {
+		t.Fatalf("Player#1 should be walking by the rolled back input, got characterState=%v", moved.CharacterState)
+	}
+}
+
+func TestKnockedOutPlayerRespawnsUnlessEliminated(t *testing.T) {
+	const renderFrameCount = int32(12)
+	for _, koRespawnFrames := range []int32{0, 4} {
+		pR := newTestRollbackRoom()
+		pR.KoRespawnFrames = koRespawnFrames
+		pR.StrToVec2DListMap = map[string]*Vec2DList{
+			"PlayerStartingPos": &Vec2DList{Eles: []*Vec2D{&Vec2D{X: -100, Y: 0}, &Vec2D{X: 200, Y: 0}}},
+		}
+		pR.refreshColliders()
+		knockedOut := pR.RenderFrameBuffer.GetByFrameId(0).(*RoomDownsyncFrame).Players[20]
+		knockedOut.Hp, knockedOut.CharacterState, knockedOut.FramesToRecover = 0, battle.ATK_CHARACTER_STATE_KO, koRespawnFrames
+
+		for inputFrameId := int32(0); inputFrameId < 4; inputFrameId++ {
+			pR.upsyncForTest(inputFrameId, 1, 0)
+			pR.upsyncForTest(inputFrameId, 2, 0)
+		}
+		pR.markConfirmationIfApplicable()
+		pR.applyInputFrameDownsyncDynamics(0, renderFrameCount)
+		if renderFrameCount != pR.lastSettledRenderFrameId() {
+			t.Fatalf("Expected renderFrames up to %v to be settled, got %v", renderFrameCount, pR.lastSettledRenderFrameId())
+		}
+
+		respawned := pR.RenderFrameBuffer.GetByFrameId(renderFrameCount).(*RoomDownsyncFrame).Players[20]
+		if 0 == koRespawnFrames {
+			if battle.ATK_CHARACTER_STATE_KO != respawned.CharacterState || nil == pR.battleDecidedByKo() {
+				t.Fatalf("Expected playerId=20 to be eliminated and the battle decided, got %v", respawned)
+			}
+			continue
+		}
+		if nil != pR.battleDecidedByKo() {
+			t.Fatalf("Expected the battle not decided by knock-outs with koRespawnFrames=%v", koRespawnFrames)
+		}
+		if battle.ATK_CHARACTER_STATE_KO == respawned.CharacterState || respawned.MaxHp != respawned.Hp || 200000 != respawned.VirtualGridX {
+			t.Fatalf("Expected playerId=20 to respawn with full Hp at its starting position, got %v", respawned)
+		}
+	}
+}
+
+func TestReplayReproducesRenderFramesOfRoom(t *testing.T) {
+	const inputFrameCount = int32(8)
+	pR := newTestRollbackRoom()
+	pR.ReplayRecordingEnabled = true
+	pR.startReplayRecording(proto.Clone(&pR.BattleColliderInfo).(*BattleColliderInfo), pR.RenderFrameBuffer.GetByFrameId(0).(*RoomDownsyncFrame))
+	encodedList := [][]uint64{{3, 4}, {19, 4}, {0, 20}, {4, 3}, {20, 0}, {1, 2}, {2, 1}, {3, 19}}
+	for inputFrameId := int32(0); inputFrameId < inputFrameCount; inputFrameId++ {
+		pR.upsyncForTest(inputFrameId, 1, encodedList[inputFrameId][0])
+		pR.upsyncForTest(inputFrameId, 2, encodedList[inputFrameId][1])
+		pR.markConfirmationIfApplicable()
+		pR.recordAllConfirmedInputFrames()
+	}
+	lastRenderFrameId := pR.ConvertToLastUsedRenderFrameId(inputFrameCount - 1)
+	pR.applyInputFrameDownsyncDynamics(0, lastRenderFrameId)
+
+	var buf bytes.Buffer
+	if err := battle.WriteReplay(&buf, pR.replayHeader, pR.replayInputFrames); nil != err {
+		t.Fatal(err)
+	}
+	header, inputFrames, err := battle.ReadReplay(&buf)
+	if nil != err {
+		t.Fatal(err)
+	}
+	replayed := battle.ReplayRenderFrames(header, inputFrames)
+	if int(lastRenderFrameId)+1 != len(replayed) {
+		t.Fatalf("Expected %v replayed renderFrames, got %v", lastRenderFrameId+1, len(replayed))
+	}
+	for renderFrameId := int32(0); renderFrameId <= lastRenderFrameId; renderFrameId++ {
+		expected := pR.RenderFrameBuffer.GetByFrameId(renderFrameId).(*RoomDownsyncFrame)
+		if battle.Checksum(expected) != battle.Checksum(replayed[renderFrameId]) || !proto.Equal(expected, replayed[renderFrameId]) {
+			t.Fatalf("renderFrameId=%v differs from that of the room:\nexpected=%v\nactual=%v", renderFrameId, expected, replayed[renderFrameId])
+		}
+	}
+
+	// A replay exceeding "maxReplayInputFrameCount" is dropped
+	pR = newTestRollbackRoom()
+	pR.ReplayRecordingEnabled = true
+	pR.maxReplayInputFrameCount = inputFrameCount - 1
+	pR.startReplayRecording(proto.Clone(&pR.BattleColliderInfo).(*BattleColliderInfo), pR.RenderFrameBuffer.GetByFrameId(0).(*RoomDownsyncFrame))
+	for inputFrameId := int32(0); inputFrameId < inputFrameCount; inputFrameId++ {
+		pR.upsyncForTest(inputFrameId, 1, 0)
+		pR.upsyncForTest(inputFrameId, 2, 0)
+		pR.markConfirmationIfApplicable()
+		pR.recordAllConfirmedInputFrames()
+	}
+	if nil != pR.replayHeader || nil != pR.replayInputFrames {
+		t.Fatalf("Expected the replay to be dropped beyond maxReplayInputFrameCount=%v", pR.maxReplayInputFrameCount)
+	}
+}
+
+func TestSettledRenderFrameIgnoresPredictedOutcome(t *testing.T) {
+	const renderFrameCount = int32(24)
+	pR := newTestRollbackRoom()
+	pR.BackendDynamicsEnabled = true
+	pR.MeleeSkillConfig = map[int32]*MeleeBullet{
+		1: &MeleeBullet{
+			StartupFrames:      2,
+			ActiveFrames:       3,
+			RecoveryFrames:     10,
+			HitboxOffset:       24,
+			HitboxSize:         &Vec2D{X: 45, Y: 32},
+			HitStunFrames:      6,
+			Pushback:           11,
+			Damage:             10,
+			ReleaseTriggerType: battle.SKILL_RELEASE_TRIGGER_TYPE_RISING_EDGE,
+		},
+	}
+	pR.CharacterSkillBindings = map[int32]*CharacterSkillBinding{
+		0: &CharacterSkillBinding{BtnToSkillId: map[string]int32{battle.BTN_A: 1}},
+	}
+	pR.refreshColliders()
+	pR.RenderFrameBuffer.GetByFrameId(0).(*RoomDownsyncFrame).Players[20].VirtualGridX = 30000
+
+	// Player#1 lags behind, thus predicted as idle since inputFrameId=1
+	for inputFrameId := int32(0); inputFrameId < 8; inputFrameId++ {
+		pR.upsyncForTest(inputFrameId, 2, 0)
+	}
+	pR.upsyncForTest(0, 1, 0)
+	pR.markConfirmationIfApplicable()
+	pR.applyInputFrameDownsyncDynamics(0, renderFrameCount)
+	settledRdf := pR.settledRenderFrame()
+	if nil == settledRdf || pR.lastSettledRenderFrameId() != settledRdf.Id || renderFrameCount <= settledRdf.Id {
+		t.Fatalf("Expected the settled renderFrame to exclude predicted ones up to %v, got %v", renderFrameCount, settledRdf)
+	}
+	predictedWinningTeamId := battle.WinningTeamIdOf(pR.RenderFrameBuffer.GetByFrameId(renderFrameCount).(*RoomDownsyncFrame))
+
+	// The late upsync tells that player#1 actually attacked at inputFrameId=1
+	pR.upsyncForTest(1, 1, 1<<battle.INPUT_BTN_A_SHIFT)
+	for inputFrameId := int32(2); inputFrameId < 8; inputFrameId++ {
+		pR.upsyncForTest(inputFrameId, 1, 0)
+	}
+	pR.markConfirmationIfApplicable()
+	pR.rollbackIfApplicable()
+	pR.applyInputFrameDownsyncDynamics(pR.CurDynamicsRenderFrameId, renderFrameCount)
+	settledRdf = pR.settledRenderFrame()
+	expectedWinningTeamId := battle.TeamIdOf(1, 0)
+	if winningTeamId := battle.WinningTeamIdOf(settledRdf); expectedWinningTeamId != winningTeamId || predictedWinningTeamId == winningTeamId {
+		t.Fatalf("Expected the late upsync to change the winner from %v to %v, got %v with settledRdf=%v", predictedWinningTeamId, expectedWinningTeamId, winningTeamId, settledRdf)
+	}
+}
+
+func TestPredictedInputCarriesHeldButtonsForward(t *testing.T) {
+	const held = uint64(3) | (1 << battle.INPUT_BTN_A_SHIFT) | (1 << battle.INPUT_GUARD_SHIFT)
+	pR := newTestRollbackRoom()
+	pR.InputsBuffer.GetByFrameId(pR.InputsBuffer.EdFrameId - 1).(*InputFrameDownsync).InputList[0] = held
+	// Otherwise a released BtnA would be predicted, i.e. a phantom falling edge
+	if predicted := pR.prefabInputFrameDownsync(pR.InputsBuffer.EdFrameId).InputList[0]; held != predicted {
+		t.Fatalf("Expected the prefab to predict %v, got %v", held, predicted)
+	}
+
+	pR.upsyncForTest(0, 1, held)
+	pR.markConfirmationIfApplicable()
+	for inputFrameId := int32(1); inputFrameId < pR.InputsBuffer.EdFrameId; inputFrameId++ {
+		if predicted := pR.InputsBuffer.GetByFrameId(inputFrameId).(*InputFrameDownsync).InputList[0]; held != predicted {
+			t.Fatalf("Expected inputFrameId=%v to be predicted as %v after the upsync, got %v", inputFrameId, held, predicted)
+		}
+	}
+}
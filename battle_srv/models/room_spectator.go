@@ -0,0 +1,204 @@
+package models
+
+import (
+	. "battle_srv/common"
+	. "battle_srv/protos"
+	. "dnmshared"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+const (
+	MAX_SPECTATOR_COUNT_PER_ROOM = 16
+)
+
+/*
+An authenticated non-participant watching an ongoing battle, it's NEVER put into "pR.Players", thus doesn't count toward "Capacity", "EffectivePlayerCount", "JoinIndexBooleanArr" or any confirmation mask.
+
+Unlike a player, a spectator never reconnects into the same instance, therefore its network session is kept in the struct instead of the "PlayerDownsyncSessionDict"-like dictionaries.
+*/
+type Spectator struct {
+	Id                   int32
+	BattleState          int32 // Either "PlayerBattleStateIns.READDED_PENDING_BATTLE_COLLIDER_ACK" or "PlayerBattleStateIns.ACTIVE", only accessed with "pR.spectatorsMux" locked
+	LastSentInputFrameId int32 // Only accessed by "battleMainLoop"
+
+	session       *websocket.Conn
+	sessionMux    sync.Mutex // The same reason as "Room.PlayerDownsyncSessionMuxDict"
+	signalToClose SignalToCloseConnCbType
+}
+
+/*
+Only an IN_BATTLE room with "BackendDynamicsEnabled" is spectatable, because a spectator starts from a "DOWNSYNC_MSG_ACT_FORCED_RESYNC". The "BattleColliderInfo" is sent right away as a "DOWNSYNC_MSG_ACT_HB_REQ", marked as "READDED_PENDING_BATTLE_COLLIDER_ACK" such that the frontend waits for the resync like a rejoined player.
+*/
+func (pR *Room) AddSpectatorIfPossible(playerId int32, session *websocket.Conn, signalToCloseConnOfThisPlayer SignalToCloseConnCbType) bool {
+	if RoomBattleStateIns.IN_BATTLE != pR.State || !pR.BackendDynamicsEnabled {
+		Logger.Warn("AddSpectatorIfPossible error, roomState:", zap.Any("playerId", playerId), zap.Any("roomId", pR.Id), zap.Any("roomState", pR.State), zap.Any("backendDynamicsEnabled", pR.BackendDynamicsEnabled))
+		return false
+	}
+	if _, existent := pR.Players[playerId]; existent {
+		Logger.Warn("AddSpectatorIfPossible error, a participant should rejoin by \"boundRoomId\" instead:", zap.Any("playerId", playerId), zap.Any("roomId", pR.Id))
+		return false
+	}
+
+	pR.spectatorsMux.Lock()
+	if _, existent := pR.spectators[playerId]; existent || MAX_SPECTATOR_COUNT_PER_ROOM <= len(pR.spectators) {
+		pR.spectatorsMux.Unlock()
+		Logger.Warn("AddSpectatorIfPossible error, already spectating or too many spectators:", zap.Any("playerId", playerId), zap.Any("roomId", pR.Id))
+		return false
+	}
+	spectator := &Spectator{
+		Id:                   playerId,
+		BattleState:          PlayerBattleStateIns.READDED_PENDING_BATTLE_COLLIDER_ACK,
+		LastSentInputFrameId: MAGIC_LAST_SENT_INPUT_FRAME_ID_READDED,
+		session:              session,
+		signalToClose:        signalToCloseConnOfThisPlayer,
+	}
+	pR.spectators[playerId] = spectator
+	pR.spectatorsMux.Unlock()
+
+	time.AfterFunc(5*time.Second, func() {
+		pR.spectatorsMux.Lock()
+		pending := (PlayerBattleStateIns.READDED_PENDING_BATTLE_COLLIDER_ACK == spectator.BattleState)
+		pR.spectatorsMux.Unlock()
+		if pending {
+			signalToCloseConnOfThisPlayer(Constants.RetCode.UnknownError, fmt.Sprintf("The expected Ack for BattleColliderInfo is not received in 5s, for spectator playerId == %v!", playerId))
+		}
+	})
+
+	pR.sendToSpectatorSafely(spectator, &WsResp{
+		Ret:      int32(Constants.RetCode.Ok),
+		Act:      DOWNSYNC_MSG_ACT_HB_REQ,
+		BciFrame: pR.BuildBattleColliderInfo(PlayerBattleStateIns.READDED_PENDING_BATTLE_COLLIDER_ACK),
+	})
+	Logger.Info("AddSpectatorIfPossible finished:", zap.Any("roomId", pR.Id), zap.Any("playerId", playerId))
+	return true
+}
+
+func (pR *Room) OnSpectatorBattleColliderAcked(playerId int32) bool {
+	pR.spectatorsMux.Lock()
+	defer pR.spectatorsMux.Unlock()
+	spectator, existent := pR.spectators[playerId]
+	if !existent {
+		return false
+	}
+	// The "DOWNSYNC_MSG_ACT_FORCED_RESYNC" will be sent by "battleMainLoop" due to "MAGIC_LAST_SENT_INPUT_FRAME_ID_READDED"
+	spectator.BattleState = PlayerBattleStateIns.ACTIVE
+	return true
+}
+
+/*
+Only triggered from "signalToCloseConnOfThisPlayer" in "ws/serve.go", including that of a session rejected by "AddSpectatorIfPossible".
+
+[WARNING] The entry is removed only if it's still of "session", otherwise a rejected second session of an already spectating player would remove the entry of the first one.
+*/
+func (pR *Room) OnSpectatorDisconnected(playerId int32, session *websocket.Conn) {
+	pR.spectatorsMux.Lock()
+	defer pR.spectatorsMux.Unlock()
+	spectator, existent := pR.spectators[playerId]
+	if !existent || session != spectator.session {
+		return
+	}
+	delete(pR.spectators, playerId)
+	Logger.Info("Spectator disconnected from room:", zap.Any("playerId", playerId), zap.Any("roomId", pR.Id))
+}
+
+/*
+Copied under "pR.spectatorsMux" such that sending is done without the lock, because a failed sending triggers "OnSpectatorDisconnected".
+*/
+func (pR *Room) copySpectators(activeOnly bool) []*Spectator {
+	pR.spectatorsMux.Lock()
+	defer pR.spectatorsMux.Unlock()
+	ret := make([]*Spectator, 0, len(pR.spectators))
+	for _, spectator := range pR.spectators {
+		if activeOnly && PlayerBattleStateIns.ACTIVE != spectator.BattleState {
+			continue
+		}
+		ret = append(ret, spectator)
+	}
+	return ret
+}
+
+/*
+Called by "battleMainLoop" after downsyncing to players, with the same "refRenderFrameId" and "upperToSendInputFrameId". A newly acked spectator gets a "DOWNSYNC_MSG_ACT_FORCED_RESYNC" of the latest "RenderFrameBuffer" entry which is consistent with all-confirmed inputFrames, i.e. "refRenderFrameId", and afterwards only all-confirmed "InputFrameDownsync" batches.
+
+[WARNING] Unlike players, the "LastSentInputFrameId" of spectators isn't taken into account for the eviction of "pR.InputsBuffer", because a spectator always catches up with "upperToSendInputFrameId" in the same iteration.
+*/
+func (pR *Room) downsyncToSpectators(refRenderFrameId int32, upperToSendInputFrameId int32) {
+	for _, spectator := range pR.copySpectators(true) {
+		shouldResync := (MAGIC_LAST_SENT_INPUT_FRAME_ID_READDED == spectator.LastSentInputFrameId)
+		candidateToSendInputFrameId := spectator.LastSentInputFrameId + 1
+		if shouldResync {
+			candidateToSendInputFrameId = pR.ConvertToDelayedInputFrameId(refRenderFrameId)
+			if 0 > candidateToSendInputFrameId {
+				candidateToSendInputFrameId = 0
+			}
+		}
+		if candidateToSendInputFrameId < pR.InputsBuffer.StFrameId {
+			candidateToSendInputFrameId = pR.InputsBuffer.StFrameId
+		}
+		if candidateToSendInputFrameId > upperToSendInputFrameId {
+			// [WARNING] When sending DOWNSYNC_MSG_ACT_FORCED_RESYNC, there MUST BE accompanying "toSendInputFrames" for calculating "refRenderFrameId"!
+			continue
+		}
+		toSendInputFrames := make([]*InputFrameDownsync, 0, upperToSendInputFrameId-candidateToSendInputFrameId+1)
+		for inputFrameId := candidateToSendInputFrameId; inputFrameId <= upperToSendInputFrameId; inputFrameId++ {
+			tmp := pR.InputsBuffer.GetByFrameId(inputFrameId)
+			if nil == tmp {
+				panic(fmt.Sprintf("Required inputFrameId=%v for roomId=%v, spectator playerId=%v doesn't exist! InputsBuffer=%v", inputFrameId, pR.Id, spectator.Id, pR.InputsBufferString(false)))
+			}
+			toSendInputFrames = append(toSendInputFrames, tmp.(*InputFrameDownsync))
+		}
+
+		pResp := &WsResp{
+			Ret:                     int32(Constants.RetCode.Ok),
+			Act:                     DOWNSYNC_MSG_ACT_INPUT_BATCH,
+			InputFrameDownsyncBatch: toSendInputFrames,
+		}
+		if shouldResync {
+			tmp := pR.RenderFrameBuffer.GetByFrameId(refRenderFrameId)
+			if nil == tmp {
+				panic(fmt.Sprintf("Required refRenderFrameId=%v for roomId=%v, spectator playerId=%v doesn't exist! RenderFrameBuffer=%v", refRenderFrameId, pR.Id, spectator.Id, pR.RenderFrameBufferString()))
+			}
+			pResp.Act = DOWNSYNC_MSG_ACT_FORCED_RESYNC
+			pResp.Rdf = tmp.(*RoomDownsyncFrame)
+		}
+		pR.sendToSpectatorSafely(spectator, pResp)
+		spectator.LastSentInputFrameId = upperToSendInputFrameId
+	}
+}
+
+func (pR *Room) broadcastToSpectators(pResp *WsResp) {
+	for _, spectator := range pR.copySpectators(true) {
+		pR.sendToSpectatorSafely(spectator, pResp)
+	}
+}
+
+// Closes the sessions of all spectators, called upon dismissal.
+func (pR *Room) expelAllSpectators() {
+	for _, spectator := range pR.copySpectators(false) {
+		spectator.signalToClose(Constants.RetCode.Ok, "battle ended")
+	}
+}
+
+func (pR *Room) sendToSpectatorSafely(spectator *Spectator, pResp *WsResp) {
+	defer func() {
+		if r := recover(); r != nil {
+			spectator.signalToClose(Constants.RetCode.UnknownError, fmt.Sprintf("%v", r))
+		}
+	}()
+	theBytes, marshalErr := proto.Marshal(pResp)
+	if nil != marshalErr {
+		panic(fmt.Sprintf("Error marshaling downsync message to spectator: roomId=%v, playerId=%v, roomState=%v", pR.Id, spectator.Id, pR.State))
+	}
+	spectator.sessionMux.Lock()
+	defer spectator.sessionMux.Unlock()
+	if err := spectator.session.WriteMessage(websocket.BinaryMessage, theBytes); nil != err {
+		panic(fmt.Sprintf("Error sending downsync message to spectator: roomId=%v, playerId=%v, roomState=%v, err=%v", pR.Id, spectator.Id, pR.State, err))
+	}
+	pR.LoadStats.onDownsyncSent(pResp.Act, len(theBytes))
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"github.com/gorilla/websocket"
+	"testing"
+)
+
+func TestRejectedSpectatorSessionKeepsTheAddedOne(t *testing.T) {
+	added, rejected := &websocket.Conn{}, &websocket.Conn{}
+	pR := &Room{
+		spectators: map[int32]*Spectator{
+			10: &Spectator{Id: 10, session: added},
+		},
+	}
+
+	pR.OnSpectatorDisconnected(10, rejected)
+	if spectator, existent := pR.spectators[10]; !existent || added != spectator.session {
+		t.Fatalf("The spectator added by the first session shouldn't be removed upon the disconnection of a rejected one")
+	}
+	pR.OnSpectatorDisconnected(10, added)
+	if _, existent := pR.spectators[10]; existent {
+		t.Fatalf("The spectator should be removed upon the disconnection of its own session")
+	}
+}
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"battle_srv/battle"
+	. "battle_srv/common"
+	. "battle_srv/protos"
+	. "dnmshared"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	DEFAULT_SPECIES_ID = int32(0)
+
+	WORLD_TO_VIRTUAL_GRID_RATIO = int32(1000) // Of every room, see "refreshBattleStates"
+)
+
+/*
+Parsed once from "<battle_srv>/configs/skills.json" at startup, such that designers can add or tune moves by editing the file and restarting, without recompiling.
+
+The "recoveryFrames" of a skill had better be 1 frame more than its actual animation to avoid critical transition, i.e. when the animation is 1 frame from ending but "framesToRecover" is already counted 0 and the player triggers another same attack, making an effective bullet but no animation is played due to the same animName still playing.
+
+Every length, i.e. "hitboxOffset", "hitboxSize*", "speed" and "pushback", is in world units throughout the file. Those of "fireballSkills" must be whole numbers because they're converted into virtual grid units upon loading, see "convertFireballSkillsToVirtualGrid".
+
+[WARNING] Shared by all rooms and READ-ONLY once parsed, every fired bullet is a clone in "battle.fireMeleeSkill".
+*/
+var SkillConfigsIns *SkillConfigs
+
+func MustLoadSkillConfigs() {
+	fp := filepath.Join(Conf.General.ConfDir, "skills.json")
+	theBytes, err := os.ReadFile(fp)
+	if nil != err {
+		panic(err)
+	}
+	tmp := &SkillConfigs{}
+	if err := protojson.Unmarshal(theBytes, tmp); nil != err {
+		panic(fmt.Sprintf("Error parsing %v: %v", fp, err))
+	}
+	if err := validateSkillConfigs(tmp); nil != err {
+		panic(fmt.Sprintf("Invalid %v: %v", fp, err))
+	}
+	convertFireballSkillsToVirtualGrid(tmp)
+	SkillConfigsIns = tmp
+	Logger.Info("Skill configs loaded:", zap.Any("fp", fp), zap.Any("skillCount", len(tmp.Skills)), zap.Any("fireballSkillCount", len(tmp.FireballSkills)), zap.Any("speciesCount", len(tmp.Characters)))
+}
+
+func validateSkillConfigs(configs *SkillConfigs) error {
+	for skillId, skill := range configs.Skills {
+		if nil == skill {
+			return fmt.Errorf("skillId=%v is empty", skillId)
+		}
+		if battle.SKILL_RELEASE_TRIGGER_TYPE_RISING_EDGE != skill.ReleaseTriggerType && battle.SKILL_RELEASE_TRIGGER_TYPE_FALLING_EDGE != skill.ReleaseTriggerType {
+			return fmt.Errorf("skillId=%v has unknown releaseTriggerType=%v", skillId, skill.ReleaseTriggerType)
+		}
+		if nil == skill.HitboxSize || 0 >= skill.HitboxSize.X || 0 >= skill.HitboxSize.Y {
+			return fmt.Errorf("skillId=%v has no positive hitboxSize", skillId)
+		}
+		if 0 > skill.StartupFrames || 0 >= skill.ActiveFrames || 0 > skill.RecoveryFrames {
+			return fmt.Errorf("skillId=%v has invalid startupFrames=%v, activeFrames=%v or recoveryFrames=%v", skillId, skill.StartupFrames, skill.ActiveFrames, skill.RecoveryFrames)
+		}
+		skill.SkillId = skillId
+	}
+	for skillId, skill := range configs.FireballSkills {
+		if nil == skill {
+			return fmt.Errorf("fireball skillId=%v is empty", skillId)
+		}
+		if _, existent := configs.Skills[skillId]; existent {
+			return fmt.Errorf("fireball skillId=%v collides with a melee skill", skillId)
+		}
+		if battle.SKILL_RELEASE_TRIGGER_TYPE_RISING_EDGE != skill.ReleaseTriggerType && battle.SKILL_RELEASE_TRIGGER_TYPE_FALLING_EDGE != skill.ReleaseTriggerType {
+			return fmt.Errorf("fireball skillId=%v has unknown releaseTriggerType=%v", skillId, skill.ReleaseTriggerType)
+		}
+		if 0 >= skill.HitboxSizeX || 0 >= skill.HitboxSizeY {
+			return fmt.Errorf("fireball skillId=%v has no positive hitboxSizeX or hitboxSizeY", skillId)
+		}
+		if 0 >= skill.StartupFrames || 0 >= skill.ActiveFrames || 0 > skill.RecoveryFrames || 0 > skill.Speed || 0 > skill.Pushback {
+			return fmt.Errorf("fireball skillId=%v has invalid startupFrames=%v, activeFrames=%v, recoveryFrames=%v, speed=%v or pushback=%v", skillId, skill.StartupFrames, skill.ActiveFrames, skill.RecoveryFrames, skill.Speed, skill.Pushback)
+		}
+		skill.SkillId = skillId
+	}
+	if _, existent := configs.Characters[DEFAULT_SPECIES_ID]; !existent {
+		return fmt.Errorf("the default speciesId=%v is not bound", DEFAULT_SPECIES_ID)
+	}
+	for speciesId, binding := range configs.Characters {
+		if nil == binding {
+			return fmt.Errorf("speciesId=%v is empty", speciesId)
+		}
+		for btn, skillId := range binding.BtnToSkillId {
+			if !isSkillBtn(btn) {
+				return fmt.Errorf("speciesId=%v binds unknown btn=%v", speciesId, btn)
+			}
+			_, isMelee := configs.Skills[skillId]
+			_, isFireball := configs.FireballSkills[skillId]
+			if !isMelee && !isFireball {
+				return fmt.Errorf("speciesId=%v binds btn=%v to nonexistent skillId=%v", speciesId, btn, skillId)
+			}
+		}
+	}
+	return nil
+}
+
+// A fireball is simulated in virtual grid units for determinism, unlike a melee bullet whose hitbox is checked by "resolv" in world units.
+func convertFireballSkillsToVirtualGrid(configs *SkillConfigs) {
+	for _, skill := range configs.FireballSkills {
+		skill.HitboxOffset *= WORLD_TO_VIRTUAL_GRID_RATIO
+		skill.HitboxSizeX *= WORLD_TO_VIRTUAL_GRID_RATIO
+		skill.HitboxSizeY *= WORLD_TO_VIRTUAL_GRID_RATIO
+		skill.Speed *= WORLD_TO_VIRTUAL_GRID_RATIO
+		skill.Pushback *= WORLD_TO_VIRTUAL_GRID_RATIO
+	}
+}
+
+func IsSpeciesIdValid(speciesId int32) bool {
+	_, existent := SkillConfigsIns.Characters[speciesId]
+	return existent
+}
+
+func isSkillBtn(btn string) bool {
+	for _, skillBtn := range battle.SKILL_BTNS {
+		if skillBtn == btn {
+			return true
+		}
+	}
+	return false
+}
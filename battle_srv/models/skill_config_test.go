@@ -0,0 +1,38 @@
+package models
+
+import (
+	"battle_srv/battle"
+	. "battle_srv/protos"
+	"google.golang.org/protobuf/encoding/protojson"
+	"testing"
+)
+
+func TestFireballSkillConfigsAreConvertedFromWorldUnits(t *testing.T) {
+	theBytes := []byte(`{
+  "skills": {
+    "1": { "startupFrames": 2, "activeFrames": 3, "hitboxOffset": 24.0, "hitboxSize": { "x": 45.0, "y": 32.0 }, "pushback": 11.0, "releaseTriggerType": 1 }
+  },
+  "fireballSkills": {
+    "3": { "startupFrames": 12, "activeFrames": 90, "hitboxOffset": 24.0, "hitboxSizeX": 24.0, "hitboxSizeY": 16.0, "speed": 5.0, "pushback": 8.0, "releaseTriggerType": 1 }
+  },
+  "characters": {
+    "0": { "btnToSkillId": { "BtnA": 1, "BtnB": 3 } }
+  }
+}`)
+	configs := &SkillConfigs{}
+	if err := protojson.Unmarshal(theBytes, configs); nil != err {
+		t.Fatal(err)
+	}
+	if err := validateSkillConfigs(configs); nil != err {
+		t.Fatal(err)
+	}
+	convertFireballSkillsToVirtualGrid(configs)
+
+	fireball := configs.FireballSkills[3]
+	if 24000 != fireball.HitboxOffset || 24000 != fireball.HitboxSizeX || 16000 != fireball.HitboxSizeY || 5000 != fireball.Speed || 8000 != fireball.Pushback {
+		t.Fatalf("Expected the fireball lengths in virtual grid units, got %v", fireball)
+	}
+	if melee := configs.Skills[1]; 24 != melee.HitboxOffset || 11 != melee.Pushback || battle.SKILL_RELEASE_TRIGGER_TYPE_RISING_EDGE != melee.ReleaseTriggerType {
+		t.Fatalf("Expected the melee skill kept in world units, got %v", melee)
+	}
+}
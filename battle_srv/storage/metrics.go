@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"battle_srv/metrics"
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/go-sql-driver/mysql"
+)
+
+/*
+The following wrappers observe "metrics.StorageCallDurationSeconds" for each MySQL statement and Redis command, such that the call sites in "models", "api" and "env_tools" needn't be changed.
+
+For MySQL the duration of a query covers until the rows are returned by the driver, i.e. excluding the scanning of rows.
+*/
+
+// All of the following are implemented by the connection of "github.com/go-sql-driver/mysql".
+type mysqlConnIface interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+	driver.NamedValueChecker
+	driver.SessionResetter
+}
+
+type mysqlStmtIface interface {
+	driver.Stmt
+	driver.StmtExecContext
+	driver.StmtQueryContext
+	driver.ColumnConverter
+}
+
+type instrumentedMySQLConnector struct {
+	dsn string
+}
+
+func (c *instrumentedMySQLConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := mysql.MySQLDriver{}.Open(c.dsn)
+	if nil != err {
+		return nil, err
+	}
+	theConn, ok := conn.(mysqlConnIface)
+	if !ok {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected mysql driver connection type %T", conn)
+	}
+	return &instrumentedMySQLConn{theConn}, nil
+}
+
+func (c *instrumentedMySQLConnector) Driver() driver.Driver {
+	return mysql.MySQLDriver{}
+}
+
+type instrumentedMySQLConn struct {
+	mysqlConnIface
+}
+
+func (conn *instrumentedMySQLConn) Prepare(query string) (driver.Stmt, error) {
+	return conn.PrepareContext(context.Background(), query)
+}
+
+func (conn *instrumentedMySQLConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	stmt, err := conn.mysqlConnIface.PrepareContext(ctx, query)
+	if nil != err {
+		return nil, err
+	}
+	theStmt, ok := stmt.(mysqlStmtIface)
+	if !ok {
+		stmt.Close()
+		return nil, fmt.Errorf("unexpected mysql driver statement type %T", stmt)
+	}
+	return &instrumentedMySQLStmt{theStmt, mysqlOpOf(query)}, nil
+}
+
+func (conn *instrumentedMySQLConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	defer observeStorageCall(metrics.STORAGE_BACKEND_MYSQL, mysqlOpOf(query), time.Now())
+	return conn.mysqlConnIface.ExecContext(ctx, query, args)
+}
+
+func (conn *instrumentedMySQLConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	defer observeStorageCall(metrics.STORAGE_BACKEND_MYSQL, mysqlOpOf(query), time.Now())
+	return conn.mysqlConnIface.QueryContext(ctx, query, args)
+}
+
+type instrumentedMySQLStmt struct {
+	mysqlStmtIface
+	op string
+}
+
+func (stmt *instrumentedMySQLStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	defer observeStorageCall(metrics.STORAGE_BACKEND_MYSQL, stmt.op, time.Now())
+	return stmt.mysqlStmtIface.ExecContext(ctx, args)
+}
+
+func (stmt *instrumentedMySQLStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	defer observeStorageCall(metrics.STORAGE_BACKEND_MYSQL, stmt.op, time.Now())
+	return stmt.mysqlStmtIface.QueryContext(ctx, args)
+}
+
+// The leading keyword of "query" in lower case, e.g. "select", or "other" to keep the cardinality bounded.
+func mysqlOpOf(query string) string {
+	fields := strings.Fields(query)
+	if 0 == len(fields) {
+		return "other"
+	}
+	op := strings.ToLower(fields[0])
+	switch op {
+	case "select", "insert", "update", "delete", "replace", "truncate":
+		return op
+	default:
+		return "other"
+	}
+}
+
+func instrumentRedis(client *redis.Client) {
+	client.WrapProcess(func(oldProcess func(cmd redis.Cmder) error) func(cmd redis.Cmder) error {
+		return func(cmd redis.Cmder) error {
+			defer observeStorageCall(metrics.STORAGE_BACKEND_REDIS, cmd.Name(), time.Now())
+			return oldProcess(cmd)
+		}
+	})
+	client.WrapProcessPipeline(func(oldProcess func(cmds []redis.Cmder) error) func(cmds []redis.Cmder) error {
+		return func(cmds []redis.Cmder) error {
+			defer observeStorageCall(metrics.STORAGE_BACKEND_REDIS, "pipeline", time.Now())
+			return oldProcess(cmds)
+		}
+	})
+}
+
+func observeStorageCall(backend string, op string, startedAt time.Time) {
+	metrics.StorageCallDurationSeconds.WithLabelValues(backend, op).Observe(time.Since(startedAt).Seconds())
+}
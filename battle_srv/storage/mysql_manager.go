@@ -2,8 +2,8 @@ package storage
 
 import (
 	. "battle_srv/common"
+	"database/sql"
 
-	_ "github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 	"go.uber.org/zap"
 
@@ -15,12 +15,9 @@ var (
 )
 
 func initMySQL() {
-	var err error
-	MySQLManagerIns, err = sqlx.Connect("mysql", Conf.MySQL.DSN+"?charset=utf8mb4")
-	if nil != err {
-		panic(err)
-	}
-	err = MySQLManagerIns.Ping()
+	// The driver name "mysql" is kept for "sqlx" to choose the bindvar type
+	MySQLManagerIns = sqlx.NewDb(sql.OpenDB(&instrumentedMySQLConnector{Conf.MySQL.DSN + "?charset=utf8mb4"}), "mysql")
+	err := MySQLManagerIns.Ping()
 	if nil != err {
 		panic(err)
 	}
@@ -21,6 +21,7 @@ func initRedis() {
 		Password: Conf.Redis.Password, // no password set
 		DB:       Conf.Redis.Dbname,   // use default DB
 	})
+	instrumentRedis(RedisManagerIns)
 	pong, err := RedisManagerIns.Ping().Result()
 	if nil != err {
 		panic(err)
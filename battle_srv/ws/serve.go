@@ -1,7 +1,9 @@
 package ws
 
 import (
+	"battle_srv/battle"
 	. "battle_srv/common"
+	"battle_srv/metrics"
 	"battle_srv/models"
 	pb "battle_srv/protos"
 	"container/heap"
@@ -50,6 +52,7 @@ func Serve(c *gin.Context) {
 	Logger.Info("Finding PlayerLogin record for ws authentication:", zap.Any("intAuthToken", token))
 	boundRoomId := 0
 	expectRoomId := 0
+	spectateRoomId := 0
 	var err error
 	if boundRoomIdStr, hasBoundRoomId := c.GetQuery("boundRoomId"); hasBoundRoomId {
 		boundRoomId, err = strconv.Atoi(boundRoomIdStr)
@@ -68,6 +71,38 @@ func Serve(c *gin.Context) {
 		}
 		Logger.Info("Finding PlayerLogin record for ws authentication:", zap.Any("intAuthToken", token), zap.Any("expectedRoomId", expectRoomId))
 	}
+	if spectateRoomIdStr, hasSpectateRoomId := c.GetQuery("spectateRoomId"); hasSpectateRoomId {
+		spectateRoomId, err = strconv.Atoi(spectateRoomIdStr)
+		if err != nil || 0 >= spectateRoomId || 0 < boundRoomId || 0 < expectRoomId {
+			// A spectator never joins as a participant
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+		Logger.Info("Finding PlayerLogin record for ws authentication:", zap.Any("intAuthToken", token), zap.Any("spectateRoomId", spectateRoomId))
+	}
+	isSpectator := (0 < spectateRoomId)
+	inviteCode := c.Query("inviteCode") // Only for joining a private room reserved by "/api/room/v1/private/reserve"
+	if "" != inviteCode && (isSpectator || 0 < expectRoomId) {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+	inputEncodingVersion := 1 // Clients before the versioning didn't carry "inputEncodingVersion"
+	if inputEncodingVersionStr, hasInputEncodingVersion := c.GetQuery("inputEncodingVersion"); hasInputEncodingVersion {
+		inputEncodingVersion, err = strconv.Atoi(inputEncodingVersionStr)
+		if err != nil {
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+	}
+	speciesId := models.DEFAULT_SPECIES_ID
+	if speciesIdStr, hasSpeciesId := c.GetQuery("speciesId"); hasSpeciesId {
+		tmp, err := strconv.Atoi(speciesIdStr)
+		if err != nil || !models.IsSpeciesIdValid(int32(tmp)) {
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+		speciesId = int32(tmp)
+	}
 
 	// TODO: Wrap the following 2 stmts by sql transaction!
 	playerId, err := models.GetPlayerIdByToken(token)
@@ -86,6 +121,7 @@ func Serve(c *gin.Context) {
 		c.AbortWithStatus(http.StatusBadRequest)
 		return
 	}
+	metrics.ActiveWsSessions.Inc()
 	Logger.Debug("ConstVals.Ws.WillKickIfInactiveFor", zap.Duration("v", ConstVals.Ws.WillKickIfInactiveFor))
 	/**
 	 * WARNING: After successfully upgraded to use the "persistent connection" of http1.1/websocket protocol, you CANNOT overwrite the http1.0 resp status by `c.AbortWithStatus(...)` any more!
@@ -99,9 +135,14 @@ func Serve(c *gin.Context) {
 		if swapped := atomic.CompareAndSwapInt32(pConnHasBeenSignaledToClose, 0, 1); !swapped {
 			return
 		}
+		metrics.ActiveWsSessions.Dec()
 		Logger.Warn("signalToCloseConnOfThisPlayer:", zap.Any("playerId", playerId), zap.Any("customRetCode", customRetCode), zap.Any("customRetMsg", customRetMsg))
 		if nil != pRoom {
-			pRoom.OnPlayerDisconnected(int32(playerId))
+			if isSpectator {
+				pRoom.OnSpectatorDisconnected(int32(playerId), conn)
+			} else {
+				pRoom.OnPlayerDisconnected(int32(playerId))
+			}
 		}
 		defer func() {
 			if r := recover(); r != nil {
@@ -153,14 +194,32 @@ func Serve(c *gin.Context) {
 	 */
 	conn.SetCloseHandler(onReceivedCloseMessageFromClient)
 
+	if !isSpectator && battle.INPUT_ENCODING_VERSION != inputEncodingVersion {
+		// Rejected after upgraded such that the client receives a close code, which it can't tell from an http error status
+		signalToCloseConnOfThisPlayer(Constants.RetCode.InputEncodingVersionMismatch, fmt.Sprintf("inputEncodingVersion == %v is not supported, expecting %v", inputEncodingVersion, battle.INPUT_ENCODING_VERSION))
+		return
+	}
+
 	pPlayer, err := models.GetPlayerById(playerId)
 
 	if nil != err || nil == pPlayer {
 		// TODO: Abort with specific message.
 		signalToCloseConnOfThisPlayer(Constants.RetCode.PlayerNotFound, "")
+		return
 	}
 
 	Logger.Info("Player has logged in and its profile is found from persistent storage:", zap.Any("playerId", playerId), zap.Any("play", pPlayer))
+	pPlayer.SpeciesId = speciesId // Only effective upon "AddPlayerIfPossible", a rejoining player keeps the species chosen initially
+
+	var pMatchedRoom *models.Room = nil
+	if !isSpectator && 0 == boundRoomId && 0 == expectRoomId && "" == inviteCode && Conf.Matchmaking.Enabled {
+		// Waiting without "RoomHeapMux" locked
+		pMatchedRoom = models.MatchmakingQueueIns.WaitForMatch(int32(playerId))
+		if nil == pMatchedRoom {
+			signalToCloseConnOfThisPlayer(Constants.RetCode.MatchmakingTimeout, fmt.Sprintf("No match found in time for playerId == %v!", playerId))
+			return
+		}
+	}
 
 	// Find a room to join.
 	Logger.Info("About to acquire RoomHeapMux for player:", zap.Any("playerId", playerId))
@@ -178,6 +237,16 @@ func Serve(c *gin.Context) {
 	Logger.Info("Acquired RoomHeapMux for player:", zap.Any("playerId", playerId))
 	// Logger.Info("The RoomHeapManagerIns has:", zap.Any("addr", fmt.Sprintf("%p", models.RoomHeapManagerIns)), zap.Any("size", len(*(models.RoomHeapManagerIns))))
 	playerSuccessfullyAddedToRoom := false
+	if isSpectator {
+		if tmpRoom, existent := (*models.RoomMapManagerIns)[int32(spectateRoomId)]; existent {
+			pRoom = tmpRoom
+		}
+		if nil == pRoom || !pRoom.AddSpectatorIfPossible(int32(playerId), conn, signalToCloseConnOfThisPlayer) {
+			signalToCloseConnOfThisPlayer(Constants.RetCode.PlayerNotAddableToRoom, fmt.Sprintf("AddSpectatorIfPossible returns false for roomId == %v, playerId == %v!", spectateRoomId, playerId))
+			return
+		}
+	}
+
 	if 0 < boundRoomId {
 		if tmpPRoom, existent := (*models.RoomMapManagerIns)[int32(boundRoomId)]; existent {
 			pRoom = tmpPRoom
@@ -198,7 +267,7 @@ func Serve(c *gin.Context) {
 
 			if pRoom.ReAddPlayerIfPossible(pPlayer, conn, signalToCloseConnOfThisPlayer) {
 				playerSuccessfullyAddedToRoom = true
-			} else if pRoom.AddPlayerIfPossible(pPlayer, conn, signalToCloseConnOfThisPlayer) {
+			} else if pRoom.AddPlayerIfPossible(pPlayer, "", conn, signalToCloseConnOfThisPlayer) {
 				playerSuccessfullyAddedToRoom = true
 			} else {
 				Logger.Warn("Failed to get:\n", zap.Any("roomId", pRoom.Id), zap.Any("playerId", playerId), zap.Any("forExpectedRoomId", expectRoomId))
@@ -208,7 +277,30 @@ func Serve(c *gin.Context) {
 		}
 	}
 
-	if false == playerSuccessfullyAddedToRoom {
+	if "" != inviteCode && false == playerSuccessfullyAddedToRoom {
+		// A private room is out of "RoomHeapManagerIns", thus never pushed back
+		pRoom = models.GetPrivateRoomByInviteCode(inviteCode)
+		if nil == pRoom {
+			signalToCloseConnOfThisPlayer(Constants.RetCode.LocallyNoSpecifiedRoom, fmt.Sprintf("No private room is reserved by the invite code, playerId == %v!", playerId))
+			return
+		}
+		if !pRoom.AddPlayerIfPossible(pPlayer, inviteCode, conn, signalToCloseConnOfThisPlayer) {
+			signalToCloseConnOfThisPlayer(Constants.RetCode.PlayerNotAddableToRoom, fmt.Sprintf("AddPlayerIfPossible returns false for private roomId == %v, playerId == %v!", pRoom.Id, playerId))
+			return
+		}
+		Logger.Info("Successfully joined a private room:\n", zap.Any("roomId", pRoom.Id), zap.Any("playerId", playerId))
+		playerSuccessfullyAddedToRoom = true
+	}
+
+	if nil != pMatchedRoom {
+		// A matchmade room is out of "RoomHeapManagerIns" until its reservation is released, thus never pushed back
+		pRoom = pMatchedRoom
+		Logger.Info("Successfully matched:\n", zap.Any("roomId", pRoom.Id), zap.Any("playerId", playerId))
+		if !pRoom.AddPlayerIfPossible(pPlayer, "", conn, signalToCloseConnOfThisPlayer) {
+			signalToCloseConnOfThisPlayer(Constants.RetCode.PlayerNotAddableToRoom, fmt.Sprintf("AddPlayerIfPossible returns false for matched roomId == %v, playerId == %v!", pRoom.Id, playerId))
+		}
+	} else if !isSpectator && false == playerSuccessfullyAddedToRoom {
+		pRoom = nil // Only the popped room is pushed back, e.g. not the one failed by "expectedRoomId" which is still in "RoomHeapManagerIns"
 		defer func() {
 			if pRoom != nil {
 				heap.Push(models.RoomHeapManagerIns, pRoom)
@@ -216,13 +308,13 @@ func Serve(c *gin.Context) {
 			}
 			(models.RoomHeapManagerIns).PrintInOrder()
 		}()
-		tmpRoom, ok := heap.Pop(models.RoomHeapManagerIns).(*models.Room)
-		if !ok {
+		tmpRoom := models.PopAvailableRoom()
+		if nil == tmpRoom {
 			signalToCloseConnOfThisPlayer(Constants.RetCode.LocallyNoAvailableRoom, fmt.Sprintf("Cannot pop a (*Room) for playerId == %v!", playerId))
 		} else {
 			pRoom = tmpRoom
 			Logger.Info("Successfully popped:\n", zap.Any("roomId", pRoom.Id), zap.Any("playerId", playerId))
-			res := pRoom.AddPlayerIfPossible(pPlayer, conn, signalToCloseConnOfThisPlayer)
+			res := pRoom.AddPlayerIfPossible(pPlayer, "", conn, signalToCloseConnOfThisPlayer)
 			if !res {
 				signalToCloseConnOfThisPlayer(Constants.RetCode.PlayerNotAddableToRoom, fmt.Sprintf("AddPlayerIfPossible returns false for roomId == %v, playerId == %v!", pRoom.Id, playerId))
 			}
@@ -244,36 +336,7 @@ func Serve(c *gin.Context) {
 		}()
 
 		// Construct "battleColliderInfo" to downsync
-		bciFrame := &pb.BattleColliderInfo{
-			BoundRoomId:           pRoom.Id,
-			StageName:             pRoom.StageName,
-			StrToVec2DListMap:     pRoom.StrToVec2DListMap,
-			StrToPolygon2DListMap: pRoom.StrToPolygon2DListMap,
-			StageDiscreteW:        pRoom.StageDiscreteW,
-			StageDiscreteH:        pRoom.StageDiscreteH,
-			StageTileW:            pRoom.StageTileW,
-			StageTileH:            pRoom.StageTileH,
-
-			IntervalToPing:                  int32(Constants.Ws.IntervalToPing),
-			WillKickIfInactiveFor:           int32(Constants.Ws.WillKickIfInactiveFor),
-			BattleDurationNanos:             pRoom.BattleDurationNanos,
-			ServerFps:                       pRoom.ServerFps,
-			InputDelayFrames:                pRoom.InputDelayFrames,
-			InputScaleFrames:                pRoom.InputScaleFrames,
-			NstDelayFrames:                  pRoom.NstDelayFrames,
-			InputFrameUpsyncDelayTolerance:  pRoom.InputFrameUpsyncDelayTolerance,
-			MaxChasingRenderFramesPerUpdate: pRoom.MaxChasingRenderFramesPerUpdate,
-			PlayerBattleState:               pThePlayer.BattleState, // For frontend to know whether it's rejoining
-			RollbackEstimatedDtMillis:       pRoom.RollbackEstimatedDtMillis,
-			RollbackEstimatedDtNanos:        pRoom.RollbackEstimatedDtNanos,
-
-			WorldToVirtualGridRatio: pRoom.WorldToVirtualGridRatio,
-			VirtualGridToWorldRatio: pRoom.VirtualGridToWorldRatio,
-
-			SpAtkLookupFrames: pRoom.SpAtkLookupFrames,
-			RenderCacheSize:   pRoom.RenderCacheSize,
-			MeleeSkillConfig:  pRoom.MeleeSkillConfig,
-		}
+		bciFrame := pRoom.BuildBattleColliderInfo(pThePlayer.BattleState) // "PlayerBattleState" is for frontend to know whether it's rejoining
 
 		resp := &pb.WsResp{
 			Ret:         int32(Constants.RetCode.Ok),
@@ -390,11 +453,31 @@ func Serve(c *gin.Context) {
 			switch pReq.Act {
 			case models.UPSYNC_MSG_ACT_HB_PING:
 				startOrFeedHeartbeatWatchdog(conn)
+				pRoom.OnHeartbeatReceived(int32(playerId), pReq.Hb)
 			case models.UPSYNC_MSG_ACT_PLAYER_CMD:
 				startOrFeedHeartbeatWatchdog(conn)
+				if isSpectator {
+					// [WARNING] A spectator MUST NOT affect the confirmation of any inputFrame!
+					continue
+				}
 				pRoom.OnBattleCmdReceived(pReq)
+			case models.UPSYNC_MSG_ACT_PLAYER_READY:
+				if isSpectator {
+					continue
+				}
+				pRoom.OnPlayerReady(int32(playerId))
+			case models.UPSYNC_MSG_ACT_REMATCH_VOTE:
+				if isSpectator {
+					continue
+				}
+				pRoom.OnPlayerRematchVote(int32(playerId))
 			case models.UPSYNC_MSG_ACT_PLAYER_COLLIDER_ACK:
-				res := pRoom.OnPlayerBattleColliderAcked(int32(playerId))
+				var res bool
+				if isSpectator {
+					res = pRoom.OnSpectatorBattleColliderAcked(int32(playerId))
+				} else {
+					res = pRoom.OnPlayerBattleColliderAcked(int32(playerId))
+				}
 				if false == res {
 					Logger.Error("About to `signalToCloseConnOfThisPlayer`", zap.Any("roomId", pRoom.Id), zap.Any("playerId", playerId), zap.Error(err))
 					signalToCloseConnOfThisPlayer(Constants.RetCode.UnknownError, "")
@@ -0,0 +1,177 @@
+package dnmshared
+
+import (
+	. "dnmshared/sharedprotos"
+	"math"
+)
+
+/*
+An integer counterpart of "CalcPushbacks" working directly in virtual grid units, i.e. without any "VirtualGridToPolygonColliderAnchorPos" or "PolygonColliderAnchorToVirtualGridPos" roundtrip.
+
+All arithmetics are done in int64 and ONLY by "+", "-", "*", "/" (truncated towards zero) and an integer square root, thus any runtime, e.g. JavaScript by "BigInt", reproduces exactly the same results.
+
+[WARNING] The absolute coordinates are assumed to be within 2^24 virtual grid units, every edge within 2^22 and every penetration depth within 2^15 such that the intermediate products never overflow int64, which holds for any stage no larger than 8192*8192 in world coordinates with "WorldToVirtualGridRatio == 1000" and players of the default size.
+*/
+
+type IntVec2D struct {
+	X int64
+	Y int64
+}
+
+// A convex polygon whose "Points" are absolute positions in virtual grid units.
+type IntPolygon2D struct {
+	Points []IntVec2D
+}
+
+// Converts a "Polygon2D" in world coordinates, rounded once per point, supposed to be done once per stage rather than per render frame.
+func Polygon2DToIntPolygon2D(polygon *Polygon2D, worldToVirtualGridRatio float64) *IntPolygon2D {
+	ret := &IntPolygon2D{
+		Points: make([]IntVec2D, len(polygon.Points)),
+	}
+	for i, p := range polygon.Points {
+		ret.Points[i] = IntVec2D{
+			X: int64(math.Round((polygon.Anchor.X + p.X) * worldToVirtualGridRatio)),
+			Y: int64(math.Round((polygon.Anchor.Y + p.Y) * worldToVirtualGridRatio)),
+		}
+	}
+	return ret
+}
+
+// Reuses "ret" to avoid heap allocation per render frame, "(cx, cy)" is the center.
+func SetIntRectPolygon2D(ret *IntPolygon2D, cx, cy, halfW, halfH int64) {
+	if 4 != len(ret.Points) {
+		ret.Points = make([]IntVec2D, 4)
+	}
+	left, right, bottom, top := cx-halfW, cx+halfW, cy-halfH, cy+halfH
+	ret.Points[0] = IntVec2D{X: left, Y: bottom}
+	ret.Points[1] = IntVec2D{X: right, Y: bottom}
+	ret.Points[2] = IntVec2D{X: right, Y: top}
+	ret.Points[3] = IntVec2D{X: left, Y: top}
+}
+
+func (pPolygon *IntPolygon2D) boundingBox() (int64, int64, int64, int64) {
+	var minX, minY, maxX, maxY int64 = math.MaxInt64, math.MaxInt64, math.MinInt64, math.MinInt64
+	for _, p := range pPolygon.Points {
+		if p.X < minX {
+			minX = p.X
+		}
+		if p.X > maxX {
+			maxX = p.X
+		}
+		if p.Y < minY {
+			minY = p.Y
+		}
+		if p.Y > maxY {
+			maxY = p.Y
+		}
+	}
+	return minX, minY, maxX, maxY
+}
+
+/*
+Returns whether "a" and "b" are overlapped, and if so the pushback of "a" in virtual grid units, i.e. "a" should be moved by "(-pushbackX, -pushbackY)" to separate from "b" -- the same convention as "CalcPushbacks".
+
+The axis of the minimum penetration depth is chosen among the edge normals of "a" then "b", the first one wins in case of a tie. Touching without penetration is not an overlap.
+*/
+func CalcPushbacksInVirtualGrid(a, b *IntPolygon2D) (bool, int32, int32) {
+	aMinX, aMinY, aMaxX, aMaxY := a.boundingBox()
+	bMinX, bMinY, bMaxX, bMaxY := b.boundingBox()
+	if aMaxX <= bMinX || bMaxX <= aMinX || aMaxY <= bMinY || bMaxY <= aMinY {
+		return false, 0, 0
+	}
+
+	found := false
+	var bestDepth, bestOverlap int64 = 0, 0
+	var bestAxis IntVec2D
+	for _, polygon := range []*IntPolygon2D{a, b} {
+		cnt := len(polygon.Points)
+		if 1 >= cnt {
+			continue
+		}
+		for i := 0; i < cnt; i++ {
+			p, q := polygon.Points[i], polygon.Points[(i+1)%cnt]
+			axis := reducedNormOf(q.X-p.X, q.Y-p.Y)
+			if 0 == axis.X && 0 == axis.Y {
+				continue
+			}
+			aStart, aEnd := projectionRange(a, axis)
+			bStart, bEnd := projectionRange(b, axis)
+			if aEnd <= bStart || bEnd <= aStart {
+				// Separated by "axis"
+				return false, 0, 0
+			}
+			// Both options are the overlap in the unit of "|axis|", positive to push "a" along "-axis" and negative along "+axis"
+			overlap := aEnd - bStart
+			if option2 := bEnd - aStart; option2 < overlap {
+				overlap = -option2
+			}
+			absOverlap := overlap
+			if 0 > absOverlap {
+				absOverlap = -absOverlap
+			}
+			depth := absOverlap / intSqrt(axis.X*axis.X+axis.Y*axis.Y)
+			if !found || depth < bestDepth {
+				found, bestDepth, bestOverlap, bestAxis = true, depth, overlap, axis
+			}
+		}
+	}
+	if !found {
+		return false, 0, 0
+	}
+
+	// The penetration vector is "axis*overlap/|axis|^2", where "overlap" was measured in the unit of "|axis|"
+	axisSquaredLen := bestAxis.X*bestAxis.X + bestAxis.Y*bestAxis.Y
+	return true, int32(bestAxis.X * bestOverlap / axisSquaredLen), int32(bestAxis.Y * bestOverlap / axisSquaredLen)
+}
+
+func projectionRange(polygon *IntPolygon2D, axis IntVec2D) (int64, int64) {
+	var start, end int64 = math.MaxInt64, math.MinInt64
+	for _, p := range polygon.Points {
+		dot := p.X*axis.X + p.Y*axis.Y
+		if start > dot {
+			start = dot
+		}
+		if end < dot {
+			end = dot
+		}
+	}
+	return start, end
+}
+
+// The normal "(dy, -dx)" divided by the greatest common divisor of its components, such that an axis-aligned edge always yields a unit axis.
+func reducedNormOf(dx, dy int64) IntVec2D {
+	nx, ny := dy, -dx
+	g := gcd(absInt64(nx), absInt64(ny))
+	if 0 == g {
+		return IntVec2D{X: 0, Y: 0}
+	}
+	return IntVec2D{X: nx / g, Y: ny / g}
+}
+
+func gcd(a, b int64) int64 {
+	for 0 != b {
+		a, b = b, a%b
+	}
+	return a
+}
+
+func absInt64(v int64) int64 {
+	if 0 > v {
+		return -v
+	}
+	return v
+}
+
+// The floor of the square root of a non-negative "v" by Newton's method, without any floating point number.
+func intSqrt(v int64) int64 {
+	if 1 >= v {
+		return v
+	}
+	x := v
+	y := (x + 1) >> 1
+	for y < x {
+		x = y
+		y = (x + v/x) >> 1
+	}
+	return x
+}
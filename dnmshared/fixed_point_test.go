@@ -0,0 +1,54 @@
+package dnmshared
+
+import (
+	"testing"
+)
+
+func newTestIntRect(cx, cy, halfW, halfH int64) *IntPolygon2D {
+	ret := &IntPolygon2D{}
+	SetIntRectPolygon2D(ret, cx, cy, halfW, halfH)
+	return ret
+}
+
+func TestCalcPushbacksInVirtualGrid(t *testing.T) {
+	a := newTestIntRect(0, 0, 10000, 10000)
+	slope := &IntPolygon2D{
+		Points: []IntVec2D{{X: -20000, Y: -30000}, {X: 20000, Y: -30000}, {X: 20000, Y: 5000}},
+	}
+	cases := []struct {
+		name                 string
+		b                    *IntPolygon2D
+		overlapped           bool
+		pushbackX, pushbackY int32
+	}{
+		{"right", newTestIntRect(15000, 2000, 10000, 10000), true, 5000, 0},
+		{"left", newTestIntRect(-15000, 2000, 10000, 10000), true, -5000, 0},
+		{"top", newTestIntRect(1000, 17000, 10000, 10000), true, 0, 3000},
+		{"touching", newTestIntRect(20000, 0, 10000, 10000), false, 0, 0},
+		{"separated", newTestIntRect(0, -30000, 10000, 10000), false, 0, 0},
+		{"slope", slope, true, 3097, -3539},
+	}
+	for _, c := range cases {
+		overlapped, pushbackX, pushbackY := CalcPushbacksInVirtualGrid(a, c.b)
+		if c.overlapped != overlapped || c.pushbackX != pushbackX || c.pushbackY != pushbackY {
+			t.Fatalf("%v: expected (%v, %v, %v), got (%v, %v, %v)", c.name, c.overlapped, c.pushbackX, c.pushbackY, overlapped, pushbackX, pushbackY)
+		}
+		if !overlapped {
+			continue
+		}
+		// Moving "a" by the opposite of its pushback separates it from "b", at most touching
+		moved := &IntPolygon2D{Points: make([]IntVec2D, len(a.Points))}
+		for i, p := range a.Points {
+			moved.Points[i] = IntVec2D{X: p.X - int64(pushbackX), Y: p.Y - int64(pushbackY)}
+		}
+		if stillOverlapped, _, _ := CalcPushbacksInVirtualGrid(moved, c.b); stillOverlapped {
+			if _, residualX, residualY := CalcPushbacksInVirtualGrid(moved, c.b); 1 < absInt64(int64(residualX)) || 1 < absInt64(int64(residualY)) {
+				t.Fatalf("%v: still overlapped by (%v, %v) after pushed back", c.name, residualX, residualY)
+			}
+		}
+		// The other way around yields the opposite pushback
+		if reversedOverlapped, reversedX, reversedY := CalcPushbacksInVirtualGrid(c.b, a); !reversedOverlapped || -pushbackX != reversedX || -pushbackY != reversedY {
+			t.Fatalf("%v: expected the reversed pushback (%v, %v), got (%v, %v, %v)", c.name, -pushbackX, -pushbackY, reversedOverlapped, reversedX, reversedY)
+		}
+	}
+}
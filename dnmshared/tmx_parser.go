@@ -345,7 +345,12 @@ func ParseTmxLayersAndGroups(pTmxMapIns *TmxMap, gidBoundariesMap map[int]StrToP
 	toRetStrToPolygon2DListMap := make(StrToPolygon2DListMap, 0)
 
 	for _, objGroup := range pTmxMapIns.ObjectGroups {
-		switch objGroup.Name {
+		groupType := objGroup.Name
+		if strings.HasPrefix(objGroup.Name, "PlayerStartingPos") {
+			// Including the per-team ones, e.g. "PlayerStartingPosTeam1", each cached by its own name
+			groupType = "PlayerStartingPos"
+		}
+		switch groupType {
 		case "PlayerStartingPos":
 			var pTheVec2DListToCache *Vec2DList
 			_, ok := toRetStrToVec2DListMap[objGroup.Name]
@@ -364,8 +369,12 @@ func ParseTmxLayersAndGroups(pTmxMapIns *TmxMap, gidBoundariesMap map[int]StrToP
 				thePosInWorld := pTmxMapIns.continuousObjLayerOffsetToContinuousMapNodePos(theUntransformedPos)
 				pTheVec2DListToCache.Eles = append(pTheVec2DListToCache.Eles, &thePosInWorld)
 			}
-		case "Barrier":
+		case "Barrier", "OneWayPlatform":
 			// Note that in this case, the "Polygon2D.Anchor" of each "TmxOrTsxObject" is exactly overlapping with "Polygon2D.Points[0]".
+			expectedBoundaryType := "barrier"
+			if "OneWayPlatform" == objGroup.Name {
+				expectedBoundaryType = "one_way_platform"
+			}
 			var pThePolygon2DListToCache *Polygon2DList
 			_, ok := toRetStrToPolygon2DListMap[objGroup.Name]
 			if false == ok {
@@ -379,7 +388,7 @@ func ParseTmxLayersAndGroups(pTmxMapIns *TmxMap, gidBoundariesMap map[int]StrToP
 				if nil == singleObjInTmxFile.Polyline {
 					continue
 				}
-				if nil == singleObjInTmxFile.Properties.Property || "boundary_type" != singleObjInTmxFile.Properties.Property[0].Name || "barrier" != singleObjInTmxFile.Properties.Property[0].Value {
+				if nil == singleObjInTmxFile.Properties.Property || "boundary_type" != singleObjInTmxFile.Properties.Property[0].Name || expectedBoundaryType != singleObjInTmxFile.Properties.Property[0].Value {
 					continue
 				}
 
@@ -395,6 +404,21 @@ func ParseTmxLayersAndGroups(pTmxMapIns *TmxMap, gidBoundariesMap map[int]StrToP
 	return int32(pTmxMapIns.Width), int32(pTmxMapIns.Height), int32(pTmxMapIns.TileWidth), int32(pTmxMapIns.TileHeight), toRetStrToVec2DListMap, toRetStrToPolygon2DListMap, nil
 }
 
+// Returns the value of the map-level property "name", e.g. "battleMode".
+func (pTmxMapIns *TmxMap) PropertyValue(name string) (string, bool) {
+	for _, properties := range pTmxMapIns.Properties {
+		if nil == properties {
+			continue
+		}
+		for _, property := range properties.Property {
+			if name == property.Name {
+				return property.Value, true
+			}
+		}
+	}
+	return "", false
+}
+
 func (pTmxMap *TmxMap) ToXML() (string, error) {
 	ret, err := xml.Marshal(pTmxMap)
 	return string(ret[:]), err
@@ -78,6 +78,9 @@ var constants = {
     "PLAYER_NOT_READDABLE_TO_ROOM": 9013,
     "PLAYER_NOT_FOUND": 9014,
     "PLAYER_CHEATING": 9015,
+    "INPUT_ENCODING_VERSION_MISMATCH": 9018,
+    "MATCHMAKING_TIMEOUT": 9019,
+    "READY_CHECK_FAILED": 9020,
   
 
     "__comment__": "SMS",
@@ -22,6 +22,11 @@ window.MAGIC_ROOM_DOWNSYNC_FRAME_ID = {
   BATTLE_START: 0
 };
 
+/*
+[WARNING] Kept false until "applyInputFrameDownsyncDynamicsOnSingleRenderFrame" simulates damage, fireballs, guard, gravity and player pushback the same as the backend, otherwise every checksum upsynced after the first hit mismatches that of "battle.Checksum" and, when "resyncUponChecksumMismatch" is on, the player is forced to resync forever.
+*/
+window.CHECKSUM_UPSYNC_ENABLED = false;
+
 window.PlayerBattleState = {
   ADDED_PENDING_BATTLE_COLLIDER_ACK: 0,
   READDED_PENDING_BATTLE_COLLIDER_ACK: 1,
@@ -116,7 +121,7 @@ cc.Class({
 
   dumpToInputCache: function(inputFrameDownsync) {
     const self = this;
-    let minToKeepInputFrameId = self._convertToInputFrameId(self.lastAllConfirmedRenderFrameId, self.inputDelayFrames) - self.spAtkLookupFrames; // [WARNING] This could be different from "self.lastAllConfirmedInputFrameId". We'd like to keep the corresponding delayedInputFrame for "self.lastAllConfirmedRenderFrameId" such that a rollback could place "self.chaserRenderFrameId = self.lastAllConfirmedRenderFrameId" for the worst case incorrect prediction.
+    let minToKeepInputFrameId = self._convertToScheduledInputFrameId(self.lastAllConfirmedRenderFrameId) - self.spAtkLookupFrames; // [WARNING] This could be different from "self.lastAllConfirmedInputFrameId". We'd like to keep the corresponding delayedInputFrame for "self.lastAllConfirmedRenderFrameId" such that a rollback could place "self.chaserRenderFrameId = self.lastAllConfirmedRenderFrameId" for the worst case incorrect prediction.
     if (minToKeepInputFrameId > self.lastAllConfirmedInputFrameId) {
       minToKeepInputFrameId = self.lastAllConfirmedInputFrameId;
     }
@@ -139,6 +144,60 @@ cc.Class({
     return ((inputFrameId << this.inputScaleFrames) + inputDelayFrames);
   },
 
+  _convertToLastUsedRenderFrameId(inputFrameId, inputDelayFrames) {
+    return ((inputFrameId << this.inputScaleFrames) + inputDelayFrames + (1 << this.inputScaleFrames) - 1);
+  },
+
+  /*
+  The following "Scheduled" variants take "self.inputDelayFramesSwitches" renegotiated by the backend into account, and MUST match "battle.ConvertToScheduledInputFrameId" and "battle.ConvertToScheduledLastUsedRenderFrameId" of the backend.
+  */
+  _convertToScheduledInputFrameId(renderFrameId) {
+    const switches = this.inputDelayFramesSwitches || [];
+    let held = -2147483648;
+    let inputDelayFrames = this.inputDelayFrames;
+    for (let theSwitch of switches) {
+      if (renderFrameId < theSwitch.fromRenderFrameId) break;
+      held = Math.max(this._convertToInputFrameId(theSwitch.fromRenderFrameId - 1, inputDelayFrames), held);
+      inputDelayFrames = theSwitch.inputDelayFrames;
+    }
+    return Math.max(this._convertToInputFrameId(renderFrameId, inputDelayFrames), held);
+  },
+
+  _convertToScheduledLastUsedRenderFrameId(inputFrameId) {
+    const switches = this.inputDelayFramesSwitches || [];
+    const helds = [];
+    let held = -2147483648;
+    let inputDelayFrames = this.inputDelayFrames;
+    for (let theSwitch of switches) {
+      held = Math.max(this._convertToInputFrameId(theSwitch.fromRenderFrameId - 1, inputDelayFrames), held);
+      helds.push(held);
+      inputDelayFrames = theSwitch.inputDelayFrames;
+    }
+    for (let j = switches.length - 1; 0 <= j; --j) {
+      if (helds[j] > inputFrameId) continue;
+      const candidate = this._convertToLastUsedRenderFrameId(inputFrameId, switches[j].inputDelayFrames);
+      if (candidate < switches[j].fromRenderFrameId) {
+        return switches[j].fromRenderFrameId - 1;
+      }
+      return candidate;
+    }
+    return this._convertToLastUsedRenderFrameId(inputFrameId, this.inputDelayFrames);
+  },
+
+  _convertToScheduledFirstUsedRenderFrameId(inputFrameId) {
+    // If "inputFrameId" is skipped, it's the first renderFrameId using a later inputFrame
+    return this._convertToScheduledLastUsedRenderFrameId(inputFrameId - 1) + 1;
+  },
+
+  onInputDelayFramesChanged(bciFrame) {
+    const self = this;
+    if (null == bciFrame) return;
+    self.inputDelayFrames = bciFrame.inputDelayFrames;
+    self.nstDelayFrames = bciFrame.nstDelayFrames;
+    self.inputDelayFramesSwitches = bciFrame.inputDelayFramesSwitches;
+    console.log(`Input delay changed@localRenderFrameId=${self.renderFrameId}: inputDelayFrames=${self.inputDelayFrames}, inputDelayFramesSwitches=${JSON.stringify(self.inputDelayFramesSwitches)}`);
+  },
+
   shouldGenerateInputFrameUpsync(renderFrameId) {
     return ((renderFrameId & ((1 << this.inputScaleFrames) - 1)) == 0);
   },
@@ -213,6 +272,19 @@ cc.Class({
       }
     }
 
+    // The latest all-confirmed renderFrame is verified by the backend, see "Room.verifyChecksumsIfApplicable"
+    let checksum = null;
+    if (window.CHECKSUM_UPSYNC_ENABLED && self.lastAllConfirmedRenderFrameId > self.lastChecksumUpsyncRenderFrameId) {
+      const rdf = self.recentRenderCache.getByFrameId(self.lastAllConfirmedRenderFrameId);
+      if (null != rdf) {
+        checksum = {
+          renderFrameId: rdf.id,
+          checksum: self.checksumOf(rdf),
+        };
+        self.lastChecksumUpsyncRenderFrameId = rdf.id;
+      }
+    }
+
     // console.info(`inputFrameUpsyncBatch: ${JSON.stringify(inputFrameUpsyncBatch)}`);
     const reqData = window.pb.protos.WsReq.encode({
       msgId: Date.now(),
@@ -222,11 +294,81 @@ cc.Class({
       ackingFrameId: self.lastAllConfirmedRenderFrameId,
       ackingInputFrameId: self.lastAllConfirmedInputFrameId,
       inputFrameUpsyncBatch: inputFrameUpsyncBatch,
+      checksum: checksum,
     }).finish();
     window.sendSafely(reqData);
     self.lastUpsyncInputFrameId = latestLocalInputFrameId;
   },
 
+  /*
+  MUST match "battle.Checksum" of the backend, i.e. a FNV-1a 64-bit hash of the same fields in the same order, each written as 4 little-endian bytes. The hash is kept in four 16-bit limbs because a javascript number can't hold an exact 64-bit product, and returned as "{low, high}" which "protobufjs" accepts for a uint64 field.
+  */
+  checksumOf(rdf) {
+    const h = [0x2325, 0x8422, 0x9ce4, 0xcbf2]; // The offset basis 0xcbf29ce484222325, from the least significant limb
+    const writeByte = function(b) {
+      h[0] ^= b;
+      // Multiplied by the prime 0x100000001b3, i.e. "h*0x1b3 + (h << 40)" truncated to 64 bits
+      let c = h[0] * 0x1b3;
+      const r0 = (c & 0xffff);
+      c = h[1] * 0x1b3 + (c >>> 16);
+      const r1 = (c & 0xffff);
+      c = h[2] * 0x1b3 + (h[0] << 8) + (c >>> 16);
+      const r2 = (c & 0xffff);
+      c = h[3] * 0x1b3 + (h[1] << 8) + (c >>> 16);
+      const r3 = (c & 0xffff);
+      h[0] = r0;
+      h[1] = r1;
+      h[2] = r2;
+      h[3] = r3;
+    };
+    const write = function(v) {
+      v = (v | 0);
+      writeByte(v & 0xff);
+      writeByte((v >>> 8) & 0xff);
+      writeByte((v >>> 16) & 0xff);
+      writeByte((v >>> 24) & 0xff);
+    };
+
+    const players = [];
+    for (let playerId in rdf.players) {
+      players.push(rdf.players[playerId]);
+    }
+    players.sort((lhs, rhs) => (lhs.joinIndex - rhs.joinIndex));
+
+    write(rdf.id);
+    write(players.length);
+    for (let player of players) {
+      write(player.joinIndex);
+      write(player.virtualGridX);
+      write(player.virtualGridY);
+      write(player.framesToRecover);
+      write(player.hp);
+      write(player.velY);
+    }
+    const meleeBullets = (rdf.meleeBullets || []);
+    write(meleeBullets.length);
+    for (let meleeBullet of meleeBullets) {
+      write(meleeBullet.offenderJoinIndex);
+      write(meleeBullet.originatedRenderFrameId);
+    }
+    const fireballBullets = (rdf.fireballBullets || []);
+    write(fireballBullets.length);
+    for (let fireball of fireballBullets) {
+      write(fireball.offenderJoinIndex);
+      write(fireball.originatedRenderFrameId);
+      write(fireball.virtualGridX);
+      write(fireball.virtualGridY);
+      write(fireball.velX);
+      write(fireball.velY);
+    }
+
+    return {
+      low: ((h[1] << 16) | h[0]) >>> 0,
+      high: ((h[3] << 16) | h[2]) >>> 0,
+      unsigned: true,
+    };
+  },
+
   onEnable() {
     cc.log("+++++++ Map onEnable()");
   },
@@ -307,6 +449,7 @@ cc.Class({
     self.lastAllConfirmedRenderFrameId = -1;
     self.lastAllConfirmedInputFrameId = -1;
     self.lastUpsyncInputFrameId = -1;
+    self.lastChecksumUpsyncRenderFrameId = 0; // The kickoff frame is never upsynced
     self.chaserRenderFrameId = -1; // at any moment, "lastAllConfirmedRenderFrameId <= chaserRenderFrameId <= renderFrameId", but "chaserRenderFrameId" would fluctuate according to "onInputFrameDownsyncBatch"
 
     self.recentRenderCache = new RingBuffer(self.renderCacheSize);
@@ -680,7 +823,7 @@ cc.Class({
 
     if (null == firstPredictedYetIncorrectInputFrameId) return;
     const inputFrameId1 = firstPredictedYetIncorrectInputFrameId;
-    const renderFrameId1 = self._convertToFirstUsedRenderFrameId(inputFrameId1, self.inputDelayFrames); // a.k.a. "firstRenderFrameIdUsingIncorrectInputFrameId"
+    const renderFrameId1 = self._convertToScheduledFirstUsedRenderFrameId(inputFrameId1); // a.k.a. "firstRenderFrameIdUsingIncorrectInputFrameId"
     if (renderFrameId1 >= self.renderFrameId) return; // No need to rollback when "renderFrameId1 == self.renderFrameId", because the "corresponding delayedInputFrame for renderFrameId1" is NOT YET EXECUTED BY NOW, it just went through "++self.renderFrameId" in "update(dt)" and javascript-runtime is mostly single-threaded in our programmable range.
 
     if (renderFrameId1 >= self.chaserRenderFrameId) return;
@@ -976,7 +1119,7 @@ cc.Class({
       const lastAllConfirmedInputFrame = self.recentInputCache.getByFrameId(self.lastAllConfirmedInputFrameId);
       for (let i = 0; i < inputFrameDownsync.inputList.length; ++i) {
         if (i == self.selfPlayerInfo.joinIndex - 1) continue;
-        inputFrameDownsync.inputList[i] = (lastAllConfirmedInputFrame.inputList[i] & 15); // Don't predict attack input!
+        inputFrameDownsync.inputList[i] = (lastAllConfirmedInputFrame.inputList[i] & window.INPUT_PREDICTED_BITS_MASK); // Held levels are carried forward, such that no edge is generated by prediction
       }
     }
 
@@ -1109,7 +1252,7 @@ cc.Class({
 
     // Process player inputs
     if (null != delayedInputFrame) {
-      const delayedInputFrameForPrevRenderFrame = self.getCachedInputFrameDownsyncWithPrediction(self._convertToInputFrameId(currRenderFrame.id - 1, self.inputDelayFrames));
+      const delayedInputFrameForPrevRenderFrame = self.getCachedInputFrameDownsyncWithPrediction(self._convertToScheduledInputFrameId(currRenderFrame.id - 1));
       const inputList = delayedInputFrame.inputList;
       for (let j in self.playerRichInfoArr) {
         const joinIndex = parseInt(j) + 1;
@@ -1223,7 +1366,7 @@ cc.Class({
         console.warn(`Couldn't find renderFrame for i=${i} to rollback, self.renderFrameId=${self.renderFrameId}, lastAllConfirmedRenderFrameId=${self.lastAllConfirmedRenderFrameId}, lastAllConfirmedInputFrameId=${self.lastAllConfirmedInputFrameId}, might've been interruptted by onRoomDownsyncFrame`);
         return [prevLatestRdf, latestRdf];
       }
-      const j = self._convertToInputFrameId(i, self.inputDelayFrames);
+      const j = self._convertToScheduledInputFrameId(i);
       const delayedInputFrame = self.getCachedInputFrameDownsyncWithPrediction(j);
       if (null == delayedInputFrame) {
         console.warn(`Failed to get cached delayedInputFrame for i=${i}, j=${j}, self.renderFrameId=${self.renderFrameId}, lastAllConfirmedRenderFrameId=${self.lastAllConfirmedRenderFrameId}, lastAllConfirmedInputFrameId=${self.lastAllConfirmedInputFrameId}`);
@@ -99,6 +99,10 @@ cc.Class({
     this.cachedBtnLeftLevel = 0;
     this.cachedBtnRightLevel = 0;
     this.cachedBtnALevel = 0;
+    this.cachedGuardLevel = 0;
+    this.cachedBtnBLevel = 0;
+    this.cachedBtnCLevel = 0;
+    this.cachedBtnDLevel = 0;
 
     this.canvasNode = this.mapNode.parent;
     this.mainCameraNode = this.canvasNode.getChildByName("Main Camera"); // Cannot drag and assign the `mainCameraNode` from CocosCreator EDITOR directly, otherwise it'll cause an infinite loading time, till v2.1.0.
@@ -168,6 +172,18 @@ cc.Class({
         case cc.macro.KEY.h:
           self.cachedBtnALevel = 1;
           break;
+        case cc.macro.KEY.j:
+          self.cachedGuardLevel = 1;
+          break;
+        case cc.macro.KEY.k:
+          self.cachedBtnBLevel = 1;
+          break;
+        case cc.macro.KEY.l:
+          self.cachedBtnCLevel = 1;
+          break;
+        case cc.macro.KEY.space:
+          self.cachedBtnDLevel = 1;
+          break;
         default:
           break;
       }
@@ -190,6 +206,18 @@ cc.Class({
         case cc.macro.KEY.h:
           self.cachedBtnALevel = 0;
           break;
+        case cc.macro.KEY.j:
+          self.cachedGuardLevel = 0;
+          break;
+        case cc.macro.KEY.k:
+          self.cachedBtnBLevel = 0;
+          break;
+        case cc.macro.KEY.l:
+          self.cachedBtnCLevel = 0;
+          break;
+        case cc.macro.KEY.space:
+          self.cachedBtnDLevel = 0;
+          break;
         default:
           break;
       }
@@ -399,21 +427,33 @@ cc.Class({
 
   getEncodedInput() {
     const discretizedDir = this.discretizeDirection(this.stickhead.x, this.stickhead.y, this.joyStickEps).encodedIdx; // There're only 9 dirs, thus using only the lower 4-bits
-    const btnALevel = (this.cachedBtnALevel << 4);
-    return (btnALevel + discretizedDir);
+    // MUST match the layout of "battle.INPUT_ENCODING_VERSION" of the backend
+    return (
+      discretizedDir
+      + (this.cachedBtnALevel << window.INPUT_BTN_A_SHIFT)
+      + (this.cachedGuardLevel << window.INPUT_GUARD_SHIFT)
+      + (this.cachedBtnBLevel << window.INPUT_BTN_B_SHIFT)
+      + (this.cachedBtnCLevel << window.INPUT_BTN_C_SHIFT)
+      + (this.cachedBtnDLevel << window.INPUT_BTN_D_SHIFT)
+    );
   },
 
   decodeInput(encodedInput) {
-    const encodedDirection = (encodedInput & 15);
-    const mappedDirection = window.DIRECTION_DECODER[encodedDirection];
+    const encodedDirection = (encodedInput & window.INPUT_DIRECTION_MASK);
+    let mappedDirection = window.DIRECTION_DECODER[encodedDirection];
     if (null == mappedDirection) {
+      // Same as "battle.DecodeInput" of the backend
       console.error("Unexpected encodedDirection = ", encodedDirection);
+      mappedDirection = window.DIRECTION_DECODER[0];
     }
-    const btnALevel = ((encodedInput >> 4) & 1);
     return window.pb.protos.InputFrameDecoded.create({
       dx: mappedDirection[0],
       dy: mappedDirection[1],
-      btnALevel: btnALevel,
+      btnALevel: ((encodedInput >> window.INPUT_BTN_A_SHIFT) & 1),
+      guardLevel: ((encodedInput >> window.INPUT_GUARD_SHIFT) & 1),
+      btnBLevel: ((encodedInput >> window.INPUT_BTN_B_SHIFT) & 1),
+      btnCLevel: ((encodedInput >> window.INPUT_BTN_C_SHIFT) & 1),
+      btnDLevel: ((encodedInput >> window.INPUT_BTN_D_SHIFT) & 1),
     });
   },
 });
@@ -12,6 +12,17 @@ window.DOWNSYNC_MSG_ACT_HB_REQ = 1;
 window.DOWNSYNC_MSG_ACT_INPUT_BATCH = 2;
 window.DOWNSYNC_MSG_ACT_BATTLE_STOPPED = 3;
 window.DOWNSYNC_MSG_ACT_FORCED_RESYNC = 4;
+window.DOWNSYNC_MSG_ACT_HB_ECHO = 5;
+window.DOWNSYNC_MSG_ACT_INPUT_DELAY_SWITCHED = 6;
+
+window.INPUT_ENCODING_VERSION = 2; // MUST match "battle.INPUT_ENCODING_VERSION" of the backend, see "TouchEventsManager.getEncodedInput"
+window.INPUT_DIRECTION_MASK = 15;
+window.INPUT_BTN_A_SHIFT = 4;
+window.INPUT_GUARD_SHIFT = 5;
+window.INPUT_BTN_B_SHIFT = 6;
+window.INPUT_BTN_C_SHIFT = 7;
+window.INPUT_BTN_D_SHIFT = 8;
+window.INPUT_PREDICTED_BITS_MASK = (window.INPUT_DIRECTION_MASK | (1 << window.INPUT_GUARD_SHIFT) | (1 << window.INPUT_BTN_A_SHIFT) | (1 << window.INPUT_BTN_B_SHIFT) | (1 << window.INPUT_BTN_C_SHIFT) | (1 << window.INPUT_BTN_D_SHIFT)); // MUST match "battle.INPUT_PREDICTED_BITS_MASK" of the backend
 
 
 window.sendSafely = function(msgStr) {
@@ -66,6 +77,45 @@ window.handleHbRequirements = function(resp) {
   if (window.handleBattleColliderInfo) {
     window.handleBattleColliderInfo(resp.bciFrame);
   }
+
+  if (null == window.hbIntervalHandler && 0 < resp.bciFrame.intervalToPing) {
+    window.hbIntervalHandler = setInterval(window.sendHeartbeat, resp.bciFrame.intervalToPing);
+  }
+};
+
+/*
+The backend samples the round-trip time by the echoed fields of each "HeartbeatUpsync" to choose "inputDelayFrames" when "adaptiveInputDelayEnabled" is on, otherwise the heartbeats are just dropped.
+*/
+window.echoedServerTimestamp = 0; // By the latest "DOWNSYNC_MSG_ACT_HB_ECHO"
+window.echoReceivedClientTimestamp = 0;
+window.hbIntervalHandler = null;
+
+window.sendHeartbeat = function() {
+  const reqData = window.pb.protos.WsReq.encode({
+    msgId: Date.now(),
+    act: window.UPSYNC_MSG_ACT_HB_PING,
+    hb: {
+      clientTimestamp: Date.now(),
+      echoedServerTimestamp: window.echoedServerTimestamp,
+      echoReceivedClientTimestamp: window.echoReceivedClientTimestamp,
+    },
+  }).finish();
+  window.sendSafely(reqData);
+};
+
+window.handleHbEcho = function(resp) {
+  if (null == resp.hb) return;
+  window.echoedServerTimestamp = resp.hb.serverTimestamp;
+  window.echoReceivedClientTimestamp = Date.now();
+};
+
+window.stopHeartbeat = function() {
+  if (null != window.hbIntervalHandler) {
+    clearInterval(window.hbIntervalHandler);
+    window.hbIntervalHandler = null;
+  }
+  window.echoedServerTimestamp = 0;
+  window.echoReceivedClientTimestamp = 0;
 };
 
 function _uint8ToBase64(uint8Arr) {
@@ -116,7 +166,7 @@ window.initPersistentSessionClient = function(onopenCb, expectedRoomId) {
   const selfPlayer = null == selfPlayerStr ? null : JSON.parse(selfPlayerStr);
   const intAuthToken = null == selfPlayer ? "" : selfPlayer.intAuthToken;
 
-  let urlToConnect = backendAddress.PROTOCOL.replace('http', 'ws') + '://' + backendAddress.HOST + ":" + backendAddress.PORT + backendAddress.WS_PATH_PREFIX + "?intAuthToken=" + intAuthToken;
+  let urlToConnect = backendAddress.PROTOCOL.replace('http', 'ws') + '://' + backendAddress.HOST + ":" + backendAddress.PORT + backendAddress.WS_PATH_PREFIX + "?intAuthToken=" + intAuthToken + "&inputEncodingVersion=" + window.INPUT_ENCODING_VERSION;
 
   if (null != expectedRoomId) {
     console.log("initPersistentSessionClient with expectedRoomId == " + expectedRoomId);
@@ -162,8 +212,18 @@ window.initPersistentSessionClient = function(onopenCb, expectedRoomId) {
           mapIns.onBattleReadyToStart(resp.rdf);
           break;
         case window.DOWNSYNC_MSG_ACT_BATTLE_START:
+          if (null != resp.bciFrame) {
+            // The "inputDelayFrames" chosen at battle start by "adaptiveInputDelayEnabled" differs from the one in "DOWNSYNC_MSG_ACT_HB_REQ"
+            mapIns.onInputDelayFramesChanged(resp.bciFrame);
+          }
           mapIns.onRoomDownsyncFrame(resp.rdf);
           break;
+        case window.DOWNSYNC_MSG_ACT_HB_ECHO:
+          window.handleHbEcho(resp);
+          break;
+        case window.DOWNSYNC_MSG_ACT_INPUT_DELAY_SWITCHED:
+          mapIns.onInputDelayFramesChanged(resp.bciFrame);
+          break;
         case window.DOWNSYNC_MSG_ACT_BATTLE_STOPPED:
           mapIns.onBattleStopped();
           break;
@@ -201,6 +261,7 @@ ${JSON.stringify(resp, null, 2)}`);
   clientSession.onclose = function(evt) {
     // [WARNING] The callback "onclose" might be called AFTER the webpage is refreshed with "1001 == evt.code".
     console.warn("The WS clientSession is closed: ", evt, clientSession);
+    window.stopHeartbeat();
     if (false == evt.wasClean) {
       /*
       Chrome doesn't allow the use of "CustomCloseCode"s (yet) and will callback with a "WebsocketStdCloseCode 1006" and "false == evt.wasClean" here. See https://tools.ietf.org/html/rfc6455#section-7.4 for more information.
@@ -218,6 +279,7 @@ ${JSON.stringify(resp, null, 2)}`);
         case constants.RET_CODE.MYSQL_ERROR:
         case constants.RET_CODE.PLAYER_NOT_FOUND:
         case constants.RET_CODE.PLAYER_CHEATING:
+        case constants.RET_CODE.INPUT_ENCODING_VERSION_MISMATCH:
         case 1006: // Peer(i.e. the backend) gone unexpectedly 
           if (window.handleClientSessionError) {
             window.handleClientSessionError();